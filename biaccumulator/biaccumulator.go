@@ -1,43 +1,113 @@
 package biaccumulator
 
 import (
+	"context"
 	"errors"
+	"io"
 	"math/big"
 
-	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/poly"
 	"github.com/zhtluo/libpolycrypto/polycommit"
 )
 
 type PublicInfo = polycommit.Pk
 
-func Expand(cred []big.Int) (poly []big.Int) {
-	poly = make([]big.Int, len(cred)+1)
-	poly[0].SetInt64(1)
-	// Polynomial expansion. Change to FFT if necessary.
-	for i := range cred {
-		for j := i + 1; j >= 1; j-- {
-			poly[j].Sub(&poly[j-1], new(big.Int).Mul(&poly[j], &cred[i]))
-		}
-		poly[0].Neg(poly[0].Mul(&poly[0], &cred[i]))
+// G1PublicInfo accumulates in G1, which gives smaller accumulators
+// and a faster setup.
+type G1PublicInfo = polycommit.G1Pk
+
+// Expand the product of (x - cred[i]) over the curve c into its coefficients.
+func Expand(c curve.Curve, cred []curve.Scalar) []curve.Scalar {
+	return poly.Vanishing(c, cred)
+}
+
+// Expand the product of (x - cred[i]) into its coefficients modulo the order of c.
+func ExpandBig(c curve.Curve, cred []big.Int) (poly []big.Int) {
+	return curve.ToBigInts(Expand(c, curve.FromBigInts(c, cred)))
+}
+
+func Evaluate(pi *PublicInfo, poly []curve.Scalar) (curve.Point, error) {
+	return pi.Commit(poly)
+}
+
+// Evaluate the accumulator as Evaluate does,
+// splitting the work among workers goroutines and stopping once ctx is done.
+func EvaluateContext(ctx context.Context, pi *PublicInfo, poly []curve.Scalar, workers int) (curve.Point, error) {
+	return pi.CommitContext(ctx, poly, workers)
+}
+
+func CreateWitness(pi *PublicInfo, poly []curve.Scalar, d curve.Scalar) (curve.Point, error) {
+	res, g1, err := pi.CreateWitness(poly, d)
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsZero() {
+		return nil, errors.New("Polynomial does not contain credential.")
 	}
-	return
+	return g1, nil
 }
 
-func Evaluate(pi *PublicInfo, poly []big.Int) (*bn256.G2, error) {
+// Create the witness of the credential d as CreateWitness does,
+// splitting the work among workers goroutines and stopping once ctx is done.
+func CreateWitnessContext(ctx context.Context, pi *PublicInfo, poly []curve.Scalar, d curve.Scalar, workers int) (curve.Point, error) {
+	res, g1, err := pi.CreateWitnessContext(ctx, poly, d, workers)
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsZero() {
+		return nil, errors.New("Polynomial does not contain credential.")
+	}
+	return g1, nil
+}
+
+func CreateWitnessBig(pi *PublicInfo, poly []big.Int, d *big.Int) (curve.Point, error) {
+	return CreateWitness(pi, curve.FromBigInts(pi.Curve, poly), pi.Curve.NewScalar().SetBigInt(d))
+}
+
+func EvaluateG1(pi *G1PublicInfo, poly []curve.Scalar) (curve.Point, error) {
 	return pi.Commit(poly)
 }
 
-func CreateWitness(pi *PublicInfo, poly []big.Int, d *big.Int) (*bn256.G1, error) {
+func CreateWitnessG1(pi *G1PublicInfo, poly []curve.Scalar, d curve.Scalar) (curve.Point, error) {
 	res, g1, err := pi.CreateWitness(poly, d)
 	if err != nil {
 		return nil, err
 	}
-	if res.Cmp(big.NewInt(0)) != 0 {
+	if !res.IsZero() {
 		return nil, errors.New("Polynomial does not contain credential.")
 	}
 	return g1, nil
 }
 
-func Verify(pi *PublicInfo, g2 *bn256.G2, g1 *bn256.G1, d *big.Int) bool {
-	return pi.VerifyEval(g2, d, big.NewInt(0), g1)
+// Prove the knowledge of the polynomial poly behind the accumulator g2
+// without revealing the credentials, with the randomness in reader r.
+func ProveAccumulator(r io.Reader, pi *PublicInfo, poly []curve.Scalar, g2 curve.Point) (*polycommit.OpeningProof, error) {
+	return pi.ProveOpening(r, poly, g2)
+}
+
+func VerifyAccumulator(pi *PublicInfo, g2 curve.Point, pr *polycommit.OpeningProof) bool {
+	return pi.VerifyOpening(g2, pr)
+}
+
+func ProveAccumulatorG1(r io.Reader, pi *G1PublicInfo, poly []curve.Scalar, acc curve.Point) (*polycommit.G1OpeningProof, error) {
+	return pi.ProveOpening(r, poly, acc)
+}
+
+func VerifyAccumulatorG1(pi *G1PublicInfo, acc curve.Point, pr *polycommit.G1OpeningProof) bool {
+	return pi.VerifyOpening(acc, pr)
+}
+
+type VerifierInfo = polycommit.VerifierKey
+
+func Verify(vi *VerifierInfo, g2 curve.Point, g1 curve.Point, d curve.Scalar) bool {
+	return vi.VerifyEval(g2, d, vi.Curve.NewScalar(), g1)
+}
+
+func VerifyBig(vi *VerifierInfo, g2 curve.Point, g1 curve.Point, d *big.Int) bool {
+	return Verify(vi, g2, g1, vi.Curve.NewScalar().SetBigInt(d))
+}
+
+func VerifyG1(vi *VerifierInfo, acc curve.Point, g1 curve.Point, d curve.Scalar) bool {
+	return vi.VerifyEvalG1(acc, d, vi.Curve.NewScalar(), g1)
 }
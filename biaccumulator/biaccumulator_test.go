@@ -4,43 +4,209 @@ import (
 	"crypto/rand"
 	"testing"
 
+	"context"
 	"math/big"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/polycommit"
 )
 
+var curves = []curve.Curve{curve.BN256, curve.BN256Google, curve.BLS12381}
+
 func TestExpand(t *testing.T) {
-	cred := make([]big.Int, 2)
-	cred[0].SetInt64(2)
-	cred[1].SetInt64(3)
-	poly := Expand(cred)
-	// x^2 - 5x + 6
-	if len(poly) != 3 || poly[0].String() != "6" || poly[1].String() != "-5" || poly[2].String() != "1" {
-		t.Error("Wrong expansion, got:", poly)
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			cred := []curve.Scalar{curve.NewScalar(c, 2), curve.NewScalar(c, 3)}
+			poly := Expand(c, cred)
+			// x^2 - 5x + 6
+			expected := []curve.Scalar{curve.NewScalar(c, 6), curve.NewScalar(c, -5), curve.NewScalar(c, 1)}
+			if len(poly) != 3 || !poly[0].Equal(expected[0]) || !poly[1].Equal(expected[1]) || !poly[2].Equal(expected[2]) {
+				t.Error("Wrong expansion, got:", poly)
+			}
+			// Large enough to go through the number-theoretic transform.
+			cred = curve.NewScalars(c, 300)
+			for i := range cred {
+				cred[i].SetInt64(int64(i + 1))
+			}
+			poly = Expand(c, cred)
+			if len(poly) != len(cred)+1 {
+				t.Fatal("Wrong expansion length, got:", len(poly))
+			}
+			for i := range cred {
+				if !polycommit.Evaluate(poly, cred[i]).IsZero() {
+					t.Error("Expansion does not vanish at credential", cred[i].String())
+				}
+			}
+			if polycommit.Evaluate(poly, c.NewScalar()).IsZero() || !poly[len(cred)].IsOne() {
+				t.Error("Wrong expansion.")
+			}
+		})
 	}
 }
 
-func TestWitness(t *testing.T) {
-	cred := make([]big.Int, 2)
-	cred[0].SetInt64(2)
-	cred[1].SetInt64(3)
-	poly := Expand(cred)
-	pi := new(PublicInfo)
-	err := pi.Setup(rand.Reader, 3)
-	if err != nil {
-		t.Error(err)
+func TestExpandBig(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			cred := []big.Int{*big.NewInt(2), *big.NewInt(3)}
+			poly := ExpandBig(c, cred)
+			// x^2 - 5x + 6
+			minus5 := new(big.Int).Sub(c.Order(), big.NewInt(5))
+			if len(poly) != 3 || poly[0].String() != "6" || poly[1].Cmp(minus5) != 0 || poly[2].String() != "1" {
+				t.Error("Wrong expansion, got:", poly)
+			}
+		})
 	}
-	g2, err := Evaluate(pi, poly)
-	if err != nil {
-		t.Error(err)
+}
+
+func TestWitness(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			cred := []curve.Scalar{curve.NewScalar(c, 2), curve.NewScalar(c, 3)}
+			poly := Expand(c, cred)
+			pi := new(PublicInfo)
+			err := pi.Setup(c, rand.Reader, 3)
+			if err != nil {
+				t.Error(err)
+			}
+			g2, err := Evaluate(pi, poly)
+			if err != nil {
+				t.Error(err)
+			}
+			g1, err := CreateWitness(pi, poly, cred[0])
+			if err != nil {
+				t.Error(err)
+			}
+			vi, err := pi.VerifierKey()
+			if err != nil {
+				t.Error(err)
+			}
+			if Verify(vi, g2, g1, cred[0]) == false {
+				t.Error("Verify failed.")
+			}
+			if VerifyBig(vi, g2, g1, big.NewInt(2)) == false {
+				t.Error("VerifyBig failed.")
+			}
+			g1, err = CreateWitness(pi, poly, curve.NewScalar(c, 5))
+			if err == nil {
+				t.Error("Invalid credential accepted")
+			}
+		})
 	}
-	g1, err := CreateWitness(pi, poly, &cred[0])
-	if err != nil {
-		t.Error(err)
+}
+
+func TestEvaluateContext(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			cred := curve.NewScalars(c, 32)
+			for i := range cred {
+				cred[i].SetRandom(rand.Reader)
+			}
+			poly := Expand(c, cred)
+			pi := new(PublicInfo)
+			if err := pi.Setup(c, rand.Reader, len(poly)); err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			g2, _ := Evaluate(pi, poly)
+			g2c, err := EvaluateContext(ctx, pi, poly, 0)
+			if err != nil || !g2.Equal(g2c) {
+				t.Error("EvaluateContext failed. Expected the accumulator of Evaluate.")
+			}
+			g1, _ := CreateWitness(pi, poly, cred[5])
+			g1c, err := CreateWitnessContext(ctx, pi, poly, cred[5], 2)
+			if err != nil || !g1.Equal(g1c) {
+				t.Error("CreateWitnessContext failed. Expected the witness of CreateWitness.")
+			}
+			if _, err = CreateWitnessContext(ctx, pi, poly, c.NewScalar(), 2); err == nil {
+				t.Error("CreateWitnessContext accepted a missing credential.")
+			}
+			canceled, cancel := context.WithCancel(ctx)
+			cancel()
+			if _, err = EvaluateContext(canceled, pi, poly, 0); err != context.Canceled {
+				t.Error("EvaluateContext failed. Expected: context.Canceled.")
+			}
+		})
 	}
-	if Verify(pi, g2, g1, &cred[0]) == false {
-		t.Error("Verify failed.")
+}
+
+func TestWitnessG1(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			cred := []curve.Scalar{curve.NewScalar(c, 2), curve.NewScalar(c, 3)}
+			poly := Expand(c, cred)
+			pi := new(G1PublicInfo)
+			err := pi.Setup(c, rand.Reader, 3)
+			if err != nil {
+				t.Error(err)
+			}
+			acc, err := EvaluateG1(pi, poly)
+			if err != nil {
+				t.Error(err)
+			}
+			g1, err := CreateWitnessG1(pi, poly, cred[1])
+			if err != nil {
+				t.Error(err)
+			}
+			vi, err := pi.VerifierKey()
+			if err != nil {
+				t.Error(err)
+			}
+			if VerifyG1(vi, acc, g1, cred[1]) == false {
+				t.Error("VerifyG1 failed.")
+			}
+			if VerifyG1(vi, acc, g1, cred[0]) == true {
+				t.Error("VerifyG1 accepted the wrong credential.")
+			}
+			if _, err = CreateWitnessG1(pi, poly, curve.NewScalar(c, 5)); err == nil {
+				t.Error("Invalid credential accepted")
+			}
+		})
 	}
-	g1, err = CreateWitness(pi, poly, big.NewInt(5))
-	if err == nil {
-		t.Error("Invalid credential accepted")
+}
+
+func TestProveAccumulator(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			cred := []curve.Scalar{curve.NewScalar(c, 2), curve.NewScalar(c, 3)}
+			poly := Expand(c, cred)
+			pi := new(PublicInfo)
+			err := pi.Setup(c, rand.Reader, 3)
+			if err != nil {
+				t.Error(err)
+			}
+			g2, err := Evaluate(pi, poly)
+			if err != nil {
+				t.Error(err)
+			}
+			pr, err := ProveAccumulator(rand.Reader, pi, poly, g2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if VerifyAccumulator(pi, g2, pr) == false {
+				t.Error("VerifyAccumulator failed.")
+			}
+			other, err := Evaluate(pi, Expand(c, cred[:1]))
+			if err != nil {
+				t.Error(err)
+			}
+			if VerifyAccumulator(pi, other, pr) == true {
+				t.Error("VerifyAccumulator accepted another accumulator.")
+			}
+			g1pi := new(G1PublicInfo)
+			if err = g1pi.Setup(c, rand.Reader, 3); err != nil {
+				t.Error(err)
+			}
+			acc, err := EvaluateG1(g1pi, poly)
+			if err != nil {
+				t.Error(err)
+			}
+			g1pr, err := ProveAccumulatorG1(rand.Reader, g1pi, poly, acc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if VerifyAccumulatorG1(g1pi, acc, g1pr) == false {
+				t.Error("VerifyAccumulatorG1 failed.")
+			}
+		})
 	}
 }
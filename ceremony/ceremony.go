@@ -0,0 +1,216 @@
+// Package ceremony implements a multi-party powers-of-tau ceremony for
+// polycommit.Pk, following the update proofs in
+// S. Bowe, A. Gabizon, I. Miers.
+// Scalable Multi-party Computation for zk-SNARK Parameters in the Random Beacon Model.
+// Every participant raises the powers of the current key by a secret factor,
+// so the final trapdoor stays unknown as long as one participant is honest.
+
+package ceremony
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/point"
+	"github.com/zhtluo/libpolycrypto/polycommit"
+	pb "github.com/zhtluo/libpolycrypto/proto"
+	"github.com/zhtluo/libpolycrypto/transcript"
+	"google.golang.org/protobuf/proto"
+)
+
+// Struct Contribution implements the public record of one update.
+// G1P1 is the first power g^alpha of the updated key and G2Tau = g^tau
+// commits to the secret factor tau. PokR and PokS are a Schnorr proof of
+// knowledge of tau bound to the previous and the updated key.
+type Contribution struct {
+	G1P1  curve.Point
+	G2Tau curve.Point
+	PokR  curve.Point
+	PokS  curve.Scalar
+}
+
+// Create the initial key of the ceremony on the curve c with degree t,
+// i.e. every power equal to the generator, which corresponds to alpha = 1.
+func Start(c curve.Curve, t int) (*polycommit.Pk, error) {
+	if t < 2 {
+		return nil, errors.New("Ceremony needs a degree of at least 2")
+	}
+	pk := new(polycommit.Pk)
+	pk.Curve = c
+	pk.G1P = make([]curve.Point, t)
+	pk.G2P = make([]curve.Point, t)
+	for i := 0; i < t; i++ {
+		pk.G1P[i] = c.NewG1().ScalarBaseMult(big.NewInt(1))
+		pk.G2P[i] = c.NewG2().ScalarBaseMult(big.NewInt(1))
+	}
+	return pk, nil
+}
+
+// Compute the Fiat-Shamir challenge of the proof of knowledge.
+func challenge(c curve.Curve, prev curve.Point, ct *Contribution) curve.Scalar {
+	t := transcript.New("ceremony-pok")
+	t.AppendPoint("prev", prev)
+	t.AppendPoint("g1p1", ct.G1P1)
+	t.AppendPoint("g2tau", ct.G2Tau)
+	t.AppendPoint("pokr", ct.PokR)
+	return c.NewScalar().SetBytesReduce(t.ChallengeScalarBytes("challenge"))
+}
+
+// Rerandomize the key prev with a secret factor generated in reader r,
+// returning the updated key and the contribution proving the update.
+// The secret factor is never returned and is cleared before returning.
+func Contribute(r io.Reader, prev *polycommit.Pk) (*polycommit.Pk, *Contribution, error) {
+	if prev.Degree() < 2 || len(prev.G2P) != prev.Degree() || prev.Curve == nil {
+		return nil, nil, errors.New("Public key is malformed")
+	}
+	c := prev.Curve
+	tau, k := c.NewScalar(), c.NewScalar()
+	// Overwrite the secrets on every path out.
+	defer tau.SetZero()
+	defer k.SetZero()
+	for tau.IsZero() {
+		if _, err := tau.SetRandom(r); err != nil {
+			return nil, nil, err
+		}
+	}
+	if _, err := k.SetRandom(r); err != nil {
+		return nil, nil, err
+	}
+	next := new(polycommit.Pk)
+	next.Curve = c
+	next.G1P = make([]curve.Point, prev.Degree())
+	next.G2P = make([]curve.Point, prev.Degree())
+	next.G1P[0] = c.NewG1().Set(prev.G1P[0])
+	next.G2P[0] = c.NewG2().Set(prev.G2P[0])
+	tm := c.NewScalar().SetOne()
+	for i := 1; i < prev.Degree(); i++ {
+		tm.Mul(tm, tau)
+		e := tm.BigInt()
+		next.G1P[i] = c.NewG1().ScalarMult(prev.G1P[i], e)
+		next.G2P[i] = c.NewG2().ScalarMult(prev.G2P[i], e)
+	}
+	tm.SetZero()
+	ct := new(Contribution)
+	ct.G1P1 = c.NewG1().Set(next.G1P[1])
+	ct.G2Tau = c.NewG2().ScalarBaseMult(tau.BigInt())
+	ct.PokR = c.NewG2().ScalarBaseMult(k.BigInt())
+	// s = k + h * tau
+	ct.PokS = c.NewScalar().Mul(challenge(c, prev.G1P[1], ct), tau)
+	ct.PokS.Add(ct.PokS, k)
+	return next, ct, nil
+}
+
+// Verify that the contribution ct updates a key on the curve c
+// whose first power is prev.
+func verifyLink(c curve.Curve, prev curve.Point, ct *Contribution) error {
+	if ct.PokS == nil || ct.PokS.Curve() != c {
+		return errors.New("Contribution is on a different curve")
+	}
+	if ct.G1P1.IsInfinity() {
+		return errors.New("Contribution has a zero factor")
+	}
+	// g^s = R * (g^tau)^h
+	lhs := c.NewG2().ScalarBaseMult(ct.PokS.BigInt())
+	rhs := c.NewG2().ScalarMult(ct.G2Tau, challenge(c, prev, ct).BigInt())
+	rhs.Add(rhs, ct.PokR)
+	if !lhs.Equal(rhs) {
+		return errors.New("Proof of knowledge verification failed")
+	}
+	// e(G1P1, g) = e(prev, g^tau)
+	g2 := c.NewG2().ScalarBaseMult(big.NewInt(1))
+	if !c.PairingCheck([]curve.Point{ct.G1P1, c.NewG1().Neg(prev)}, []curve.Point{g2, ct.G2Tau}) {
+		return errors.New("Update proof verification failed")
+	}
+	return nil
+}
+
+// Verify a single contribution ct that updates the key prev to next.
+func VerifyContribution(prev *polycommit.Pk, next *polycommit.Pk, ct *Contribution) error {
+	if prev.Degree() < 2 || prev.Degree() != next.Degree() {
+		return errors.New("Public keys have different degrees")
+	}
+	if prev.Curve == nil || prev.Curve != next.Curve {
+		return errors.New("Public keys are on different curves")
+	}
+	err := verifyLink(prev.Curve, prev.G1P[1], ct)
+	if err != nil {
+		return err
+	}
+	if !next.G1P[1].Equal(ct.G1P1) {
+		return errors.New("Contribution does not match the public key")
+	}
+	return next.CheckPowers()
+}
+
+// Verify the whole ceremony that starts from the key start,
+// goes through the contributions cs in order and ends in the key final.
+// The intermediate keys are not needed since every contribution carries
+// the first power it produces.
+func VerifyChain(start *polycommit.Pk, cs []Contribution, final *polycommit.Pk) error {
+	if start.Degree() < 2 || start.Degree() != final.Degree() {
+		return errors.New("Public keys have different degrees")
+	}
+	if start.Curve == nil || start.Curve != final.Curve {
+		return errors.New("Public keys are on different curves")
+	}
+	err := start.CheckPowers()
+	if err != nil {
+		return err
+	}
+	prev := start.G1P[1]
+	for i := range cs {
+		err = verifyLink(start.Curve, prev, &cs[i])
+		if err != nil {
+			return err
+		}
+		prev = cs[i].G1P1
+	}
+	if !final.G1P[1].Equal(prev) {
+		return errors.New("Final public key does not match the contributions")
+	}
+	return final.CheckPowers()
+}
+
+// Serialize the contribution.
+func (ct *Contribution) Marshal() ([]byte, error) {
+	return ct.MarshalFormat(point.Uncompressed)
+}
+
+// Serialize the contribution with the points encoded in the format f.
+func (ct *Contribution) MarshalFormat(f point.Format) ([]byte, error) {
+	var sC pb.Contribution
+	sC.G1P1 = curve.Encode(ct.G1P1, f)
+	sC.G2Tau = curve.Encode(ct.G2Tau, f)
+	sC.PokR = curve.Encode(ct.PokR, f)
+	sC.PokS = ct.PokS.Marshal()
+	sC.Format = uint32(f)
+	sC.Curve = ct.PokS.Curve().Name()
+	return proto.Marshal(&sC)
+}
+
+// Deserialize the contribution.
+func (ct *Contribution) Unmarshal(b []byte) error {
+	var sC pb.Contribution
+	err := proto.Unmarshal(b, &sC)
+	if err != nil {
+		return err
+	}
+	c, err := curve.ByName(sC.Curve)
+	if err != nil {
+		return err
+	}
+	f := point.Format(sC.Format)
+	ct.G1P1, ct.G2Tau, ct.PokR, ct.PokS = c.NewG1(), c.NewG2(), c.NewG2(), c.NewScalar()
+	if err = curve.Decode(ct.G1P1, sC.G1P1, f); err != nil {
+		return err
+	}
+	if err = curve.Decode(ct.G2Tau, sC.G2Tau, f); err != nil {
+		return err
+	}
+	if err = curve.Decode(ct.PokR, sC.PokR, f); err != nil {
+		return err
+	}
+	return ct.PokS.Unmarshal(sC.PokS)
+}
@@ -0,0 +1,150 @@
+package ceremony
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/point"
+	"github.com/zhtluo/libpolycrypto/polycommit"
+)
+
+const (
+	deg          = 16
+	participants = 3
+)
+
+var curves = []curve.Curve{curve.BN256, curve.BN256Google, curve.BLS12381}
+
+// Run a ceremony and return the keys after every contribution.
+func runCeremony(t *testing.T, c curve.Curve) ([]*polycommit.Pk, []Contribution) {
+	start, err := Start(c, deg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pks := []*polycommit.Pk{start}
+	cs := make([]Contribution, participants)
+	for i := 0; i < participants; i++ {
+		next, ct, err := Contribute(rand.Reader, pks[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		pks = append(pks, next)
+		cs[i] = *ct
+	}
+	return pks, cs
+}
+
+func TestContribute(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			pks, cs := runCeremony(t, c)
+			for i := range cs {
+				if err := VerifyContribution(pks[i], pks[i+1], &cs[i]); err != nil {
+					t.Error(err)
+				}
+			}
+			// A contribution only verifies against the key it updates.
+			if VerifyContribution(pks[0], pks[2], &cs[1]) == nil {
+				t.Error("VerifyContribution accepted a contribution on the wrong key.")
+			}
+			// The final key works for commitments.
+			pk := pks[participants]
+			poly := curve.NewScalars(c, deg)
+			for i := range poly {
+				poly[i].SetRandom(rand.Reader)
+			}
+			g2, err := pk.Commit(poly)
+			if err != nil {
+				t.Error(err)
+			}
+			i := curve.NewScalar(c, 7)
+			res, g1, err := pk.CreateWitness(poly, i)
+			if err != nil {
+				t.Error(err)
+			}
+			vk, err := pk.VerifierKey()
+			if err != nil {
+				t.Error(err)
+			}
+			if vk.VerifyEval(g2, i, res, g1) != true {
+				t.Error("VerifyEval failed, expected: true.")
+			}
+		})
+	}
+}
+
+func TestVerifyChain(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			pks, cs := runCeremony(t, c)
+			if err := VerifyChain(pks[0], cs, pks[participants]); err != nil {
+				t.Error(err)
+			}
+			if VerifyChain(pks[0], cs[:participants-1], pks[participants]) == nil {
+				t.Error("VerifyChain accepted a missing contribution.")
+			}
+			if VerifyChain(pks[0], []Contribution{cs[1], cs[0], cs[2]}, pks[participants]) == nil {
+				t.Error("VerifyChain accepted reordered contributions.")
+			}
+			// Replaying a contribution fails the proof of knowledge.
+			replay := append([]Contribution{}, cs...)
+			replay = append(replay, cs[participants-1])
+			if VerifyChain(pks[0], replay, pks[participants]) == nil {
+				t.Error("VerifyChain accepted a replayed contribution.")
+			}
+			forged := append([]Contribution{}, cs...)
+			forged[1].PokS, _ = c.NewScalar().SetRandom(rand.Reader)
+			if VerifyChain(pks[0], forged, pks[participants]) == nil {
+				t.Error("VerifyChain accepted a forged proof of knowledge.")
+			}
+			// A final key with an inconsistent power.
+			bad := &polycommit.Pk{Curve: c, G1P: append(pks[participants].G1P[:0:0], pks[participants].G1P...), G2P: pks[participants].G2P}
+			bad.G1P[deg-1] = c.NewG1().Set(bad.G1P[deg-2])
+			if VerifyChain(pks[0], cs, bad) == nil {
+				t.Error("VerifyChain accepted a malformed final key.")
+			}
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			pks, cs := runCeremony(t, c)
+			for i := range cs {
+				// Alternate between the uncompressed and the compressed format.
+				f := point.Format(i % 2)
+				b, err := cs[i].MarshalFormat(f)
+				if err != nil {
+					t.Error(err)
+				}
+				var ct Contribution
+				if err = ct.Unmarshal(b); err != nil {
+					t.Error(err)
+				}
+				cs[i] = ct
+			}
+			if err := VerifyChain(pks[0], cs, pks[participants]); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func BenchmarkContribute(b *testing.B) {
+	pk, _ := Start(curve.BN256, 256)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		Contribute(rand.Reader, pk)
+	}
+}
+
+func BenchmarkVerifyContribution(b *testing.B) {
+	pk, _ := Start(curve.BN256, 256)
+	next, ct, _ := Contribute(rand.Reader, pk)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		VerifyContribution(pk, next, ct)
+	}
+}
@@ -1,140 +1,133 @@
 package constantinople
 
 import (
-	"bytes"
-	"crypto/rand"
 	"crypto/sha256"
 	"errors"
 	"io"
 	"math/big"
 
-	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/poly"
+	"github.com/zhtluo/libpolycrypto/transcript"
 )
 
+// Domain separator of the hash of the coin onto G1.
+// The base of the coin must have an unknown discrete logarithm,
+// which g^sha256(coin) does not.
+var coinDomain = []byte("constantinople-coin")
+
 type PublicInfo struct {
-	V []bn256.G1
+	V []curve.Point
 }
 
 type Share struct {
-	S     big.Int
-	Index big.Int
+	S     curve.Scalar
+	Index curve.Scalar
 }
 
 // Proof on DLEQ with random r and
-// Pi = r + s[i] * hash(Gb, Gbi, Gr, Gbr, V).
+// Pi = r + s[i] * challenge(Gb, Gbi, Gr, Gbr, V),
+// where the challenge is derived from a transcript.
 type Proof struct {
-	Gbi   bn256.G1
-	Gr    bn256.G1
-	Gbr   bn256.G1
-	Pi    big.Int
-	Index big.Int
+	Gbi   curve.Point
+	Gr    curve.Point
+	Gbr   curve.Point
+	Pi    curve.Scalar
+	Index curve.Scalar
 }
 
-func GenerateData(r io.Reader, secret *big.Int, index []big.Int, degree int) (*PublicInfo, []Share, error) {
-	poly := make([]big.Int, degree)
-	poly[0] = *secret
-	for i := 1; i < degree; i++ {
-		v, err := rand.Int(r, bn256.Order)
-		if err != nil {
-			return nil, nil, err
-		}
-		poly[i] = *v
+// Generate the shares of the secret over the curve of the secret.
+func GenerateData(r io.Reader, secret curve.Scalar, index []curve.Scalar, degree int) (*PublicInfo, []Share, error) {
+	if degree < 1 {
+		return nil, nil, errors.New("Degree is less than 1")
+	}
+	c := secret.Curve()
+	p, err := poly.Random(c, r, degree)
+	if err != nil {
+		return nil, nil, err
 	}
+	p[0].Set(secret)
 	pi := new(PublicInfo)
-	pi.V = make([]bn256.G1, len(index))
+	pi.V = make([]curve.Point, len(index))
 	sh := make([]Share, len(index))
 	for i := 0; i < len(index); i++ {
-		sh[i].Index = index[i]
-		term := new(big.Int)
-		power := big.NewInt(1)
-		for j := 0; j < degree; j++ {
-			sh[i].S.Add(&sh[i].S, term.Mul(&poly[j], power))
-			sh[i].S.Mod(&sh[i].S, bn256.Order)
-			power.Mod(power.Mul(power, &sh[i].Index), bn256.Order)
-		}
-		pi.V[i].ScalarBaseMult(&sh[i].S)
+		sh[i].Index = c.NewScalar().Set(index[i])
+		sh[i].S = p.Evaluate(sh[i].Index)
+		pi.V[i] = c.NewG1().ScalarBaseMult(sh[i].S.BigInt())
 	}
 	return pi, sh, nil
 }
 
-func generateHashFromArray(g []*bn256.G1) *big.Int {
-	bytes := make([]byte, 0)
-	for i := range g {
-		bytes = append(bytes, g[i].Marshal()...)
-	}
-	hash := sha256.Sum256(bytes)
-	return new(big.Int).SetBytes(hash[:])
+func GenerateDataBig(c curve.Curve, r io.Reader, secret *big.Int, index []big.Int, degree int) (*PublicInfo, []Share, error) {
+	return GenerateData(r, c.NewScalar().SetBigInt(secret), curve.FromBigInts(c, index), degree)
 }
 
-func generateCoin(coin []byte) *bn256.G1 {
-	hash := sha256.Sum256(coin)
-	return new(bn256.G1).ScalarBaseMult(new(big.Int).SetBytes(hash[:]))
+// Derive the DLEQ challenge from the transcript of Gb, Gbi, Gr, Gbr and V.
+func generateChallenge(c curve.Curve, gb curve.Point, pr *Proof, v curve.Point) curve.Scalar {
+	t := transcript.New("constantinople-dleq")
+	t.AppendPoint("gb", gb)
+	t.AppendPoint("gbi", pr.Gbi)
+	t.AppendPoint("gr", pr.Gr)
+	t.AppendPoint("gbr", pr.Gbr)
+	t.AppendPoint("v", v)
+	return c.NewScalar().SetBytesReduce(t.ChallengeScalarBytes("challenge"))
 }
 
 func GenerateProof(r io.Reader, sh *Share, coin []byte) (*Proof, error) {
+	c := sh.S.Curve()
 	pr := new(Proof)
-	gb := generateCoin(coin)
-	rVal, err := rand.Int(r, bn256.Order)
+	gb := c.HashToG1(coinDomain, coin)
+	rVal, err := c.NewScalar().SetRandom(r)
 	if err != nil {
 		return nil, err
 	}
-	pr.Index.Set(&sh.Index)
-	pr.Gbi.ScalarMult(gb, &sh.S)
-	pr.Gr.ScalarBaseMult(rVal)
-	pr.Gbr.ScalarMult(gb, rVal)
-	pr.Pi.Mod(pr.Pi.Add(pr.Pi.Mul(generateHashFromArray(
-		[]*bn256.G1{gb, &pr.Gbi, &pr.Gr, &pr.Gbr, new(bn256.G1).ScalarBaseMult(&sh.S)}),
-		&sh.S), rVal), bn256.Order)
+	s, rv := sh.S.BigInt(), rVal.BigInt()
+	pr.Index = c.NewScalar().Set(sh.Index)
+	pr.Gbi = c.NewG1().ScalarMult(gb, s)
+	pr.Gr = c.NewG1().ScalarBaseMult(rv)
+	pr.Gbr = c.NewG1().ScalarMult(gb, rv)
+	pr.Pi = c.NewScalar().Mul(generateChallenge(c, gb, pr, c.NewG1().ScalarBaseMult(s)), sh.S)
+	pr.Pi.Add(pr.Pi, rVal)
 	return pr, nil
 }
 
 func VerifyProof(pi *PublicInfo, id int, coin []byte, pr *Proof) error {
-	gb := generateCoin(coin)
-	hash := generateHashFromArray(
-		[]*bn256.G1{gb, &pr.Gbi, &pr.Gr, &pr.Gbr, &pi.V[id]})
-	hash.Mod(hash, bn256.Order)
+	c := pr.Pi.Curve()
+	gb := c.HashToG1(coinDomain, coin)
+	hash := generateChallenge(c, gb, pr, pi.V[id]).BigInt()
+	p := pr.Pi.BigInt()
 
-	GPi := new(bn256.G1).ScalarBaseMult(&pr.Pi)
-	GbPi := new(bn256.G1).ScalarMult(gb, &pr.Pi)
+	GPi := c.NewG1().ScalarBaseMult(p)
+	GbPi := c.NewG1().ScalarMult(gb, p)
 
-	Vx := new(bn256.G1).ScalarMult(&pi.V[id], hash)
-	GRhs := new(bn256.G1).Add(&pr.Gr, Vx)
-	Gbix := new(bn256.G1).ScalarMult(&pr.Gbi, hash)
-	GbRhs := new(bn256.G1).Add(&pr.Gbr, Gbix)
+	Vx := c.NewG1().ScalarMult(pi.V[id], hash)
+	GRhs := c.NewG1().Add(pr.Gr, Vx)
+	Gbix := c.NewG1().ScalarMult(pr.Gbi, hash)
+	GbRhs := c.NewG1().Add(pr.Gbr, Gbix)
 
-	if !bytes.Equal(GPi.Marshal(), GRhs.Marshal()) ||
-		!bytes.Equal(GbPi.Marshal(), GbRhs.Marshal()) {
+	if !GPi.Equal(GRhs) || !GbPi.Equal(GbRhs) {
 		return errors.New("DLEQ verification failed.")
 	}
 	return nil
 }
 
-func interpolate(prs []Proof) *bn256.G1 {
-	inverse := make([]big.Int, len(prs))
-	for i := range inverse {
-		inverse[i].ModInverse(&prs[i].Index, bn256.Order)
+func interpolate(c curve.Curve, prs []Proof) curve.Point {
+	// lambda_i = prod_(j != i) x_j / (x_j - x_i)
+	xs := make([]curve.Scalar, len(prs))
+	for i := range prs {
+		xs[i] = prs[i].Index
 	}
-	val := new(bn256.G1).ScalarBaseMult(big.NewInt(0))
-	// Order + 1
-	orders1 := new(big.Int).Add(bn256.Order, big.NewInt(1))
+	lambda := poly.LagrangeCoefficients(xs, c.NewScalar())
+	val := c.NewG1()
+	term := c.NewG1()
 	for i := range prs {
-		partial := big.NewInt(1)
-		for j := range prs {
-			if i != j {
-				// p = p * (1 - x_i * x_j^-1)
-				term := new(big.Int).Mul(&prs[i].Index, &inverse[j])
-				term.Mod(term, bn256.Order)
-				partial.Mul(partial, new(big.Int).Sub(orders1, term))
-				partial.Mod(partial, bn256.Order)
-			}
-		}
-		partial.ModInverse(partial, bn256.Order)
-		val.Add(val, new(bn256.G1).ScalarMult(&prs[i].Gbi, partial))
+		val.Add(val, term.ScalarMult(prs[i].Gbi, lambda[i].BigInt()))
 	}
 	return val
 }
 
-func Reconstruct(prs []Proof) *big.Int {
-	hash := sha256.Sum256(interpolate(prs).Marshal())
+// Reconstruct the value of the coin from the proofs over the curve c.
+func Reconstruct(c curve.Curve, prs []Proof) *big.Int {
+	hash := sha256.Sum256(interpolate(c, prs).Marshal())
 	return new(big.Int).SetBytes(hash[:])
 }
@@ -6,7 +6,7 @@ import (
 	"crypto/rand"
 	"math/big"
 
-	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/zhtluo/libpolycrypto/curve"
 )
 
 const (
@@ -15,33 +15,59 @@ const (
 )
 
 var (
-	coin = []byte{0x05, 0xf1}
+	coin   = []byte{0x05, 0xf1}
+	curves = []curve.Curve{curve.BN256, curve.BN256Google, curve.BLS12381}
 )
 
 func TestProof(t *testing.T) {
-	index := make([]big.Int, deg)
-	for i := range index {
-		v, err := rand.Int(rand.Reader, bn256.Order)
-		if err != nil {
-			t.Error(err)
-		}
-		index[i] = *v
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			index := curve.NewScalars(c, deg)
+			for i := range index {
+				_, err := index[i].SetRandom(rand.Reader)
+				if err != nil {
+					t.Error(err)
+				}
+			}
+			pi, shs, err := GenerateData(rand.Reader, curve.NewScalar(c, secret), index, deg)
+			if err != nil {
+				t.Error(err)
+			}
+			prs := make([]Proof, deg)
+			for i := range index {
+				pr, err := GenerateProof(rand.Reader, &shs[i], coin)
+				if err != nil {
+					t.Error(err)
+				}
+				prs[i] = *pr
+				err = VerifyProof(pi, i, coin, &prs[i])
+				if err != nil {
+					t.Error(err)
+				}
+			}
+			// Shares of the same secret with another polynomial reconstruct the same value.
+			expected := Reconstruct(c, prs)
+			prs[0].Pi.SetRandom(rand.Reader)
+			if VerifyProof(pi, 0, coin, &prs[0]) == nil {
+				t.Error("VerifyProof accepted a tampered proof.")
+			}
+			_, shs, err = GenerateDataBig(c, rand.Reader, big.NewInt(secret), curve.ToBigInts(index), deg)
+			if err != nil {
+				t.Error(err)
+			}
+			for i := range index {
+				pr, err := GenerateProof(rand.Reader, &shs[i], coin)
+				if err != nil {
+					t.Error(err)
+				}
+				prs[i] = *pr
+			}
+			if Reconstruct(c, prs).Cmp(expected) != 0 {
+				t.Error("Reconstruct failed. Different results for the same secret.")
+			}
+			if _, _, err = GenerateData(rand.Reader, curve.NewScalar(c, secret), index, 0); err == nil {
+				t.Error("GenerateData accepted a degree of 0.")
+			}
+		})
 	}
-	pi, shs, err := GenerateData(rand.Reader, big.NewInt(secret), index, deg)
-	if err != nil {
-		t.Error(err)
-	}
-	prs := make([]Proof, deg)
-	for i := range index {
-		pr, err := GenerateProof(rand.Reader, &shs[i], coin)
-		if err != nil {
-			t.Error(err)
-		}
-		prs[i] = *pr
-		err = VerifyProof(pi, i, coin, &prs[i])
-		if err != nil {
-			t.Error(err)
-		}
-	}
-	Reconstruct(prs)
 }
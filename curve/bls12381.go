@@ -0,0 +1,373 @@
+package curve
+
+// This file implements the backend of BLS12-381 from crypto/bls12381.
+// The engines of bls12381 hold temporaries, so a new one is taken for every
+// operation to keep the points safe for concurrent use.
+// Points are compressed in the layout of ZCash: the big-endian x-coordinate,
+// with c1 first in G2, whose top three bits flag the compression, the point
+// at infinity and the greater y-coordinate respectively.
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+	"github.com/zhtluo/libpolycrypto/transcript"
+)
+
+var (
+	// Modulus of the base field of BLS12-381.
+	bls12381P, _  = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+	bls12381Order = bls12381.NewG1().Q()
+	// b of the twist y^2 = x^3 + 4(1 + u) of G2.
+	bls12381TwistB = func() *blsFp2 {
+		b := new(blsFp2)
+		b.c0.SetInt64(4)
+		b.c1.SetInt64(4)
+		return b
+	}()
+)
+
+const (
+	// Size of a coordinate of the base field in bytes.
+	bls12381FpSize = 48
+
+	bls12381FlagCompressed = 0x80
+	bls12381FlagInfinity   = 0x40
+	bls12381FlagGreater    = 0x20
+)
+
+// Split the flags off the first byte of the compressed point b into a copy,
+// checking that the point at infinity is encoded canonically.
+func bls12381SplitFlags(b []byte) (m []byte, greater bool, infinity bool, err error) {
+	m = make([]byte, len(b))
+	copy(m, b)
+	if m[0]&bls12381FlagCompressed == 0 {
+		return nil, false, false, errors.New("Point is not compressed")
+	}
+	greater = m[0]&bls12381FlagGreater != 0
+	infinity = m[0]&bls12381FlagInfinity != 0
+	m[0] &^= bls12381FlagCompressed | bls12381FlagInfinity | bls12381FlagGreater
+	if infinity && (greater || !isZero(m)) {
+		return nil, false, false, errors.New("Point at infinity is not encoded as zero")
+	}
+	return m, greater, infinity, nil
+}
+
+type bls12381Curve struct{}
+
+func (bls12381Curve) Name() string {
+	return "bls12381"
+}
+
+func (bls12381Curve) Order() *big.Int {
+	return bls12381Order
+}
+
+func (bls12381Curve) NewG1() Point {
+	return new(bls12381G1).SetInfinity()
+}
+
+func (bls12381Curve) NewG2() Point {
+	return new(bls12381G2).SetInfinity()
+}
+
+func (c bls12381Curve) NewScalar() Scalar {
+	return &bigScalar{c: c, n: bls12381Order}
+}
+
+func (c bls12381Curve) MultiplicativeGenerator() Scalar {
+	return c.NewScalar().SetInt64(7)
+}
+
+func (bls12381Curve) Pair(a, b Point) GT {
+	e := bls12381.NewPairingEngine()
+	e.AddPair(a.(*bls12381G1).p, b.(*bls12381G2).p)
+	return &bls12381GT{e.Result()}
+}
+
+func (bls12381Curve) PairingCheck(a, b []Point) bool {
+	e := bls12381.NewPairingEngine()
+	for i := range a {
+		e.AddPair(a[i].(*bls12381G1).p, b[i].(*bls12381G2).p)
+	}
+	return e.Check()
+}
+
+// Hash to a field element with the transcript and map it onto the curve
+// with the simplified SWU map of bls12381, which also clears the cofactor.
+func (bls12381Curve) HashToG1(domain, msg []byte) Point {
+	t := transcript.New("curve-hash-bls12381")
+	t.AppendMessage("domain", domain)
+	t.AppendMessage("msg", msg)
+	u := new(big.Int).SetBytes(t.ChallengeBytes("u", 64))
+	p, _ := bls12381.NewG1().MapToCurve(padBytes(u.Mod(u, bls12381P), 48))
+	return &bls12381G1{p}
+}
+
+// Struct bls12381G1 implements Point in G1.
+type bls12381G1 struct {
+	p *bls12381.PointG1
+}
+
+func (e *bls12381G1) Set(a Point) Point {
+	e.p = new(bls12381.PointG1).Set(a.(*bls12381G1).p)
+	return e
+}
+
+func (e *bls12381G1) SetInfinity() Point {
+	e.p = bls12381.NewG1().Zero()
+	return e
+}
+
+func (e *bls12381G1) Add(a, b Point) Point {
+	g := bls12381.NewG1()
+	e.p = g.Add(g.New(), a.(*bls12381G1).p, b.(*bls12381G1).p)
+	return e
+}
+
+func (e *bls12381G1) Neg(a Point) Point {
+	g := bls12381.NewG1()
+	e.p = g.Neg(g.New(), a.(*bls12381G1).p)
+	return e
+}
+
+func (e *bls12381G1) ScalarMult(a Point, k *big.Int) Point {
+	g := bls12381.NewG1()
+	e.p = g.MulScalar(g.New(), a.(*bls12381G1).p, reduce(k, bls12381Order))
+	return e
+}
+
+func (e *bls12381G1) ScalarBaseMult(k *big.Int) Point {
+	g := bls12381.NewG1()
+	e.p = g.MulScalar(g.New(), g.One(), reduce(k, bls12381Order))
+	return e
+}
+
+func (e *bls12381G1) IsInfinity() bool {
+	return bls12381.NewG1().IsZero(e.p)
+}
+
+func (e *bls12381G1) Equal(b Point) bool {
+	return bls12381.NewG1().Equal(e.p, b.(*bls12381G1).p)
+}
+
+func (e *bls12381G1) Marshal() []byte {
+	return bls12381.NewG1().ToBytes(e.p)
+}
+
+func (e *bls12381G1) Unmarshal(b []byte) error {
+	if err := checkSize(b, 2*bls12381FpSize); err != nil {
+		return err
+	}
+	g := bls12381.NewG1()
+	p, err := g.FromBytes(b)
+	if err != nil {
+		return err
+	}
+	if !g.InCorrectSubgroup(p) {
+		return errNotInSubgroup
+	}
+	e.p = p
+	return nil
+}
+
+func (e *bls12381G1) MarshalCompressed() []byte {
+	m := e.Marshal()
+	b := make([]byte, bls12381FpSize)
+	if e.IsInfinity() {
+		b[0] = bls12381FlagCompressed | bls12381FlagInfinity
+		return b
+	}
+	copy(b, m[:bls12381FpSize])
+	b[0] |= bls12381FlagCompressed
+	if blsFpGreater(new(big.Int).SetBytes(m[bls12381FpSize:])) {
+		b[0] |= bls12381FlagGreater
+	}
+	return b
+}
+
+func (e *bls12381G1) UnmarshalCompressed(b []byte) error {
+	if err := checkSize(b, bls12381FpSize); err != nil {
+		return err
+	}
+	m, greater, infinity, err := bls12381SplitFlags(b)
+	if err != nil {
+		return err
+	}
+	if infinity {
+		e.SetInfinity()
+		return nil
+	}
+	x := new(big.Int).SetBytes(m)
+	if x.Cmp(bls12381P) >= 0 {
+		return errors.New("Coordinate exceeds the modulus")
+	}
+	// y^2 = x^3 + 4
+	y := new(big.Int).Exp(x, big.NewInt(3), bls12381P)
+	y.Add(y, big.NewInt(4))
+	y = blsFpSqrt(y)
+	if y == nil {
+		return errors.New("Point is not on the curve")
+	}
+	if blsFpGreater(y) != greater {
+		y.Sub(bls12381P, y)
+	}
+	return e.Unmarshal(append(m, padBytes(y, bls12381FpSize)...))
+}
+
+// Struct bls12381G2 implements Point in G2.
+type bls12381G2 struct {
+	p *bls12381.PointG2
+}
+
+func (e *bls12381G2) Set(a Point) Point {
+	e.p = new(bls12381.PointG2).Set(a.(*bls12381G2).p)
+	return e
+}
+
+func (e *bls12381G2) SetInfinity() Point {
+	e.p = bls12381.NewG2().Zero()
+	return e
+}
+
+func (e *bls12381G2) Add(a, b Point) Point {
+	g := bls12381.NewG2()
+	e.p = g.Add(g.New(), a.(*bls12381G2).p, b.(*bls12381G2).p)
+	return e
+}
+
+func (e *bls12381G2) Neg(a Point) Point {
+	g := bls12381.NewG2()
+	e.p = g.Neg(g.New(), a.(*bls12381G2).p)
+	return e
+}
+
+func (e *bls12381G2) ScalarMult(a Point, k *big.Int) Point {
+	g := bls12381.NewG2()
+	e.p = g.MulScalar(g.New(), a.(*bls12381G2).p, reduce(k, bls12381Order))
+	return e
+}
+
+func (e *bls12381G2) ScalarBaseMult(k *big.Int) Point {
+	g := bls12381.NewG2()
+	e.p = g.MulScalar(g.New(), g.One(), reduce(k, bls12381Order))
+	return e
+}
+
+func (e *bls12381G2) IsInfinity() bool {
+	return bls12381.NewG2().IsZero(e.p)
+}
+
+func (e *bls12381G2) Equal(b Point) bool {
+	return bls12381.NewG2().Equal(e.p, b.(*bls12381G2).p)
+}
+
+func (e *bls12381G2) Marshal() []byte {
+	return bls12381.NewG2().ToBytes(e.p)
+}
+
+func (e *bls12381G2) Unmarshal(b []byte) error {
+	if err := checkSize(b, 4*bls12381FpSize); err != nil {
+		return err
+	}
+	g := bls12381.NewG2()
+	p, err := g.FromBytes(b)
+	if err != nil {
+		return err
+	}
+	if !g.InCorrectSubgroup(p) {
+		return errNotInSubgroup
+	}
+	e.p = p
+	return nil
+}
+
+func (e *bls12381G2) MarshalCompressed() []byte {
+	m := e.Marshal()
+	b := make([]byte, 2*bls12381FpSize)
+	if e.IsInfinity() {
+		b[0] = bls12381FlagCompressed | bls12381FlagInfinity
+		return b
+	}
+	copy(b, m[:2*bls12381FpSize])
+	b[0] |= bls12381FlagCompressed
+	var y blsFp2
+	y.c1.SetBytes(m[2*bls12381FpSize : 3*bls12381FpSize])
+	y.c0.SetBytes(m[3*bls12381FpSize:])
+	if y.greater() {
+		b[0] |= bls12381FlagGreater
+	}
+	return b
+}
+
+func (e *bls12381G2) UnmarshalCompressed(b []byte) error {
+	if err := checkSize(b, 2*bls12381FpSize); err != nil {
+		return err
+	}
+	m, greater, infinity, err := bls12381SplitFlags(b)
+	if err != nil {
+		return err
+	}
+	if infinity {
+		e.SetInfinity()
+		return nil
+	}
+	var x blsFp2
+	x.c1.SetBytes(m[:bls12381FpSize])
+	x.c0.SetBytes(m[bls12381FpSize:])
+	if x.c0.Cmp(bls12381P) >= 0 || x.c1.Cmp(bls12381P) >= 0 {
+		return errors.New("Coordinate exceeds the modulus")
+	}
+	// y^2 = x^3 + 4(1 + u)
+	var y blsFp2
+	y.mul(&x, &x)
+	y.mul(&y, &x)
+	y.add(&y, bls12381TwistB)
+	if !y.sqrt(&y) {
+		return errors.New("Point is not on the curve")
+	}
+	if y.greater() != greater {
+		y.neg(&y)
+	}
+	m = append(m, padBytes(&y.c1, bls12381FpSize)...)
+	return e.Unmarshal(append(m, padBytes(&y.c0, bls12381FpSize)...))
+}
+
+// Struct bls12381GT implements GT.
+type bls12381GT struct {
+	p *bls12381.E
+}
+
+func (e *bls12381GT) Set(a GT) GT {
+	e.p = new(bls12381.E).Set(a.(*bls12381GT).p)
+	return e
+}
+
+func (e *bls12381GT) Mul(a, b GT) GT {
+	g := bls12381.NewGT()
+	c := g.New()
+	g.Mul(c, a.(*bls12381GT).p, b.(*bls12381GT).p)
+	e.p = c
+	return e
+}
+
+func (e *bls12381GT) Exp(a GT, k *big.Int) GT {
+	g := bls12381.NewGT()
+	c := g.New()
+	g.Exp(c, a.(*bls12381GT).p, reduce(k, bls12381Order))
+	e.p = c
+	return e
+}
+
+func (e *bls12381GT) IsOne() bool {
+	return e.p.IsOne()
+}
+
+func (e *bls12381GT) Equal(b GT) bool {
+	return e.p.Equal(b.(*bls12381GT).p)
+}
+
+func (e *bls12381GT) Marshal() []byte {
+	return bls12381.NewGT().ToBytes(e.p)
+}
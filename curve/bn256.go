@@ -0,0 +1,268 @@
+package curve
+
+// This file implements the backend of bn256 from crypto/bn256/cloudflare,
+// and the hashing onto G1 of BN254 shared with crypto/bn256/google.
+
+import (
+	"bytes"
+	"math/big"
+
+	cloudflare "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/zhtluo/libpolycrypto/point"
+	"github.com/zhtluo/libpolycrypto/transcript"
+)
+
+// Hash the message msg onto G1 of BN254 under the domain separator domain
+// by try-and-increment on the x-coordinate, returning the point in the
+// 64-byte encoding of Marshal.
+func hashToBN254(domain, msg []byte) []byte {
+	t := transcript.New("curve-hash-bn254")
+	t.AppendMessage("domain", domain)
+	t.AppendMessage("msg", msg)
+	for {
+		// 48 bytes keep the bias of the reduction negligible.
+		x := new(big.Int).SetBytes(t.ChallengeBytes("x", 48))
+		x.Mod(x, cloudflare.P)
+		// y^2 = x^3 + 3
+		y := new(big.Int).Exp(x, big.NewInt(3), cloudflare.P)
+		y.Add(y, big.NewInt(3))
+		if y.ModSqrt(y, cloudflare.P) == nil {
+			continue
+		}
+		if t.ChallengeBytes("sign", 1)[0]&1 == 1 {
+			y.Sub(cloudflare.P, y)
+		}
+		return append(padBytes(x, 32), padBytes(y, 32)...)
+	}
+}
+
+type cloudflareCurve struct{}
+
+func (cloudflareCurve) Name() string {
+	return "bn256"
+}
+
+func (cloudflareCurve) Order() *big.Int {
+	return cloudflare.Order
+}
+
+func (cloudflareCurve) NewG1() Point {
+	return new(cloudflareG1).SetInfinity()
+}
+
+func (cloudflareCurve) NewG2() Point {
+	return new(cloudflareG2).SetInfinity()
+}
+
+func (c cloudflareCurve) NewScalar() Scalar {
+	return &frScalar{c: c}
+}
+
+func (c cloudflareCurve) MultiplicativeGenerator() Scalar {
+	return c.NewScalar().SetInt64(5)
+}
+
+func (cloudflareCurve) Pair(a, b Point) GT {
+	return &cloudflareGT{*cloudflare.Pair(&a.(*cloudflareG1).p, &b.(*cloudflareG2).p)}
+}
+
+func (cloudflareCurve) PairingCheck(a, b []Point) bool {
+	g1 := make([]*cloudflare.G1, len(a))
+	g2 := make([]*cloudflare.G2, len(b))
+	for i := range a {
+		g1[i] = &a[i].(*cloudflareG1).p
+	}
+	for i := range b {
+		g2[i] = &b[i].(*cloudflareG2).p
+	}
+	return cloudflare.PairingCheck(g1, g2)
+}
+
+func (cloudflareCurve) HashToG1(domain, msg []byte) Point {
+	e := new(cloudflareG1)
+	e.p.Unmarshal(hashToBN254(domain, msg))
+	return e
+}
+
+// The points at infinity, computed once since ScalarBaseMult does not
+// shortcut a zero scalar.
+var (
+	cloudflareInfinityG1 = new(cloudflare.G1).ScalarBaseMult(new(big.Int))
+	cloudflareInfinityG2 = new(cloudflare.G2).ScalarBaseMult(new(big.Int))
+)
+
+// Struct cloudflareG1 implements Point in G1.
+// Every sum goes through a temporary since Add of cloudflare doubles
+// incorrectly when the output aliases an equal input.
+type cloudflareG1 struct {
+	p cloudflare.G1
+}
+
+func (e *cloudflareG1) Set(a Point) Point {
+	e.p.Set(&a.(*cloudflareG1).p)
+	return e
+}
+
+func (e *cloudflareG1) SetInfinity() Point {
+	e.p.Set(cloudflareInfinityG1)
+	return e
+}
+
+func (e *cloudflareG1) Add(a, b Point) Point {
+	t := new(cloudflare.G1).Add(&a.(*cloudflareG1).p, &b.(*cloudflareG1).p)
+	e.p.Set(t)
+	return e
+}
+
+func (e *cloudflareG1) Neg(a Point) Point {
+	e.p.Set(new(cloudflare.G1).Neg(&a.(*cloudflareG1).p))
+	return e
+}
+
+func (e *cloudflareG1) ScalarMult(a Point, k *big.Int) Point {
+	e.p.Set(new(cloudflare.G1).ScalarMult(&a.(*cloudflareG1).p, reduce(k, cloudflare.Order)))
+	return e
+}
+
+func (e *cloudflareG1) ScalarBaseMult(k *big.Int) Point {
+	e.p.ScalarBaseMult(reduce(k, cloudflare.Order))
+	return e
+}
+
+func (e *cloudflareG1) IsInfinity() bool {
+	return isZero(e.p.Marshal())
+}
+
+func (e *cloudflareG1) Equal(b Point) bool {
+	return bytes.Equal(e.p.Marshal(), b.(*cloudflareG1).p.Marshal())
+}
+
+func (e *cloudflareG1) Marshal() []byte {
+	return e.p.Marshal()
+}
+
+func (e *cloudflareG1) Unmarshal(b []byte) error {
+	return e.decode(b, point.Uncompressed)
+}
+
+func (e *cloudflareG1) MarshalCompressed() []byte {
+	return point.CompressG1(&e.p)
+}
+
+func (e *cloudflareG1) UnmarshalCompressed(b []byte) error {
+	return e.decode(b, point.Compressed)
+}
+
+// Decode b in the format f, leaving the point untouched on failure.
+func (e *cloudflareG1) decode(b []byte, f point.Format) error {
+	p := new(cloudflare.G1)
+	if err := point.DecodeG1(p, b, f); err != nil {
+		return err
+	}
+	e.p.Set(p)
+	return nil
+}
+
+// Struct cloudflareG2 implements Point in G2.
+type cloudflareG2 struct {
+	p cloudflare.G2
+}
+
+func (e *cloudflareG2) Set(a Point) Point {
+	e.p.Set(&a.(*cloudflareG2).p)
+	return e
+}
+
+func (e *cloudflareG2) SetInfinity() Point {
+	e.p.Set(cloudflareInfinityG2)
+	return e
+}
+
+func (e *cloudflareG2) Add(a, b Point) Point {
+	t := new(cloudflare.G2).Add(&a.(*cloudflareG2).p, &b.(*cloudflareG2).p)
+	e.p.Set(t)
+	return e
+}
+
+func (e *cloudflareG2) Neg(a Point) Point {
+	e.p.Set(new(cloudflare.G2).Neg(&a.(*cloudflareG2).p))
+	return e
+}
+
+func (e *cloudflareG2) ScalarMult(a Point, k *big.Int) Point {
+	e.p.Set(new(cloudflare.G2).ScalarMult(&a.(*cloudflareG2).p, reduce(k, cloudflare.Order)))
+	return e
+}
+
+func (e *cloudflareG2) ScalarBaseMult(k *big.Int) Point {
+	e.p.ScalarBaseMult(reduce(k, cloudflare.Order))
+	return e
+}
+
+func (e *cloudflareG2) IsInfinity() bool {
+	return isZero(e.p.Marshal())
+}
+
+func (e *cloudflareG2) Equal(b Point) bool {
+	return bytes.Equal(e.p.Marshal(), b.(*cloudflareG2).p.Marshal())
+}
+
+func (e *cloudflareG2) Marshal() []byte {
+	return e.p.Marshal()
+}
+
+// Unmarshal of cloudflare also checks that the point is in the subgroup.
+func (e *cloudflareG2) Unmarshal(b []byte) error {
+	return e.decode(b, point.Uncompressed)
+}
+
+func (e *cloudflareG2) MarshalCompressed() []byte {
+	return point.CompressG2(&e.p)
+}
+
+func (e *cloudflareG2) UnmarshalCompressed(b []byte) error {
+	return e.decode(b, point.Compressed)
+}
+
+// Decode b in the format f, leaving the point untouched on failure.
+func (e *cloudflareG2) decode(b []byte, f point.Format) error {
+	p := new(cloudflare.G2)
+	if err := point.DecodeG2(p, b, f); err != nil {
+		return err
+	}
+	e.p.Set(p)
+	return nil
+}
+
+// Struct cloudflareGT implements GT.
+type cloudflareGT struct {
+	p cloudflare.GT
+}
+
+func (e *cloudflareGT) Set(a GT) GT {
+	e.p.Set(&a.(*cloudflareGT).p)
+	return e
+}
+
+func (e *cloudflareGT) Mul(a, b GT) GT {
+	e.p.Set(new(cloudflare.GT).Add(&a.(*cloudflareGT).p, &b.(*cloudflareGT).p))
+	return e
+}
+
+func (e *cloudflareGT) Exp(a GT, k *big.Int) GT {
+	e.p.Set(new(cloudflare.GT).ScalarMult(&a.(*cloudflareGT).p, reduce(k, cloudflare.Order)))
+	return e
+}
+
+func (e *cloudflareGT) IsOne() bool {
+	one := cloudflare.Pair(new(cloudflare.G1).ScalarBaseMult(new(big.Int)), new(cloudflare.G2).ScalarBaseMult(big.NewInt(1)))
+	return bytes.Equal(e.p.Marshal(), one.Marshal())
+}
+
+func (e *cloudflareGT) Equal(b GT) bool {
+	return bytes.Equal(e.p.Marshal(), b.(*cloudflareGT).p.Marshal())
+}
+
+func (e *cloudflareGT) Marshal() []byte {
+	return e.p.Marshal()
+}
@@ -0,0 +1,271 @@
+// Package curve implements an abstraction of the pairing-friendly curves
+// the library can be built on, with backends for bn256 from go-ethereum's
+// crypto/bn256/cloudflare and crypto/bn256/google, and for BLS12-381 from
+// go-ethereum's crypto/bls12381.
+//
+// BN254, the curve behind bn256, offers about 100 bits of security since the
+// exTNFS attacks, while BLS12-381 offers about 120. Every backend comes with
+// its own scalar type: both backends of bn256 share fr.Element, and BLS12-381
+// reduces big.Int modulo its order.
+//
+// Points, scalars and target group elements of different curves must not be
+// mixed, except that the two backends of bn256 share their scalars.
+
+package curve
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/zhtluo/libpolycrypto/point"
+)
+
+// Interface Point implements an element of G1 or G2.
+// As in math/big, every operation stores its result in the receiver
+// and returns it, and the receiver may alias the operands.
+type Point interface {
+	Set(a Point) Point
+	SetInfinity() Point
+	Add(a, b Point) Point
+	Neg(a Point) Point
+	// Compute k * a with k reduced modulo the order.
+	ScalarMult(a Point, k *big.Int) Point
+	// Compute k * g with the generator g of the group.
+	ScalarBaseMult(k *big.Int) Point
+	IsInfinity() bool
+	Equal(b Point) bool
+	Marshal() []byte
+	// Deserialize the point, checking that it is on the curve and in the
+	// subgroup of prime order.
+	Unmarshal(b []byte) error
+	// Return the encoding of the x-coordinate with the flags of the point.
+	MarshalCompressed() []byte
+	// Deserialize the compressed point with the checks of Unmarshal.
+	UnmarshalCompressed(b []byte) error
+}
+
+// Interface GT implements an element of the target group, written multiplicatively.
+type GT interface {
+	Set(a GT) GT
+	Mul(a, b GT) GT
+	// Compute a^k with k reduced modulo the order.
+	Exp(a GT, k *big.Int) GT
+	IsOne() bool
+	Equal(b GT) bool
+	Marshal() []byte
+}
+
+// Interface Scalar implements an element of the scalar field of a curve,
+// i.e. the integers modulo its order.
+// As for Point, every operation stores its result in the receiver.
+type Scalar interface {
+	// Return the curve the scalar was created by.
+	Curve() Curve
+	Set(a Scalar) Scalar
+	SetZero() Scalar
+	SetOne() Scalar
+	SetInt64(v int64) Scalar
+	// Set the scalar to v reduced modulo the order.
+	SetBigInt(v *big.Int) Scalar
+	// Set the scalar to the big-endian integer b reduced modulo the order,
+	// so that wide inputs give nearly uniform results.
+	SetBytesReduce(b []byte) Scalar
+	// Set the scalar to a uniformly random element read from r.
+	SetRandom(r io.Reader) (Scalar, error)
+	Add(a, b Scalar) Scalar
+	Sub(a, b Scalar) Scalar
+	Neg(a Scalar) Scalar
+	Mul(a, b Scalar) Scalar
+	// Compute 1 / a, where the inverse of 0 is 0.
+	Inverse(a Scalar) Scalar
+	// Compute a^k for k >= 0.
+	Exp(a Scalar, k *big.Int) Scalar
+	IsZero() bool
+	IsOne() bool
+	Equal(b Scalar) bool
+	// Return the scalar as an integer in [0, order).
+	BigInt() *big.Int
+	// Return the canonical big-endian encoding of ScalarSize bytes.
+	Marshal() []byte
+	// Deserialize the big-endian scalar of at most ScalarSize bytes,
+	// checking that it is less than the order. Shorter encodings, as
+	// written by big.Int.Bytes, are read as if padded with leading zeros.
+	Unmarshal(b []byte) error
+	String() string
+}
+
+// Interface Curve implements a pairing-friendly curve.
+type Curve interface {
+	Name() string
+	// Return the prime order of G1, G2 and GT.
+	Order() *big.Int
+	// Return a new point at infinity in G1.
+	NewG1() Point
+	// Return a new point at infinity in G2.
+	NewG2() Point
+	// Return a new zero scalar.
+	NewScalar() Scalar
+	// Return a generator of the multiplicative group of the scalar field.
+	MultiplicativeGenerator() Scalar
+	// Compute the pairing e(a, b) of a in G1 and b in G2.
+	Pair(a, b Point) GT
+	// Check that the product of e(a[i], b[i]) is one.
+	PairingCheck(a, b []Point) bool
+	// Hash the message msg onto G1 under the domain separator domain,
+	// so that the discrete logarithm of the result is unknown.
+	HashToG1(domain, msg []byte) Point
+}
+
+// Size in bytes of the encoding of a scalar of every curve.
+const ScalarSize = 32
+
+var (
+	BN256       Curve = cloudflareCurve{}
+	BN256Google Curve = googleCurve{}
+	BLS12381    Curve = bls12381Curve{}
+)
+
+var errNotInSubgroup = errors.New("Point is not in the subgroup")
+
+// Return the curve with the name. The empty name selects BN256,
+// the curve of messages that do not record one.
+func ByName(name string) (Curve, error) {
+	switch name {
+	case "", BN256.Name():
+		return BN256, nil
+	case BN256Google.Name():
+		return BN256Google, nil
+	case BLS12381.Name():
+		return BLS12381, nil
+	}
+	return nil, errors.New("Unknown curve")
+}
+
+// Encode the point p in the format f.
+func Encode(p Point, f point.Format) []byte {
+	if f == point.Compressed {
+		return p.MarshalCompressed()
+	}
+	return p.Marshal()
+}
+
+// Decode the point b encoded in the format f into p.
+func Decode(p Point, b []byte, f point.Format) error {
+	switch f {
+	case point.Uncompressed:
+		return p.Unmarshal(b)
+	case point.Compressed:
+		return p.UnmarshalCompressed(b)
+	}
+	return errors.New("Unknown point format")
+}
+
+// Return n new zero scalars of the curve c.
+func NewScalars(c Curve, n int) []Scalar {
+	ret := make([]Scalar, n)
+	for i := range ret {
+		ret[i] = c.NewScalar()
+	}
+	return ret
+}
+
+// Return a new scalar of the curve c holding v reduced modulo the order.
+func NewScalar(c Curve, v int64) Scalar {
+	return c.NewScalar().SetInt64(v)
+}
+
+// Return a copy of every scalar of a.
+func CloneScalars(a []Scalar) []Scalar {
+	ret := make([]Scalar, len(a))
+	for i := range a {
+		ret[i] = a[i].Curve().NewScalar().Set(a[i])
+	}
+	return ret
+}
+
+// Convert every big.Int in v into a scalar of the curve c reduced modulo the order.
+func FromBigInts(c Curve, v []big.Int) []Scalar {
+	ret := make([]Scalar, len(v))
+	for i := range v {
+		ret[i] = c.NewScalar().SetBigInt(&v[i])
+	}
+	return ret
+}
+
+// Convert every scalar in v into a big.Int in [0, order).
+func ToBigInts(v []Scalar) []big.Int {
+	ret := make([]big.Int, len(v))
+	for i := range v {
+		ret[i].Set(v[i].BigInt())
+	}
+	return ret
+}
+
+// Invert every scalar of a in place with a single inversion.
+// Zero scalars are left as 0.
+func BatchInvert(a []Scalar) {
+	if len(a) == 0 {
+		return
+	}
+	prefix := make([]Scalar, len(a))
+	c := a[0].Curve()
+	acc := c.NewScalar().SetOne()
+	for i := range a {
+		prefix[i] = c.NewScalar().Set(acc)
+		if !a[i].IsZero() {
+			acc.Mul(acc, a[i])
+		}
+	}
+	acc.Inverse(acc)
+	inv := c.NewScalar()
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i].IsZero() {
+			continue
+		}
+		// a[i]^-1 = (a_0 ... a_(i - 1)) * (a_0 ... a_i)^-1
+		inv.Mul(prefix[i], acc)
+		acc.Mul(acc, a[i])
+		a[i].Set(inv)
+	}
+}
+
+// Return k modulo the order n.
+func reduce(k *big.Int, n *big.Int) *big.Int {
+	if k.Sign() >= 0 && k.Cmp(n) < 0 {
+		return k
+	}
+	return new(big.Int).Mod(k, n)
+}
+
+// Encode v as a big-endian integer of size bytes.
+func padBytes(v *big.Int, size int) []byte {
+	b := make([]byte, size)
+	return v.FillBytes(b)
+}
+
+// Return whether b is all zeros.
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Check that the encoding b has exactly size bytes.
+func checkSize(b []byte, size int) error {
+	if len(b) != size {
+		return errors.New("Encoding has a wrong size")
+	}
+	return nil
+}
+
+// Check that the encoding b has at most size bytes.
+func checkMaxSize(b []byte, size int) error {
+	if len(b) > size {
+		return errors.New("Encoding is too long")
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package curve
+
+import (
+	"testing"
+
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+
+	"github.com/zhtluo/libpolycrypto/point"
+)
+
+var curves = []Curve{BN256, BN256Google, BLS12381}
+
+func randomScalar(c Curve) *big.Int {
+	k, _ := rand.Int(rand.Reader, c.Order())
+	return k
+}
+
+// Run the group laws shared by G1 and G2 on the points built by newPoint.
+func testGroup(t *testing.T, c Curve, newPoint func() Point) {
+	a, b := randomScalar(c), randomScalar(c)
+	pa := newPoint().ScalarBaseMult(a)
+	pb := newPoint().ScalarBaseMult(b)
+	sum := newPoint().Add(pa, pb)
+	if !sum.Equal(newPoint().ScalarBaseMult(new(big.Int).Add(a, b))) {
+		t.Error("Add failed, expected: true.")
+	}
+	if !sum.Equal(newPoint().Add(pb, pa)) {
+		t.Error("Add failed. Not commutative.")
+	}
+	if !newPoint().Add(pa, newPoint().Neg(pa)).IsInfinity() {
+		t.Error("Neg failed, expected: true.")
+	}
+	if !newPoint().ScalarMult(pa, b).Equal(newPoint().ScalarBaseMult(new(big.Int).Mul(a, b))) {
+		t.Error("ScalarMult failed, expected: true.")
+	}
+	if !newPoint().ScalarMult(pa, new(big.Int).Neg(big.NewInt(1))).Equal(newPoint().Neg(pa)) {
+		t.Error("ScalarMult failed with a negative scalar.")
+	}
+	if !newPoint().ScalarBaseMult(c.Order()).IsInfinity() || pa.IsInfinity() {
+		t.Error("IsInfinity failed, expected: true.")
+	}
+	// The receiver may alias the operands.
+	d := newPoint().Set(pa)
+	d.Add(d, d)
+	if !d.Equal(newPoint().ScalarBaseMult(new(big.Int).Lsh(a, 1))) {
+		t.Error("Add failed with aliased operands.")
+	}
+	d.Set(pa)
+	if !d.Neg(d).Equal(newPoint().Neg(pa)) || !pa.Equal(newPoint().ScalarBaseMult(a)) {
+		t.Error("Neg failed with aliased operands.")
+	}
+	for _, p := range []Point{pa, newPoint()} {
+		r := newPoint()
+		if err := r.Unmarshal(p.Marshal()); err != nil || !r.Equal(p) {
+			t.Error("Unmarshal failed, expected: true.")
+		}
+	}
+	m := pa.Marshal()
+	m[len(m)-1] ^= 1
+	if newPoint().Unmarshal(m) == nil {
+		t.Error("Unmarshal accepted a point off the curve.")
+	}
+	if newPoint().Unmarshal(append(pa.Marshal(), 0)) == nil {
+		t.Error("Unmarshal accepted trailing bytes.")
+	}
+	for _, p := range []Point{pa, newPoint().Neg(pa), newPoint()} {
+		r := newPoint()
+		if err := r.UnmarshalCompressed(p.MarshalCompressed()); err != nil || !r.Equal(p) {
+			t.Error("UnmarshalCompressed failed, expected: true.")
+		}
+		r = newPoint()
+		if err := Decode(r, Encode(p, point.Compressed), point.Compressed); err != nil || !r.Equal(p) {
+			t.Error("Decode failed, expected: true.")
+		}
+	}
+	if len(pa.MarshalCompressed())*2 != len(pa.Marshal()) {
+		t.Error("MarshalCompressed failed. Not half of the uncompressed size.")
+	}
+	if newPoint().UnmarshalCompressed(pa.Marshal()) == nil {
+		t.Error("UnmarshalCompressed accepted an uncompressed point.")
+	}
+}
+
+func testScalar(t *testing.T, c Curve) {
+	a, err := c.NewScalar().SetRandom(rand.Reader)
+	if err != nil {
+		t.Error("SetRandom failed.")
+		return
+	}
+	b := c.NewScalar().SetBigInt(randomScalar(c))
+	n := c.Order()
+	sum := new(big.Int).Add(a.BigInt(), b.BigInt())
+	if c.NewScalar().Add(a, b).BigInt().Cmp(sum.Mod(sum, n)) != 0 {
+		t.Error("Add failed, expected: true.")
+	}
+	diff := new(big.Int).Sub(a.BigInt(), b.BigInt())
+	if c.NewScalar().Sub(a, b).BigInt().Cmp(diff.Mod(diff, n)) != 0 {
+		t.Error("Sub failed, expected: true.")
+	}
+	prod := new(big.Int).Mul(a.BigInt(), b.BigInt())
+	if c.NewScalar().Mul(a, b).BigInt().Cmp(prod.Mod(prod, n)) != 0 {
+		t.Error("Mul failed, expected: true.")
+	}
+	if !c.NewScalar().Add(a, c.NewScalar().Neg(a)).IsZero() || !c.NewScalar().Neg(c.NewScalar()).IsZero() {
+		t.Error("Neg failed, expected: true.")
+	}
+	if !c.NewScalar().Mul(a, c.NewScalar().Inverse(a)).IsOne() || !c.NewScalar().Inverse(c.NewScalar()).IsZero() {
+		t.Error("Inverse failed, expected: true.")
+	}
+	if !c.NewScalar().Exp(a, big.NewInt(3)).Equal(c.NewScalar().Mul(a, c.NewScalar().Mul(a, a))) {
+		t.Error("Exp failed, expected: true.")
+	}
+	if !c.NewScalar().SetInt64(-1).Equal(c.NewScalar().SetBigInt(new(big.Int).Sub(n, big.NewInt(1)))) {
+		t.Error("SetInt64 failed with a negative value.")
+	}
+	// The receiver may alias the operands.
+	d := c.NewScalar().Set(a)
+	if !d.Mul(d, d).Equal(c.NewScalar().Mul(a, a)) {
+		t.Error("Mul failed with aliased operands.")
+	}
+	r := c.NewScalar()
+	if err := r.Unmarshal(a.Marshal()); err != nil || !r.Equal(a) || len(a.Marshal()) != ScalarSize {
+		t.Error("Unmarshal failed, expected: true.")
+	}
+	if r.Unmarshal(padBytes(n, ScalarSize)) == nil {
+		t.Error("Unmarshal accepted an unreduced scalar.")
+	}
+	if err := r.Unmarshal(big.NewInt(1).Bytes()); err != nil || !r.IsOne() {
+		t.Error("Unmarshal failed with a short encoding.")
+	}
+	if err := r.Unmarshal(nil); err != nil || !r.IsZero() {
+		t.Error("Unmarshal failed with an empty encoding.")
+	}
+	if r.Unmarshal(append([]byte{0}, a.Marshal()...)) == nil {
+		t.Error("Unmarshal accepted a long encoding.")
+	}
+	wide := append(padBytes(big.NewInt(1), ScalarSize), make([]byte, ScalarSize)...)
+	if c.NewScalar().SetBytesReduce(wide).BigInt().Cmp(new(big.Int).Mod(new(big.Int).SetBytes(wide), n)) != 0 {
+		t.Error("SetBytesReduce failed, expected: true.")
+	}
+	// The multiplicative generator is not a square.
+	e := new(big.Int).Rsh(new(big.Int).Sub(n, big.NewInt(1)), 1)
+	if c.NewScalar().Exp(c.MultiplicativeGenerator(), e).IsOne() {
+		t.Error("MultiplicativeGenerator failed. Generator is a square.")
+	}
+	s := CloneScalars([]Scalar{a, c.NewScalar(), b})
+	BatchInvert(s)
+	if !s[0].Equal(c.NewScalar().Inverse(a)) || !s[1].IsZero() || !s[2].Equal(c.NewScalar().Inverse(b)) {
+		t.Error("BatchInvert failed, expected: true.")
+	}
+}
+
+func testCurve(t *testing.T, c Curve) {
+	testGroup(t, c, c.NewG1)
+	testGroup(t, c, c.NewG2)
+	testScalar(t, c)
+	if c.NewScalar().Curve() != c {
+		t.Error("Curve failed. Scalar does not record its curve.")
+	}
+	if d, err := ByName(c.Name()); err != nil || d != c {
+		t.Error("ByName failed, expected: true.")
+	}
+	a, b := randomScalar(c), randomScalar(c)
+	g1 := c.NewG1().ScalarBaseMult(big.NewInt(1))
+	g2 := c.NewG2().ScalarBaseMult(big.NewInt(1))
+	e := c.Pair(g1, g2)
+	if e.IsOne() {
+		t.Error("Pair failed. Pairing is degenerate.")
+	}
+	// e(a * g1, b * g2) = e(g1, g2)^(ab)
+	lhs := c.Pair(c.NewG1().ScalarBaseMult(a), c.NewG2().ScalarBaseMult(b))
+	if !lhs.Equal(e.Exp(e, new(big.Int).Mul(a, b))) {
+		t.Error("Pair failed. Pairing is not bilinear.")
+	}
+	e = c.Pair(g1, g2)
+	if !e.Mul(e, c.Pair(c.NewG1().Neg(g1), g2)).IsOne() {
+		t.Error("Mul failed, expected: true.")
+	}
+	if !c.Pair(c.NewG1(), g2).IsOne() {
+		t.Error("Pair failed with infinity, expected: true.")
+	}
+	p := []Point{c.NewG1().ScalarBaseMult(a), c.NewG1().Neg(g1)}
+	q := []Point{c.NewG2().ScalarBaseMult(b), c.NewG2().ScalarBaseMult(new(big.Int).Mul(a, b))}
+	if !c.PairingCheck(p, q) {
+		t.Error("PairingCheck failed, expected: true.")
+	}
+	q[0].ScalarBaseMult(a)
+	if c.PairingCheck(p, q) {
+		t.Error("PairingCheck failed, expected: false.")
+	}
+	h := c.HashToG1([]byte("test"), []byte("message"))
+	if h.IsInfinity() || c.NewG1().Unmarshal(h.Marshal()) != nil {
+		t.Error("HashToG1 failed. Not a point of the subgroup.")
+	}
+	if !h.Equal(c.HashToG1([]byte("test"), []byte("message"))) {
+		t.Error("HashToG1 failed. Not deterministic.")
+	}
+	if h.Equal(c.HashToG1([]byte("other"), []byte("message"))) || h.Equal(c.HashToG1([]byte("test"), []byte("other"))) {
+		t.Error("HashToG1 failed. Domain or message is ignored.")
+	}
+}
+
+func TestBN256(t *testing.T) {
+	testCurve(t, BN256)
+}
+
+func TestBN256Google(t *testing.T) {
+	testCurve(t, BN256Google)
+}
+
+func TestBLS12381(t *testing.T) {
+	testCurve(t, BLS12381)
+}
+
+func TestBN256Backends(t *testing.T) {
+	k := randomScalar(BN256)
+	if !bytes.Equal(BN256.NewG1().ScalarBaseMult(k).Marshal(), BN256Google.NewG1().ScalarBaseMult(k).Marshal()) {
+		t.Error("ScalarBaseMult failed. Backends of bn256 disagree in G1.")
+	}
+	if !bytes.Equal(BN256.NewG2().ScalarBaseMult(k).Marshal(), BN256Google.NewG2().ScalarBaseMult(k).Marshal()) {
+		t.Error("ScalarBaseMult failed. Backends of bn256 disagree in G2.")
+	}
+	if !bytes.Equal(BN256.HashToG1(nil, []byte("m")).Marshal(), BN256Google.HashToG1(nil, []byte("m")).Marshal()) {
+		t.Error("HashToG1 failed. Backends of bn256 disagree.")
+	}
+	p := BN256.NewG2().ScalarBaseMult(k)
+	if !bytes.Equal(p.MarshalCompressed(), BN256Google.NewG2().ScalarBaseMult(k).MarshalCompressed()) {
+		t.Error("MarshalCompressed failed. Backends of bn256 disagree.")
+	}
+}
+
+func TestBLS12381Compressed(t *testing.T) {
+	// The generator of G1 in the layout of ZCash.
+	want := "97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"
+	if hex.EncodeToString(BLS12381.NewG1().ScalarBaseMult(big.NewInt(1)).MarshalCompressed()) != want {
+		t.Error("MarshalCompressed failed. Generator does not match the layout of ZCash.")
+	}
+}
+
+func TestByName(t *testing.T) {
+	if c, err := ByName(""); err != nil || c != BN256 {
+		t.Error("ByName failed. Empty name is not BN256.")
+	}
+	if _, err := ByName("unknown"); err == nil {
+		t.Error("ByName failed. Unknown curve accepted.")
+	}
+}
+
+func BenchmarkPairingCheck(b *testing.B) {
+	for _, c := range curves {
+		p := []Point{c.NewG1().ScalarBaseMult(big.NewInt(1)), c.NewG1().ScalarBaseMult(big.NewInt(-1))}
+		q := []Point{c.NewG2().ScalarBaseMult(big.NewInt(1)), c.NewG2().ScalarBaseMult(big.NewInt(1))}
+		b.Run(c.Name(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				c.PairingCheck(p, q)
+			}
+		})
+	}
+}
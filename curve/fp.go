@@ -0,0 +1,129 @@
+package curve
+
+// This file implements the square roots in the base field of BLS12-381 and
+// its quadratic extension needed to decompress points. As for bn256 in the
+// point package, p = 3 mod 4 and the extension is built with u^2 = -1.
+
+import (
+	"math/big"
+)
+
+var (
+	// (p + 1) / 4, (p - 3) / 4 and (p - 1) / 2.
+	blsPPlus1Over4  = new(big.Int).Rsh(new(big.Int).Add(bls12381P, big.NewInt(1)), 2)
+	blsPMinus3Over4 = new(big.Int).Rsh(new(big.Int).Sub(bls12381P, big.NewInt(3)), 2)
+	blsPMinus1Over2 = new(big.Int).Rsh(new(big.Int).Sub(bls12381P, big.NewInt(1)), 1)
+)
+
+// Return the square root of a modulo p, or nil if there is none.
+// Since p = 3 mod 4 the root is a^((p + 1) / 4).
+func blsFpSqrt(a *big.Int) *big.Int {
+	y := new(big.Int).Exp(a, blsPPlus1Over4, bls12381P)
+	check := new(big.Int).Mul(y, y)
+	if check.Mod(check, bls12381P).Cmp(new(big.Int).Mod(a, bls12381P)) != 0 {
+		return nil
+	}
+	return y
+}
+
+// Return whether y is greater than -y.
+func blsFpGreater(y *big.Int) bool {
+	return y.Cmp(blsPMinus1Over2) > 0
+}
+
+// Struct blsFp2 implements c0 + c1 * u with u^2 = -1.
+type blsFp2 struct {
+	c0 big.Int
+	c1 big.Int
+}
+
+func (z *blsFp2) set(x *blsFp2) *blsFp2 {
+	z.c0.Set(&x.c0)
+	z.c1.Set(&x.c1)
+	return z
+}
+
+func (z *blsFp2) add(x, y *blsFp2) *blsFp2 {
+	z.c0.Mod(z.c0.Add(&x.c0, &y.c0), bls12381P)
+	z.c1.Mod(z.c1.Add(&x.c1, &y.c1), bls12381P)
+	return z
+}
+
+func (z *blsFp2) neg(x *blsFp2) *blsFp2 {
+	z.c0.Mod(z.c0.Neg(&x.c0), bls12381P)
+	z.c1.Mod(z.c1.Neg(&x.c1), bls12381P)
+	return z
+}
+
+func (z *blsFp2) mul(x, y *blsFp2) *blsFp2 {
+	// (a + bu)(c + du) = (ac - bd) + (ad + bc)u
+	ac := new(big.Int).Mul(&x.c0, &y.c0)
+	bd := new(big.Int).Mul(&x.c1, &y.c1)
+	ad := new(big.Int).Mul(&x.c0, &y.c1)
+	bc := new(big.Int).Mul(&x.c1, &y.c0)
+	z.c0.Mod(ac.Sub(ac, bd), bls12381P)
+	z.c1.Mod(ad.Add(ad, bc), bls12381P)
+	return z
+}
+
+func (z *blsFp2) exp(x *blsFp2, e *big.Int) *blsFp2 {
+	base := new(blsFp2).set(x)
+	res := new(blsFp2)
+	res.c0.SetInt64(1)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res.mul(res, res)
+		if e.Bit(i) == 1 {
+			res.mul(res, base)
+		}
+	}
+	return z.set(res)
+}
+
+func (z *blsFp2) equal(x *blsFp2) bool {
+	return z.c0.Cmp(&x.c0) == 0 && z.c1.Cmp(&x.c1) == 0
+}
+
+// Return whether z is greater than -z, comparing c1 first.
+func (z *blsFp2) greater() bool {
+	if z.c1.Sign() != 0 {
+		return blsFpGreater(&z.c1)
+	}
+	return blsFpGreater(&z.c0)
+}
+
+// Set z to a square root of x and return whether one exists,
+// with algorithm 9 of
+// G. Adj, F. Rodriguez-Henriquez.
+// Square Root Computation over Even Extension Fields.
+func (z *blsFp2) sqrt(x *blsFp2) bool {
+	minus1 := new(blsFp2)
+	minus1.c0.Sub(bls12381P, big.NewInt(1))
+	a1 := new(blsFp2).exp(x, blsPMinus3Over4)
+	alpha := new(blsFp2).mul(a1, a1)
+	alpha.mul(alpha, x)
+	// alpha^p is the conjugate of alpha.
+	a0 := new(blsFp2).set(alpha)
+	a0.c1.Mod(a0.c1.Neg(&a0.c1), bls12381P)
+	a0.mul(a0, alpha)
+	if a0.equal(minus1) {
+		return false
+	}
+	x0 := new(blsFp2).mul(a1, x)
+	res := new(blsFp2)
+	if alpha.equal(minus1) {
+		// u * x0
+		res.c0.Mod(res.c0.Neg(&x0.c1), bls12381P)
+		res.c1.Set(&x0.c0)
+	} else {
+		b := new(blsFp2).set(alpha)
+		b.c0.Mod(b.c0.Add(&b.c0, big.NewInt(1)), bls12381P)
+		b.exp(b, blsPMinus1Over2)
+		res.mul(b, x0)
+	}
+	check := new(blsFp2).mul(res, res)
+	if !check.equal(x) {
+		return false
+	}
+	z.set(res)
+	return true
+}
@@ -0,0 +1,254 @@
+package curve
+
+// This file implements the backend of bn256 from crypto/bn256/google.
+// The wrapped points are never modified once computed, so Set shares them.
+// Both backends of bn256 share the encodings of Marshal, so points are
+// compressed through the point package on the points of cloudflare.
+
+import (
+	"bytes"
+	"math/big"
+
+	cloudflare "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	google "github.com/ethereum/go-ethereum/crypto/bn256/google"
+	"github.com/zhtluo/libpolycrypto/point"
+)
+
+type googleCurve struct{}
+
+func (googleCurve) Name() string {
+	return "bn256-google"
+}
+
+func (googleCurve) Order() *big.Int {
+	return google.Order
+}
+
+func (googleCurve) NewG1() Point {
+	return new(googleG1).SetInfinity()
+}
+
+func (googleCurve) NewG2() Point {
+	return new(googleG2).SetInfinity()
+}
+
+func (c googleCurve) NewScalar() Scalar {
+	return &frScalar{c: c}
+}
+
+func (c googleCurve) MultiplicativeGenerator() Scalar {
+	return c.NewScalar().SetInt64(5)
+}
+
+func (googleCurve) Pair(a, b Point) GT {
+	return &googleGT{google.Pair(a.(*googleG1).p, b.(*googleG2).p)}
+}
+
+func (googleCurve) PairingCheck(a, b []Point) bool {
+	g1 := make([]*google.G1, len(a))
+	g2 := make([]*google.G2, len(b))
+	for i := range a {
+		g1[i] = a[i].(*googleG1).p
+	}
+	for i := range b {
+		g2[i] = b[i].(*googleG2).p
+	}
+	return google.PairingCheck(g1, g2)
+}
+
+func (googleCurve) HashToG1(domain, msg []byte) Point {
+	p := new(google.G1)
+	p.Unmarshal(hashToBN254(domain, msg))
+	return &googleG1{p}
+}
+
+// The points at infinity, shared by every point set to infinity.
+var (
+	googleInfinityG1 = new(google.G1).ScalarBaseMult(new(big.Int))
+	googleInfinityG2 = new(google.G2).ScalarBaseMult(new(big.Int))
+)
+
+// Struct googleG1 implements Point in G1.
+type googleG1 struct {
+	p *google.G1
+}
+
+func (e *googleG1) Set(a Point) Point {
+	e.p = a.(*googleG1).p
+	return e
+}
+
+func (e *googleG1) SetInfinity() Point {
+	e.p = googleInfinityG1
+	return e
+}
+
+func (e *googleG1) Add(a, b Point) Point {
+	e.p = new(google.G1).Add(a.(*googleG1).p, b.(*googleG1).p)
+	return e
+}
+
+func (e *googleG1) Neg(a Point) Point {
+	e.p = new(google.G1).Neg(a.(*googleG1).p)
+	return e
+}
+
+func (e *googleG1) ScalarMult(a Point, k *big.Int) Point {
+	e.p = new(google.G1).ScalarMult(a.(*googleG1).p, reduce(k, google.Order))
+	return e
+}
+
+func (e *googleG1) ScalarBaseMult(k *big.Int) Point {
+	e.p = new(google.G1).ScalarBaseMult(reduce(k, google.Order))
+	return e
+}
+
+func (e *googleG1) IsInfinity() bool {
+	return isZero(e.p.Marshal())
+}
+
+func (e *googleG1) Equal(b Point) bool {
+	return bytes.Equal(e.p.Marshal(), b.(*googleG1).p.Marshal())
+}
+
+func (e *googleG1) Marshal() []byte {
+	return e.p.Marshal()
+}
+
+func (e *googleG1) Unmarshal(b []byte) error {
+	if err := checkSize(b, point.G1UncompressedSize); err != nil {
+		return err
+	}
+	p := new(google.G1)
+	if _, err := p.Unmarshal(b); err != nil {
+		return err
+	}
+	e.p = p
+	return nil
+}
+
+func (e *googleG1) MarshalCompressed() []byte {
+	p := new(cloudflare.G1)
+	p.Unmarshal(e.p.Marshal())
+	return point.CompressG1(p)
+}
+
+func (e *googleG1) UnmarshalCompressed(b []byte) error {
+	p := new(cloudflare.G1)
+	if err := point.DecompressG1(p, b); err != nil {
+		return err
+	}
+	return e.Unmarshal(p.Marshal())
+}
+
+// Struct googleG2 implements Point in G2.
+type googleG2 struct {
+	p *google.G2
+}
+
+func (e *googleG2) Set(a Point) Point {
+	e.p = a.(*googleG2).p
+	return e
+}
+
+func (e *googleG2) SetInfinity() Point {
+	e.p = googleInfinityG2
+	return e
+}
+
+func (e *googleG2) Add(a, b Point) Point {
+	e.p = new(google.G2).Add(a.(*googleG2).p, b.(*googleG2).p)
+	return e
+}
+
+// Negate the y-coordinate in place on a copy, since google.G2 has no Neg
+// and multiplying by Order - 1 is slow.
+func (e *googleG2) Neg(a Point) Point {
+	p := new(google.G2).Add(a.(*googleG2).p, googleInfinityG2)
+	_, y, _, _ := p.CurvePoints()
+	y.Negative(y)
+	e.p = p
+	return e
+}
+
+func (e *googleG2) ScalarMult(a Point, k *big.Int) Point {
+	e.p = new(google.G2).ScalarMult(a.(*googleG2).p, reduce(k, google.Order))
+	return e
+}
+
+func (e *googleG2) ScalarBaseMult(k *big.Int) Point {
+	e.p = new(google.G2).ScalarBaseMult(reduce(k, google.Order))
+	return e
+}
+
+func (e *googleG2) IsInfinity() bool {
+	return isZero(e.p.Marshal())
+}
+
+func (e *googleG2) Equal(b Point) bool {
+	return bytes.Equal(e.p.Marshal(), b.(*googleG2).p.Marshal())
+}
+
+func (e *googleG2) Marshal() []byte {
+	return e.p.Marshal()
+}
+
+// Unmarshal of google also checks that the point is in the subgroup.
+func (e *googleG2) Unmarshal(b []byte) error {
+	if err := checkSize(b, point.G2UncompressedSize); err != nil {
+		return err
+	}
+	p := new(google.G2)
+	if _, err := p.Unmarshal(b); err != nil {
+		return err
+	}
+	e.p = p
+	return nil
+}
+
+func (e *googleG2) MarshalCompressed() []byte {
+	p := new(cloudflare.G2)
+	p.Unmarshal(e.p.Marshal())
+	return point.CompressG2(p)
+}
+
+func (e *googleG2) UnmarshalCompressed(b []byte) error {
+	p := new(cloudflare.G2)
+	if err := point.DecompressG2(p, b); err != nil {
+		return err
+	}
+	return e.Unmarshal(p.Marshal())
+}
+
+// Struct googleGT implements GT.
+type googleGT struct {
+	p *google.GT
+}
+
+func (e *googleGT) Set(a GT) GT {
+	e.p = a.(*googleGT).p
+	return e
+}
+
+func (e *googleGT) Mul(a, b GT) GT {
+	e.p = new(google.GT).Add(a.(*googleGT).p, b.(*googleGT).p)
+	return e
+}
+
+func (e *googleGT) Exp(a GT, k *big.Int) GT {
+	e.p = new(google.GT).ScalarMult(a.(*googleGT).p, reduce(k, google.Order))
+	return e
+}
+
+func (e *googleGT) IsOne() bool {
+	one := google.Pair(new(google.G1).ScalarBaseMult(new(big.Int)), new(google.G2).ScalarBaseMult(big.NewInt(1)))
+	return bytes.Equal(e.p.Marshal(), one.Marshal())
+}
+
+func (e *googleGT) Equal(b GT) bool {
+	return bytes.Equal(e.p.Marshal(), b.(*googleGT).p.Marshal())
+}
+
+func (e *googleGT) Marshal() []byte {
+	return e.p.Marshal()
+}
@@ -0,0 +1,257 @@
+package curve
+
+// This file implements the scalar types of the backends: frScalar wraps
+// fr.Element for both backends of bn256, and bigScalar reduces big.Int
+// modulo the order of BLS12-381, for which fr has no fixed-width field.
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/zhtluo/libpolycrypto/fr"
+)
+
+// Struct frScalar implements Scalar in the scalar field of bn256
+// for the backend c.
+type frScalar struct {
+	c Curve
+	e fr.Element
+}
+
+func (z *frScalar) Curve() Curve {
+	return z.c
+}
+
+func (z *frScalar) Set(a Scalar) Scalar {
+	z.e.Set(&a.(*frScalar).e)
+	return z
+}
+
+func (z *frScalar) SetZero() Scalar {
+	z.e.SetZero()
+	return z
+}
+
+func (z *frScalar) SetOne() Scalar {
+	z.e.SetOne()
+	return z
+}
+
+func (z *frScalar) SetInt64(v int64) Scalar {
+	z.e.SetInt64(v)
+	return z
+}
+
+func (z *frScalar) SetBigInt(v *big.Int) Scalar {
+	z.e.SetBigInt(v)
+	return z
+}
+
+func (z *frScalar) SetBytesReduce(b []byte) Scalar {
+	z.e.SetBytesReduce(b)
+	return z
+}
+
+func (z *frScalar) SetRandom(r io.Reader) (Scalar, error) {
+	if _, err := z.e.SetRandom(r); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+func (z *frScalar) Add(a, b Scalar) Scalar {
+	z.e.Add(&a.(*frScalar).e, &b.(*frScalar).e)
+	return z
+}
+
+func (z *frScalar) Sub(a, b Scalar) Scalar {
+	z.e.Sub(&a.(*frScalar).e, &b.(*frScalar).e)
+	return z
+}
+
+func (z *frScalar) Neg(a Scalar) Scalar {
+	z.e.Neg(&a.(*frScalar).e)
+	return z
+}
+
+func (z *frScalar) Mul(a, b Scalar) Scalar {
+	z.e.Mul(&a.(*frScalar).e, &b.(*frScalar).e)
+	return z
+}
+
+func (z *frScalar) Inverse(a Scalar) Scalar {
+	z.e.Inverse(&a.(*frScalar).e)
+	return z
+}
+
+func (z *frScalar) Exp(a Scalar, k *big.Int) Scalar {
+	z.e.Exp(&a.(*frScalar).e, k)
+	return z
+}
+
+func (z *frScalar) IsZero() bool {
+	return z.e.IsZero()
+}
+
+func (z *frScalar) IsOne() bool {
+	return z.e.IsOne()
+}
+
+func (z *frScalar) Equal(b Scalar) bool {
+	return z.e.Equal(&b.(*frScalar).e)
+}
+
+func (z *frScalar) BigInt() *big.Int {
+	return z.e.BigInt(nil)
+}
+
+func (z *frScalar) Marshal() []byte {
+	return z.e.Marshal()
+}
+
+func (z *frScalar) Unmarshal(b []byte) error {
+	if err := checkMaxSize(b, ScalarSize); err != nil {
+		return err
+	}
+	_, err := z.e.SetBytes(b)
+	return err
+}
+
+func (z *frScalar) String() string {
+	return z.e.String()
+}
+
+// Struct bigScalar implements Scalar modulo the order n of the curve c
+// as a big.Int in [0, n).
+type bigScalar struct {
+	c Curve
+	n *big.Int
+	v big.Int
+}
+
+func (z *bigScalar) Curve() Curve {
+	return z.c
+}
+
+func (z *bigScalar) Set(a Scalar) Scalar {
+	z.v.Set(&a.(*bigScalar).v)
+	return z
+}
+
+func (z *bigScalar) SetZero() Scalar {
+	z.v.SetInt64(0)
+	return z
+}
+
+func (z *bigScalar) SetOne() Scalar {
+	z.v.SetInt64(1)
+	return z
+}
+
+func (z *bigScalar) SetInt64(v int64) Scalar {
+	z.v.Mod(z.v.SetInt64(v), z.n)
+	return z
+}
+
+func (z *bigScalar) SetBigInt(v *big.Int) Scalar {
+	z.v.Mod(v, z.n)
+	return z
+}
+
+func (z *bigScalar) SetBytesReduce(b []byte) Scalar {
+	z.v.Mod(z.v.SetBytes(b), z.n)
+	return z
+}
+
+func (z *bigScalar) SetRandom(r io.Reader) (Scalar, error) {
+	var b [ScalarSize]byte
+	for {
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, err
+		}
+		// Rejection sampling on the bits of the order keeps the result uniform.
+		b[0] &= 0xff >> uint(8*ScalarSize-z.n.BitLen())
+		if err := z.Unmarshal(b[:]); err == nil {
+			return z, nil
+		}
+	}
+}
+
+func (z *bigScalar) Add(a, b Scalar) Scalar {
+	z.v.Add(&a.(*bigScalar).v, &b.(*bigScalar).v)
+	if z.v.Cmp(z.n) >= 0 {
+		z.v.Sub(&z.v, z.n)
+	}
+	return z
+}
+
+func (z *bigScalar) Sub(a, b Scalar) Scalar {
+	z.v.Sub(&a.(*bigScalar).v, &b.(*bigScalar).v)
+	if z.v.Sign() < 0 {
+		z.v.Add(&z.v, z.n)
+	}
+	return z
+}
+
+func (z *bigScalar) Neg(a Scalar) Scalar {
+	if a.IsZero() {
+		return z.SetZero()
+	}
+	z.v.Sub(z.n, &a.(*bigScalar).v)
+	return z
+}
+
+func (z *bigScalar) Mul(a, b Scalar) Scalar {
+	z.v.Mod(z.v.Mul(&a.(*bigScalar).v, &b.(*bigScalar).v), z.n)
+	return z
+}
+
+func (z *bigScalar) Inverse(a Scalar) Scalar {
+	if a.IsZero() {
+		return z.SetZero()
+	}
+	z.v.ModInverse(&a.(*bigScalar).v, z.n)
+	return z
+}
+
+func (z *bigScalar) Exp(a Scalar, k *big.Int) Scalar {
+	z.v.Exp(&a.(*bigScalar).v, k, z.n)
+	return z
+}
+
+func (z *bigScalar) IsZero() bool {
+	return z.v.Sign() == 0
+}
+
+func (z *bigScalar) IsOne() bool {
+	return z.v.IsInt64() && z.v.Int64() == 1
+}
+
+func (z *bigScalar) Equal(b Scalar) bool {
+	return z.v.Cmp(&b.(*bigScalar).v) == 0
+}
+
+func (z *bigScalar) BigInt() *big.Int {
+	return new(big.Int).Set(&z.v)
+}
+
+func (z *bigScalar) Marshal() []byte {
+	return padBytes(&z.v, ScalarSize)
+}
+
+func (z *bigScalar) Unmarshal(b []byte) error {
+	if err := checkMaxSize(b, ScalarSize); err != nil {
+		return err
+	}
+	v := new(big.Int).SetBytes(b)
+	if v.Cmp(z.n) >= 0 {
+		return errors.New("Scalar is not reduced")
+	}
+	z.v.Set(v)
+	return nil
+}
+
+func (z *bigScalar) String() string {
+	return z.v.String()
+}
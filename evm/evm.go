@@ -0,0 +1,201 @@
+// Package evm encodes the verification of polycommit evaluations as calls to
+// the bn256 precompiled contracts of Ethereum, ecAdd (0x06), ecMul (0x07) and
+// ecPairing (0x08) of EIP-196 and EIP-197, and runs them with the
+// precompiled contracts of go-ethereum's core/vm.
+//
+// The precompiles only offer arithmetic in G1, while the commitments of
+// polycommit are in G2. The check of VerifyEval
+//
+//	e(w, g^alpha - g^i) * e(-g, C - g^res) = 1
+//
+// is thus rearranged into
+//
+//	e(w, g^alpha) * e(-g, C) * e(g^res - w^i, g) = 1
+//
+// so that a contract computes g^res - w^i with ecMul and ecAdd before a
+// single call to ecPairing. The verifier key must be on either backend
+// of bn256.
+package evm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/polycommit"
+)
+
+// Addresses of the precompiled contracts.
+var (
+	EcAdd     = common.BytesToAddress([]byte{6})
+	EcMul     = common.BytesToAddress([]byte{7})
+	EcPairing = common.BytesToAddress([]byte{8})
+)
+
+// Sizes in bytes of the encodings.
+const (
+	ScalarSize = 32
+	G1Size     = 64
+	G2Size     = 128
+)
+
+// Struct Call implements a call to the precompiled contract at Address with the input Input.
+type Call struct {
+	Address common.Address
+	Input   []byte
+}
+
+// Encode the point p of G1 as (x, y), with the point at infinity as (0, 0).
+func EncodeG1(p curve.Point) []byte {
+	return p.Marshal()
+}
+
+// Encode the point p of G2 as (x_imag, x_real, y_imag, y_real), as in EIP-197.
+// The field elements of bn256 store the imaginary part first, so Marshal
+// already follows this order. Points encoded with the real part first
+// need SwapG2 before they are passed to ecPairing.
+func EncodeG2(p curve.Point) []byte {
+	return p.Marshal()
+}
+
+// Swap the real and imaginary parts of both coordinates of the encoded G2 point b,
+// converting between the order of EIP-197 and the order with the real part first.
+func SwapG2(b []byte) ([]byte, error) {
+	if len(b) != G2Size {
+		return nil, errors.New("Encoded G2 point has a wrong size")
+	}
+	const n = G2Size / 4
+	ret := make([]byte, G2Size)
+	copy(ret[0:n], b[n:2*n])
+	copy(ret[n:2*n], b[0:n])
+	copy(ret[2*n:3*n], b[3*n:4*n])
+	copy(ret[3*n:4*n], b[2*n:3*n])
+	return ret, nil
+}
+
+// Encode the scalar k as a 32-byte big-endian integer.
+func EncodeScalar(k *big.Int) []byte {
+	return k.FillBytes(make([]byte, ScalarSize))
+}
+
+// Encode the input of ecAdd computing a + b.
+func EncodeAdd(a, b []byte) []byte {
+	return append(append(make([]byte, 0, 2*G1Size), a...), b...)
+}
+
+// Encode the input of ecMul computing k * a.
+func EncodeMul(a []byte, k *big.Int) []byte {
+	return append(append(make([]byte, 0, G1Size+ScalarSize), a...), EncodeScalar(k)...)
+}
+
+// Encode the input of ecPairing checking that the product of e(a[i], b[i]) is one.
+func EncodePairing(a, b []curve.Point) []byte {
+	ret := make([]byte, 0, len(a)*(G1Size+G2Size))
+	for i := range a {
+		ret = append(ret, EncodeG1(a[i])...)
+		ret = append(ret, EncodeG2(b[i])...)
+	}
+	return ret
+}
+
+// Check that the verifier key is on either backend of bn256,
+// the only curve of the precompiled contracts.
+func checkCurve(vk *polycommit.VerifierKey) error {
+	if vk.Curve != curve.BN256 && vk.Curve != curve.BN256Google {
+		return errors.New("Verifier key is not on bn256")
+	}
+	return nil
+}
+
+// Return -i modulo the order as a scalar for ecMul.
+func negScalar(i curve.Scalar) *big.Int {
+	return i.Curve().NewScalar().Neg(i).BigInt()
+}
+
+// Encode the inputs of the two calls to ecMul of the evaluation,
+// computing w^-i and g^res.
+func encodeEvalMuls(vk *polycommit.VerifierKey, i curve.Scalar, res curve.Scalar, g1 curve.Point) ([]byte, []byte) {
+	return EncodeMul(EncodeG1(g1), negScalar(i)), EncodeMul(EncodeG1(vk.G1), res.BigInt())
+}
+
+// Encode the input of ecPairing of the evaluation, given the sum
+// g^res - w^i computed by ecAdd.
+func encodeEvalPairing(vk *polycommit.VerifierKey, g2 curve.Point, g1 curve.Point, sum curve.Point) []byte {
+	return EncodePairing(
+		[]curve.Point{g1, vk.Curve.NewG1().Neg(vk.G1), sum},
+		[]curve.Point{vk.G2Alpha, g2, vk.G2})
+}
+
+// Encode the calls verifying the evaluation res of the polynomial at i,
+// with the commitment g2 and the witness g1, under the verifier key vk:
+// ecMul computing w^-i, ecMul computing g^res, ecAdd computing their sum,
+// and ecPairing. The inputs of ecAdd and ecPairing hold the outputs the
+// earlier calls return.
+func EncodeEval(vk *polycommit.VerifierKey, g2 curve.Point, i curve.Scalar, res curve.Scalar, g1 curve.Point) ([]Call, error) {
+	if err := checkCurve(vk); err != nil {
+		return nil, err
+	}
+	c := vk.Curve
+	wi := c.NewG1().ScalarMult(g1, negScalar(i))
+	gres := c.NewG1().ScalarMult(vk.G1, res.BigInt())
+	sum := c.NewG1().Add(wi, gres)
+	mulW, mulG := encodeEvalMuls(vk, i, res, g1)
+	return []Call{
+		{EcMul, mulW},
+		{EcMul, mulG},
+		{EcAdd, EncodeAdd(EncodeG1(wi), EncodeG1(gres))},
+		{EcPairing, encodeEvalPairing(vk, g2, g1, sum)},
+	}, nil
+}
+
+// Run the precompiled contract at address with the input, as of Istanbul,
+// returning its output and the gas it used.
+func Run(address common.Address, input []byte) ([]byte, uint64, error) {
+	p, ok := vm.PrecompiledContractsIstanbul[address]
+	if !ok {
+		return nil, 0, errors.New("No precompiled contract at the address")
+	}
+	gas := p.RequiredGas(input)
+	ret, _, err := vm.RunPrecompiledContract(p, input, gas)
+	return ret, gas, err
+}
+
+// Verify the evaluation of the polynomial with the commitment g2 and the
+// witness g1 through the precompiled contracts, as a contract would,
+// feeding the outputs of ecMul and ecAdd into the later calls.
+// Return the result and the gas used by the precompiled contracts.
+func VerifyEval(vk *polycommit.VerifierKey, g2 curve.Point, i curve.Scalar, res curve.Scalar, g1 curve.Point) (bool, uint64, error) {
+	if err := checkCurve(vk); err != nil {
+		return false, 0, err
+	}
+	var total uint64
+	run := func(address common.Address, input []byte) ([]byte, error) {
+		ret, gas, err := Run(address, input)
+		total += gas
+		return ret, err
+	}
+	mulW, mulG := encodeEvalMuls(vk, i, res, g1)
+	wi, err := run(EcMul, mulW)
+	if err != nil {
+		return false, total, err
+	}
+	gres, err := run(EcMul, mulG)
+	if err != nil {
+		return false, total, err
+	}
+	b, err := run(EcAdd, EncodeAdd(wi, gres))
+	if err != nil {
+		return false, total, err
+	}
+	sum := vk.Curve.NewG1()
+	if err = sum.Unmarshal(b); err != nil {
+		return false, total, err
+	}
+	ret, err := run(EcPairing, encodeEvalPairing(vk, g2, g1, sum))
+	if err != nil {
+		return false, total, err
+	}
+	return new(big.Int).SetBytes(ret).Cmp(big.NewInt(1)) == 0, total, nil
+}
@@ -0,0 +1,169 @@
+package evm
+
+import (
+	"testing"
+
+	"bytes"
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/polycommit"
+	"math/big"
+)
+
+const (
+	deg = 16
+)
+
+// The precompiled contracts only implement bn256.
+var curves = []curve.Curve{curve.BN256, curve.BN256Google}
+
+func generateEval(t testing.TB, c curve.Curve) (*polycommit.VerifierKey, curve.Point, curve.Scalar, curve.Scalar, curve.Point) {
+	var pk polycommit.Pk
+	if err := pk.Setup(c, rand.Reader, deg); err != nil {
+		t.Fatal(err.Error())
+	}
+	vk, err := pk.VerifierKey()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	poly := curve.NewScalars(c, deg)
+	for i := range poly {
+		poly[i].SetRandom(rand.Reader)
+	}
+	g2, err := pk.Commit(poly)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	i, _ := c.NewScalar().SetRandom(rand.Reader)
+	res, g1, err := pk.CreateWitness(poly, i)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return vk, g2, i, res, g1
+}
+
+func TestEncodeG2(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			// The generator of G2 as given in EIP-197.
+			var coords [4]*big.Int
+			for i, s := range []string{
+				"11559732032986387107991004021392285783925812861821192530917403151452391805634",
+				"10857046999023057135944570762232829481370756359578518086990519993285655852781",
+				"4082367875863433681332203403145435568316851327593401208105741076214120093531",
+				"8495653923123431417604973247489272438418190587263600148770280649306958101930",
+			} {
+				coords[i], _ = new(big.Int).SetString(s, 10)
+			}
+			var expected []byte
+			for _, v := range coords {
+				expected = append(expected, EncodeScalar(v)...)
+			}
+			b := EncodeG2(c.NewG2().ScalarBaseMult(big.NewInt(1)))
+			if !bytes.Equal(b, expected) {
+				t.Error("EncodeG2 failed. Not in the order of EIP-197.")
+			}
+			s, err := SwapG2(b)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if !bytes.Equal(s[:ScalarSize], EncodeScalar(coords[1])) || !bytes.Equal(s[2*ScalarSize:3*ScalarSize], EncodeScalar(coords[3])) {
+				t.Error("SwapG2 failed. Real parts are not first.")
+			}
+			if s, _ = SwapG2(s); !bytes.Equal(s, b) {
+				t.Error("SwapG2 failed. Not an involution.")
+			}
+			if _, err := SwapG2(b[1:]); err == nil {
+				t.Error("SwapG2 failed. Accepted a wrong size.")
+			}
+		})
+	}
+}
+
+func TestEncodeEval(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			vk, g2, i, res, g1 := generateEval(t, c)
+			calls, err := EncodeEval(vk, g2, i, res, g1)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			var out [][]byte
+			for _, call := range calls {
+				ret, _, err := Run(call.Address, call.Input)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				out = append(out, ret)
+			}
+			if !bytes.Equal(calls[2].Input, EncodeAdd(out[0], out[1])) {
+				t.Error("EncodeEval failed. Input of ecAdd does not match the outputs of ecMul.")
+			}
+			if !bytes.Equal(calls[3].Input[2*(G1Size+G2Size):][:G1Size], out[2]) {
+				t.Error("EncodeEval failed. Input of ecPairing does not match the output of ecAdd.")
+			}
+			if new(big.Int).SetBytes(out[3]).Cmp(big.NewInt(1)) != 0 {
+				t.Error("EncodeEval failed. ecPairing returned false.")
+			}
+		})
+	}
+	vk, g2, i, res, g1 := generateEval(t, curve.BLS12381)
+	if _, err := EncodeEval(vk, g2, i, res, g1); err == nil {
+		t.Error("EncodeEval accepted a verifier key on another curve.")
+	}
+}
+
+func TestVerifyEval(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			vk, g2, i, res, g1 := generateEval(t, c)
+			ok, gas, err := VerifyEval(vk, g2, i, res, g1)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if ok != true || ok != vk.VerifyEval(g2, i, res, g1) {
+				t.Error("VerifyEval failed, expected: true.")
+			}
+			// 2 ecMul, 1 ecAdd and 1 ecPairing of 3 pairs as of Istanbul.
+			if gas != 2*6000+150+45000+3*34000 {
+				t.Error("VerifyEval failed. Wrong gas.")
+			}
+			wrong := c.NewScalar().Add(res, c.NewScalar().SetOne())
+			ok, _, err = VerifyEval(vk, g2, i, wrong, g1)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if ok != false || ok != vk.VerifyEval(g2, i, wrong, g1) {
+				t.Error("VerifyEval failed, expected: false.")
+			}
+			ok, _, err = VerifyEval(vk, g2, wrong, res, g1)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if ok != false || ok != vk.VerifyEval(g2, wrong, res, g1) {
+				t.Error("VerifyEval failed, expected: false.")
+			}
+		})
+	}
+	vk, g2, i, res, g1 := generateEval(t, curve.BLS12381)
+	if _, _, err := VerifyEval(vk, g2, i, res, g1); err == nil {
+		t.Error("VerifyEval accepted a verifier key on another curve.")
+	}
+}
+
+func TestRun(t *testing.T) {
+	if _, _, err := Run(EcAdd, EncodeAdd(make([]byte, G1Size), []byte{1})); err == nil {
+		t.Error("Run failed. Accepted a point off the curve.")
+	}
+	if _, _, err := Run(EcAdd, nil); err != nil {
+		t.Error("Run failed. Rejected the padded point at infinity.")
+	}
+}
+
+func BenchmarkVerifyEval(b *testing.B) {
+	vk, g2, i, res, g1 := generateEval(b, curve.BN256)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		VerifyEval(vk, g2, i, res, g1)
+	}
+}
@@ -5,120 +5,377 @@
 package evss
 
 import (
-	"crypto/rand"
+	"context"
+	"errors"
 	"io"
 	"math/big"
 
-	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/point"
+	"github.com/zhtluo/libpolycrypto/poly"
 	"github.com/zhtluo/libpolycrypto/polycommit"
 	pb "github.com/zhtluo/libpolycrypto/proto"
 	"google.golang.org/protobuf/proto"
 )
 
-var (
-	MaxInt = bn256.Order
+// Mode selects the group the commitment to the secret lives in.
+type Mode uint32
+
+const (
+	// Commit in G2 with polycommit.Pk.
+	ModeG2 Mode = iota
+	// Commit in G1 with polycommit.G1Pk, which gives smaller commitments
+	// and a faster setup.
+	ModeG1
 )
 
 // Struct PublicInfo implements the public information available at the start of the phase.
+// Pk and Commit are used in ModeG2, G1Pk and G1Commit in ModeG1.
+// DegreeProof proves that the secret has fewer than Threshold coefficients.
 type PublicInfo struct {
-	Pk     polycommit.Pk
-	Commit bn256.G2
+	Mode        Mode
+	Pk          polycommit.Pk
+	Commit      curve.Point
+	G1Pk        polycommit.G1Pk
+	G1Commit    curve.Point
+	Threshold   int
+	DegreeProof curve.Point
+}
+
+// Struct VerifierInfo implements the public information needed to verify shares.
+// Commit is used in ModeG2 and G1Commit in ModeG1.
+// DegreeProof proves that the secret has fewer than Threshold coefficients,
+// and is checked against a degree key of the trusted public key.
+type VerifierInfo struct {
+	Mode        Mode
+	Vk          polycommit.VerifierKey
+	Commit      curve.Point
+	G1Commit    curve.Point
+	Threshold   int
+	DegreeProof curve.Point
 }
 
 // Struct Secret implements the secret the dealer wishes to share.
 type Secret struct {
-	Poly []big.Int
+	Poly poly.Polynomial
 }
 
 // Struct Secret implements the share of each node.
 type Share struct {
-	Index   big.Int
-	Result  big.Int
-	Witness bn256.G1
-}
-
-// Generate a secret with the constant term specified.
-func GenerateSecret(r io.Reader, constant *big.Int, degree int) (*Secret, error) {
-	s := new(Secret)
-	s.Poly = make([]big.Int, degree)
-	s.Poly[0] = *constant
-	for i := 1; i < degree; i++ {
-		r, err := rand.Int(r, bn256.Order)
+	Index   curve.Scalar
+	Result  curve.Scalar
+	Witness curve.Point
+}
+
+// Struct SecretProof implements the proof of the dealer that it knows the
+// secret behind the commitment, without revealing it.
+// Proof is used in ModeG2 and G1Proof in ModeG1.
+type SecretProof struct {
+	Proof   polycommit.OpeningProof
+	G1Proof polycommit.G1OpeningProof
+}
+
+// Generate a secret with the constant term specified, over the curve of the constant.
+func GenerateSecret(r io.Reader, constant curve.Scalar, degree int) (*Secret, error) {
+	if degree < 1 {
+		return nil, errors.New("Degree of the secret is less than 1")
+	}
+	p, err := poly.Random(constant.Curve(), r, degree)
+	if err != nil {
+		return nil, err
+	}
+	p[0].Set(constant)
+	return &Secret{Poly: p}, nil
+}
+
+// Generate a secret over the curve c with the constant term specified modulo the order of c.
+func GenerateSecretBig(c curve.Curve, r io.Reader, constant *big.Int, degree int) (*Secret, error) {
+	return GenerateSecret(r, c.NewScalar().SetBigInt(constant), degree)
+}
+
+// Generate public information with the secret, committing in G2.
+func GeneratePublicInfo(r io.Reader, s *Secret) (*PublicInfo, error) {
+	return GeneratePublicInfoMode(r, s, ModeG2)
+}
+
+// Generate public information with the secret, committing in the group of mode
+// with a public key of the degree of the secret, which is also the threshold.
+func GeneratePublicInfoMode(r io.Reader, s *Secret, mode Mode) (*PublicInfo, error) {
+	return GeneratePublicInfoContext(context.Background(), r, s, mode, len(s.Poly), len(s.Poly), 1)
+}
+
+// Generate public information with the secret, committing in the group of mode
+// with a public key of degree n, and proving that the secret has fewer than
+// threshold coefficients, the threshold the parties agreed on.
+func GeneratePublicInfoThreshold(r io.Reader, s *Secret, mode Mode, n int, threshold int) (*PublicInfo, error) {
+	return GeneratePublicInfoContext(context.Background(), r, s, mode, n, threshold, 1)
+}
+
+// Generate public information with the secret as GeneratePublicInfoThreshold does,
+// splitting the work among workers goroutines and stopping once ctx is done.
+// The public key is set up over the curve of the secret.
+func GeneratePublicInfoContext(ctx context.Context, r io.Reader, s *Secret, mode Mode, n int, threshold int, workers int) (*PublicInfo, error) {
+	if threshold < 1 || n < threshold {
+		return nil, errors.New("Threshold is not between 1 and the degree of the public key")
+	}
+	if len(s.Poly) < 1 {
+		return nil, errors.New("Secret is empty")
+	}
+	if len(s.Poly) > threshold {
+		return nil, errors.New("Secret has more coefficients than the threshold")
+	}
+	c := s.Poly[0].Curve()
+	pi := new(PublicInfo)
+	pi.Mode = mode
+	var err error
+	switch mode {
+	case ModeG2:
+		if err = pi.Pk.SetupContext(ctx, c, r, n, workers); err != nil {
+			return nil, err
+		}
+		if pi.Commit, err = pi.Pk.CommitContext(ctx, s.Poly, workers); err != nil {
+			return nil, err
+		}
+		if pi.DegreeProof, err = pi.Pk.CreateDegreeProofContext(ctx, s.Poly, threshold, workers); err != nil {
+			return nil, err
+		}
+	case ModeG1:
+		if err = pi.G1Pk.SetupContext(ctx, c, r, n, workers, threshold); err != nil {
+			return nil, err
+		}
+		if pi.G1Commit, err = pi.G1Pk.CommitContext(ctx, s.Poly, workers); err != nil {
+			return nil, err
+		}
+		if pi.DegreeProof, err = pi.G1Pk.CreateDegreeProofContext(ctx, s.Poly, threshold, workers); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("Unknown commitment mode")
+	}
+	pi.Threshold = threshold
+	return pi, nil
+}
+
+// Return the curve of the public key in the public information.
+func (pi *PublicInfo) curve() (curve.Curve, error) {
+	switch pi.Mode {
+	case ModeG2:
+		return pi.Pk.Curve, nil
+	case ModeG1:
+		return pi.G1Pk.Curve, nil
+	}
+	return nil, errors.New("Unknown commitment mode")
+}
+
+// Prove the knowledge of the secret behind the commitment in the public information,
+// with the randomness in reader r.
+func ProveSecret(r io.Reader, pi *PublicInfo, s *Secret) (*SecretProof, error) {
+	pr := new(SecretProof)
+	switch pi.Mode {
+	case ModeG2:
+		p, err := pi.Pk.ProveOpening(r, s.Poly, pi.Commit)
+		if err != nil {
+			return nil, err
+		}
+		pr.Proof = *p
+	case ModeG1:
+		p, err := pi.G1Pk.ProveOpening(r, s.Poly, pi.G1Commit)
 		if err != nil {
 			return nil, err
 		}
-		s.Poly[i] = *r
+		pr.G1Proof = *p
+	default:
+		return nil, errors.New("Unknown commitment mode")
 	}
-	return s, nil
+	return pr, nil
 }
 
-// Generate public information with the secret.
-func GeneratePublicInfo(r io.Reader, s *Secret) (*PublicInfo, error) {
-	pi := new(PublicInfo)
-	err := pi.Pk.Setup(r, len(s.Poly))
+// Verify that the dealer knows the secret behind the commitment in the public information.
+func VerifySecret(pi *PublicInfo, pr *SecretProof) bool {
+	switch pi.Mode {
+	case ModeG2:
+		return pi.Pk.VerifyOpening(pi.Commit, &pr.Proof)
+	case ModeG1:
+		return pi.G1Pk.VerifyOpening(pi.G1Commit, &pr.G1Proof)
+	}
+	return false
+}
+
+// Generate a share based on the information and the secret.
+func GenerateShare(pi *PublicInfo, s *Secret, index curve.Scalar) (*Share, error) {
+	sh := new(Share)
+	sh.Index = index.Curve().NewScalar().Set(index)
+	var err error
+	switch pi.Mode {
+	case ModeG2:
+		sh.Result, sh.Witness, err = pi.Pk.CreateWitness(s.Poly, sh.Index)
+	case ModeG1:
+		sh.Result, sh.Witness, err = pi.G1Pk.CreateWitness(s.Poly, sh.Index)
+	default:
+		err = errors.New("Unknown commitment mode")
+	}
 	if err != nil {
 		return nil, err
 	}
-	c, err := pi.Pk.Commit(s.Poly)
-	pi.Commit = *c
+	return sh, nil
+}
+
+// Generate a share at the index specified modulo the order of the curve.
+func GenerateShareBig(pi *PublicInfo, s *Secret, index *big.Int) (*Share, error) {
+	c, err := pi.curve()
 	if err != nil {
 		return nil, err
 	}
-	return pi, nil
+	return GenerateShare(pi, s, c.NewScalar().SetBigInt(index))
 }
 
-// Generate a share based on the information and the secret.
-func GenerateShare(pi *PublicInfo, s *Secret, index *big.Int) (*Share, error) {
-	sh := new(Share)
-	sh.Index = *index
-	r, w, err := pi.Pk.CreateWitness(s.Poly, &sh.Index)
+// Extract the information needed to verify shares from the public information.
+func (pi *PublicInfo) VerifierInfo() (*VerifierInfo, error) {
+	vi := new(VerifierInfo)
+	vi.Mode = pi.Mode
+	var vk *polycommit.VerifierKey
+	var err error
+	switch pi.Mode {
+	case ModeG2:
+		vk, err = pi.Pk.VerifierKey()
+		vi.Commit = pi.Commit
+	case ModeG1:
+		vk, err = pi.G1Pk.VerifierKey()
+		vi.G1Commit = pi.G1Commit
+	default:
+		err = errors.New("Unknown commitment mode")
+	}
 	if err != nil {
 		return nil, err
 	}
-	sh.Result, sh.Witness = *r, *w
-	return sh, nil
+	vi.Vk = *vk
+	if pi.Threshold > 0 {
+		vi.Threshold = pi.Threshold
+		vi.DegreeProof = pi.DegreeProof
+	}
+	return vi, nil
 }
 
-// Verify the received share with the public information.
-func VerifyShare(pi *PublicInfo, sh *Share) bool {
-	return pi.Pk.VerifyEval(&pi.Commit, &sh.Index, &sh.Result, &sh.Witness)
+// Extract the degree key for the threshold from the public key in the public information.
+// The public key must be trusted, and threshold must be the one the parties
+// agreed on rather than the one the dealer claims.
+func (pi *PublicInfo) DegreeKey(threshold int) (*polycommit.DegreeKey, error) {
+	switch pi.Mode {
+	case ModeG2:
+		return pi.Pk.DegreeKey(threshold)
+	case ModeG1:
+		return pi.G1Pk.DegreeKey(threshold)
+	}
+	return nil, errors.New("Unknown commitment mode")
 }
 
-// Reconstruct the constant term of the secret with shares.
-func ReconstructSecret(shs []Share) *big.Int {
-	inverse := make([]big.Int, len(shs))
-	for i := range inverse {
-		inverse[i].ModInverse(&shs[i].Index, bn256.Order)
+// Verify the received share with the verifier information.
+func VerifyShare(vi *VerifierInfo, sh *Share) bool {
+	if sh.Index.Curve() != vi.Vk.Curve || sh.Result.Curve() != vi.Vk.Curve {
+		return false
 	}
-	constant := big.NewInt(0)
-	// Order + 1
-	orders1 := new(big.Int).Add(bn256.Order, big.NewInt(1))
-	for i := range shs {
-		partial := new(big.Int).ModInverse(&shs[i].Result, bn256.Order)
-		for j := range shs {
-			if i != j {
-				// p = p * (1 - x_i * x_j^-1)
-				term := new(big.Int).Mul(&shs[i].Index, &inverse[j])
-				term.Mod(term, bn256.Order)
-				partial.Mul(partial, new(big.Int).Sub(orders1, term))
-				partial.Mod(partial, bn256.Order)
-			}
+	switch vi.Mode {
+	case ModeG2:
+		return vi.Vk.VerifyEval(vi.Commit, sh.Index, sh.Result, sh.Witness)
+	case ModeG1:
+		return vi.Vk.VerifyEvalG1(vi.G1Commit, sh.Index, sh.Result, sh.Witness)
+	}
+	return false
+}
+
+// Verify the received share as VerifyShare, and also require the dealer's
+// proof that the secret has fewer than dk.Degree coefficients, so that any
+// dk.Degree shares determine it. The degree key dk of the agreed threshold
+// must be derived from the trusted public key, e.g. by PublicInfo.DegreeKey,
+// and never be taken from the dealer.
+func VerifyShareDegree(vi *VerifierInfo, dk *polycommit.DegreeKey, sh *Share) bool {
+	if dk.Degree < 1 || vi.Threshold != dk.Degree || dk.Curve != vi.Vk.Curve || !dk.G2.Equal(vi.Vk.G2) {
+		return false
+	}
+	var ok bool
+	switch vi.Mode {
+	case ModeG2:
+		ok = dk.VerifyDegree(vi.Commit, vi.DegreeProof)
+	case ModeG1:
+		ok = dk.VerifyDegreeG1(vi.G1Commit, vi.DegreeProof)
+	}
+	return ok && VerifyShare(vi, sh)
+}
+
+// Verify all the shares at once with a single multi-pairing and return the
+// indices of the invalid ones, which is empty if every share is valid.
+func VerifyShares(vi *VerifierInfo, shs []Share) []int {
+	switch vi.Mode {
+	case ModeG2:
+		proofs := make([]polycommit.EvalProof, len(shs))
+		for k := range shs {
+			proofs[k] = polycommit.EvalProof{Commit: vi.Commit, I: shs[k].Index, Res: shs[k].Result, Witness: shs[k].Witness}
+		}
+		return vi.Vk.FindInvalidEvals(proofs)
+	case ModeG1:
+		proofs := make([]polycommit.G1EvalProof, len(shs))
+		for k := range shs {
+			proofs[k] = polycommit.G1EvalProof{Commit: vi.G1Commit, I: shs[k].Index, Res: shs[k].Result, Witness: shs[k].Witness}
 		}
-		partial.ModInverse(partial, bn256.Order)
-		constant.Mod(constant.Add(constant, partial), bn256.Order)
+		return vi.Vk.FindInvalidEvalsG1(proofs)
+	}
+	bad := make([]int, len(shs))
+	for k := range bad {
+		bad[k] = k
+	}
+	return bad
+}
+
+// Reconstruct the constant term of the secret over the curve c with shares.
+// The indices of the shares must be distinct.
+func ReconstructSecret(c curve.Curve, shs []Share) curve.Scalar {
+	// p(0) = sum_i y_i * prod_(j != i) x_j / (x_j - x_i)
+	xs := make([]curve.Scalar, len(shs))
+	for i := range shs {
+		xs[i] = shs[i].Index
+	}
+	lambda := poly.LagrangeCoefficients(xs, c.NewScalar())
+	constant := c.NewScalar()
+	term := c.NewScalar()
+	for i := range shs {
+		constant.Add(constant, term.Mul(shs[i].Result, lambda[i]))
 	}
 	return constant
 }
 
+// Reconstruct the constant term of the secret over the curve c with shares as a big.Int.
+func ReconstructSecretBig(c curve.Curve, shs []Share) *big.Int {
+	return ReconstructSecret(c, shs).BigInt()
+}
+
 // Serialize the public infomation.
 func (pi *PublicInfo) Marshal() ([]byte, error) {
+	return pi.MarshalFormat(point.Uncompressed)
+}
+
+// Serialize the public infomation with the points encoded in the format f.
+func (pi *PublicInfo) MarshalFormat(f point.Format) ([]byte, error) {
 	var sPi pb.PublicInfo
 	var err error
-	sPi.Pk, err = pi.Pk.Marshal()
+	sPi.Mode = uint32(pi.Mode)
+	sPi.Format = uint32(f)
+	switch pi.Mode {
+	case ModeG2:
+		sPi.Pk, err = pi.Pk.MarshalFormat(f)
+		sPi.Commit = curve.Encode(pi.Commit, f)
+	case ModeG1:
+		sPi.Pk, err = pi.G1Pk.MarshalFormat(f)
+		sPi.Commit = curve.Encode(pi.G1Commit, f)
+	default:
+		err = errors.New("Unknown commitment mode")
+	}
 	if err != nil {
 		return nil, err
 	}
-	sPi.Commit = pi.Commit.Marshal()
+	if pi.Threshold > 0 {
+		sPi.Threshold = uint32(pi.Threshold)
+		sPi.DegreeProof = curve.Encode(pi.DegreeProof, f)
+	}
 	return proto.Marshal(&sPi)
 }
 
@@ -129,23 +386,116 @@ func (pi *PublicInfo) Unmarshal(b []byte) error {
 	if err != nil {
 		return err
 	}
-	if pi == nil {
-		pi = new(PublicInfo)
+	pi.Mode = Mode(sPi.Mode)
+	f := point.Format(sPi.Format)
+	var c curve.Curve
+	switch pi.Mode {
+	case ModeG2:
+		if err = pi.Pk.Unmarshal(sPi.Pk); err != nil {
+			return err
+		}
+		c = pi.Pk.Curve
+		pi.Commit = c.NewG2()
+		err = curve.Decode(pi.Commit, sPi.Commit, f)
+	case ModeG1:
+		if err = pi.G1Pk.Unmarshal(sPi.Pk); err != nil {
+			return err
+		}
+		c = pi.G1Pk.Curve
+		pi.G1Commit = c.NewG1()
+		err = curve.Decode(pi.G1Commit, sPi.Commit, f)
+	default:
+		err = errors.New("Unknown commitment mode")
 	}
-	err = pi.Pk.Unmarshal(sPi.Pk)
 	if err != nil {
 		return err
 	}
-	_, err = pi.Commit.Unmarshal(sPi.Commit)
+	pi.Threshold = int(sPi.Threshold)
+	if pi.Threshold > 0 {
+		pi.DegreeProof = c.NewG1()
+		err = curve.Decode(pi.DegreeProof, sPi.DegreeProof, f)
+	}
+	return err
+}
+
+// Serialize the verifier infomation.
+func (vi *VerifierInfo) Marshal() ([]byte, error) {
+	return vi.MarshalFormat(point.Uncompressed)
+}
+
+// Serialize the verifier infomation with the points encoded in the format f.
+func (vi *VerifierInfo) MarshalFormat(f point.Format) ([]byte, error) {
+	var sVi pb.VerifierInfo
+	var err error
+	sVi.Mode = uint32(vi.Mode)
+	sVi.Format = uint32(f)
+	sVi.Vk, err = vi.Vk.MarshalFormat(f)
+	if err != nil {
+		return nil, err
+	}
+	switch vi.Mode {
+	case ModeG2:
+		sVi.Commit = curve.Encode(vi.Commit, f)
+	case ModeG1:
+		sVi.Commit = curve.Encode(vi.G1Commit, f)
+	default:
+		return nil, errors.New("Unknown commitment mode")
+	}
+	if vi.Threshold > 0 {
+		sVi.Threshold = uint32(vi.Threshold)
+		sVi.DegreeProof = curve.Encode(vi.DegreeProof, f)
+	}
+	return proto.Marshal(&sVi)
+}
+
+// Deserialize the verifier infomation.
+func (vi *VerifierInfo) Unmarshal(b []byte) error {
+	var sVi pb.VerifierInfo
+	err := proto.Unmarshal(b, &sVi)
+	if err != nil {
+		return err
+	}
+	err = vi.Vk.Unmarshal(sVi.Vk)
+	if err != nil {
+		return err
+	}
+	vi.Mode = Mode(sVi.Mode)
+	f := point.Format(sVi.Format)
+	c := vi.Vk.Curve
+	switch vi.Mode {
+	case ModeG2:
+		vi.Commit = c.NewG2()
+		err = curve.Decode(vi.Commit, sVi.Commit, f)
+	case ModeG1:
+		vi.G1Commit = c.NewG1()
+		err = curve.Decode(vi.G1Commit, sVi.Commit, f)
+	default:
+		err = errors.New("Unknown commitment mode")
+	}
+	if err != nil {
+		return err
+	}
+	vi.Threshold = int(sVi.Threshold)
+	if vi.Threshold > 0 {
+		vi.DegreeProof = c.NewG1()
+		err = curve.Decode(vi.DegreeProof, sVi.DegreeProof, f)
+	}
 	return err
 }
 
 // Serialize the share.
 func (sh *Share) Marshal() ([]byte, error) {
+	return sh.MarshalFormat(point.Uncompressed)
+}
+
+// Serialize the share with the witness encoded in the format f.
+func (sh *Share) MarshalFormat(f point.Format) ([]byte, error) {
 	var sSh pb.Share
-	sSh.Index = sh.Index.Bytes()
-	sSh.Result = sh.Result.Bytes()
-	sSh.Witness = sh.Witness.Marshal()
+	sSh.Index = sh.Index.Marshal()
+	sSh.Result = sh.Result.Marshal()
+	sSh.Witness = curve.Encode(sh.Witness, f)
+	sSh.Format = uint32(f)
+	sSh.Curve = sh.Index.Curve().Name()
 	return proto.Marshal(&sSh)
 }
 
@@ -156,11 +506,16 @@ func (sh *Share) Unmarshal(b []byte) error {
 	if err != nil {
 		return err
 	}
-	if sh == nil {
-		sh = new(Share)
+	c, err := curve.ByName(sSh.Curve)
+	if err != nil {
+		return err
 	}
-	sh.Index.SetBytes(sSh.Index)
-	sh.Result.SetBytes(sSh.Result)
-	_, err = sh.Witness.Unmarshal(sSh.Witness)
-	return err
+	sh.Index, sh.Result, sh.Witness = c.NewScalar(), c.NewScalar(), c.NewG1()
+	if err = sh.Index.Unmarshal(sSh.Index); err != nil {
+		return err
+	}
+	if err = sh.Result.Unmarshal(sSh.Result); err != nil {
+		return err
+	}
+	return curve.Decode(sh.Witness, sSh.Witness, point.Format(sSh.Format))
 }
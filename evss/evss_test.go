@@ -3,8 +3,12 @@ package evss
 import (
 	"testing"
 
+	"bytes"
+	"context"
 	"crypto/rand"
-	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"encoding/hex"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/point"
 	"math/big"
 )
 
@@ -12,112 +16,566 @@ const (
 	deg = 16
 )
 
+var curves = []curve.Curve{curve.BN256, curve.BN256Google, curve.BLS12381}
+
 func TestVerifyShare(t *testing.T) {
-	constant, err := rand.Int(rand.Reader, bn256.Order)
-	if err != nil {
-		t.Error(err.Error())
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			constant, err := c.NewScalar().SetRandom(rand.Reader)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			s, err := GenerateSecret(rand.Reader, constant, deg)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			pi, err := GeneratePublicInfo(rand.Reader, s)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			vi, err := pi.VerifierInfo()
+			if err != nil {
+				t.Error(err.Error())
+			}
+			for i := 0; i < deg; i++ {
+				index := curve.NewScalar(c, int64(i))
+				sh, err := GenerateShare(pi, s, index)
+				if err != nil {
+					t.Error(err.Error())
+				}
+				flag := VerifyShare(vi, sh)
+				if !flag {
+					t.Error("VerifyShare failed. Expected: true")
+				}
+				sh.Result.SetRandom(rand.Reader)
+				flag = VerifyShare(vi, sh)
+				if flag {
+					t.Error("VerifyShare failed. Expected: false")
+				}
+			}
+		})
 	}
-	s, err := GenerateSecret(rand.Reader, constant, deg)
-	if err != nil {
-		t.Error(err.Error())
+}
+
+func TestVerifyShareDegree(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			constant, err := c.NewScalar().SetRandom(rand.Reader)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			s, err := GenerateSecret(rand.Reader, constant, deg)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			wide, err := GenerateSecret(rand.Reader, constant, 2*deg)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if _, err = GeneratePublicInfoThreshold(rand.Reader, wide, ModeG2, 2*deg, deg); err == nil {
+				t.Error("GeneratePublicInfoThreshold accepted a secret beyond the threshold.")
+			}
+			if _, err = GeneratePublicInfoThreshold(rand.Reader, s, ModeG2, deg, 2*deg); err == nil {
+				t.Error("GeneratePublicInfoThreshold accepted a threshold beyond the public key.")
+			}
+			for _, mode := range []Mode{ModeG2, ModeG1} {
+				// The public key is larger than the agreed threshold.
+				pi, err := GeneratePublicInfoThreshold(rand.Reader, s, mode, 2*deg, deg)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				b, err := pi.Marshal()
+				if err != nil {
+					t.Error(err.Error())
+				}
+				var rPi PublicInfo
+				if err = rPi.Unmarshal(b); err != nil {
+					t.Error(err.Error())
+				}
+				vi, err := rPi.VerifierInfo()
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				b, err = vi.Marshal()
+				if err != nil {
+					t.Error(err.Error())
+				}
+				var rVi VerifierInfo
+				if err = rVi.Unmarshal(b); err != nil {
+					t.Error(err.Error())
+				}
+				dk, err := rPi.DegreeKey(deg)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				index := curve.NewScalar(c, 1)
+				sh, err := GenerateShare(pi, s, index)
+				if err != nil {
+					t.Error(err.Error())
+				}
+				if !VerifyShareDegree(&rVi, dk, sh) {
+					t.Error("VerifyShareDegree failed. Expected: true")
+				}
+				if mode == ModeG2 {
+					loose, err := pi.DegreeKey(2 * deg)
+					if err != nil {
+						t.Fatal(err.Error())
+					}
+					if VerifyShareDegree(&rVi, loose, sh) {
+						t.Error("VerifyShareDegree failed with another threshold. Expected: false")
+					}
+				}
+				// The dealer commits to a secret beyond the threshold with the same
+				// public key, proving the bound the public key allows instead.
+				switch mode {
+				case ModeG2:
+					com, err := pi.Pk.Commit(wide.Poly)
+					if err != nil {
+						t.Fatal(err.Error())
+					}
+					p, err := pi.Pk.CreateDegreeProof(wide.Poly, 2*deg)
+					if err != nil {
+						t.Fatal(err.Error())
+					}
+					pi.Commit, pi.DegreeProof = com, p
+				case ModeG1:
+					com, err := pi.G1Pk.Commit(wide.Poly)
+					if err != nil {
+						t.Fatal(err.Error())
+					}
+					p, err := pi.G1Pk.CreateDegreeProof(wide.Poly, 2*deg)
+					if err != nil {
+						t.Fatal(err.Error())
+					}
+					pi.G1Commit, pi.DegreeProof = com, p
+				}
+				vi, err = pi.VerifierInfo()
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				sh, err = GenerateShare(pi, wide, index)
+				if err != nil {
+					t.Error(err.Error())
+				}
+				if !VerifyShare(vi, sh) {
+					t.Error("VerifyShare failed. Expected: true")
+				}
+				if VerifyShareDegree(vi, dk, sh) {
+					t.Error("VerifyShareDegree failed with a secret beyond the threshold. Expected: false")
+				}
+			}
+		})
 	}
-	pi, err := GeneratePublicInfo(rand.Reader, s)
-	if err != nil {
-		t.Error(err.Error())
+}
+
+func TestProveSecret(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			constant, err := c.NewScalar().SetRandom(rand.Reader)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			s, err := GenerateSecret(rand.Reader, constant, deg)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			for _, mode := range []Mode{ModeG2, ModeG1} {
+				pi, err := GeneratePublicInfoMode(rand.Reader, s, mode)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				pr, err := ProveSecret(rand.Reader, pi, s)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				if !VerifySecret(pi, pr) {
+					t.Error("VerifySecret failed. Expected: true")
+				}
+				other, err := GenerateSecret(rand.Reader, constant, deg)
+				if err != nil {
+					t.Error(err.Error())
+				}
+				if pr, err = ProveSecret(rand.Reader, pi, other); err != nil {
+					t.Error(err.Error())
+				}
+				if VerifySecret(pi, pr) {
+					t.Error("VerifySecret failed with another secret. Expected: false")
+				}
+			}
+		})
 	}
-	for i := 0; i < deg; i++ {
-		index := big.NewInt(int64(i))
-		sh, err := GenerateShare(pi, s, index)
-		if err != nil {
-			t.Error(err.Error())
-		}
-		flag := VerifyShare(pi, sh)
-		if !flag {
-			t.Error("VerifyShare failed. Expected: true")
-		}
-		r, _ := rand.Int(rand.Reader, bn256.Order)
-		sh.Result = *r
-		flag = VerifyShare(pi, sh)
-		if flag {
-			t.Error("VerifyShare failed. Expected: false")
-		}
+}
+
+func TestVerifyShares(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			constant, err := c.NewScalar().SetRandom(rand.Reader)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			s, err := GenerateSecret(rand.Reader, constant, deg)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			for _, mode := range []Mode{ModeG2, ModeG1} {
+				pi, err := GeneratePublicInfoMode(rand.Reader, s, mode)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				vi, err := pi.VerifierInfo()
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				shs := make([]Share, deg)
+				for i := 0; i < deg; i++ {
+					index := curve.NewScalar(c, int64(i+1))
+					sh, err := GenerateShare(pi, s, index)
+					if err != nil {
+						t.Error(err.Error())
+					}
+					shs[i] = *sh
+				}
+				if bad := VerifyShares(vi, shs); len(bad) != 0 {
+					t.Errorf("VerifyShares failed. Expected no invalid share, Got: %v", bad)
+				}
+				shs[5].Result.SetRandom(rand.Reader)
+				if bad := VerifyShares(vi, shs); len(bad) != 1 || bad[0] != 5 {
+					t.Errorf("VerifyShares failed. Expected: [5], Got: %v", bad)
+				}
+			}
+		})
 	}
 }
 
 func TestReconstructSecret(t *testing.T) {
-	constant, err := rand.Int(rand.Reader, bn256.Order)
-	if err != nil {
-		t.Error(err.Error())
-	}
-	s, err := GenerateSecret(rand.Reader, constant, deg)
-	if err != nil {
-		t.Error(err.Error())
-	}
-	pi, err := GeneratePublicInfo(rand.Reader, s)
-	if err != nil {
-		t.Error(err.Error())
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			constant, err := c.NewScalar().SetRandom(rand.Reader)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			s, err := GenerateSecret(rand.Reader, constant, deg)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			pi, err := GeneratePublicInfo(rand.Reader, s)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			shs := make([]Share, deg)
+			for i := 0; i < deg; i++ {
+				index := curve.NewScalar(c, int64(i+1))
+				sh, err := GenerateShare(pi, s, index)
+				if err != nil {
+					t.Error(err.Error())
+				}
+				shs[i] = *sh
+			}
+			reconstructed := ReconstructSecret(c, shs)
+			if !constant.Equal(reconstructed) {
+				t.Errorf("ReconstructSecret failed. Expected: %s, Got: %s", constant.String(), reconstructed.String())
+			}
+			if _, err = GenerateSecret(rand.Reader, constant, 0); err == nil {
+				t.Error("GenerateSecret accepted a degree of 0.")
+			}
+		})
 	}
-	shs := make([]Share, deg)
-	for i := 0; i < deg; i++ {
-		index := big.NewInt(int64(i + 1))
-		sh, err := GenerateShare(pi, s, index)
-		if err != nil {
-			t.Error(err.Error())
-		}
-		shs[i] = *sh
-	}
-	reconstructed := ReconstructSecret(shs)
-	if constant.Cmp(reconstructed) != 0 {
-		t.Errorf("ReconstructSecret failed. Expected: %s, Got: %s", constant.String(), reconstructed.String())
+}
+
+func TestBig(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			constant, err := rand.Int(rand.Reader, c.Order())
+			if err != nil {
+				t.Error(err.Error())
+			}
+			s, err := GenerateSecretBig(c, rand.Reader, constant, deg)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			pi, err := GeneratePublicInfo(rand.Reader, s)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			vi, err := pi.VerifierInfo()
+			if err != nil {
+				t.Error(err.Error())
+			}
+			shs := make([]Share, deg)
+			for i := 0; i < deg; i++ {
+				// Negative indices are taken modulo the order.
+				sh, err := GenerateShareBig(pi, s, big.NewInt(int64(-i-1)))
+				if err != nil {
+					t.Error(err.Error())
+				}
+				if !VerifyShare(vi, sh) {
+					t.Error("VerifyShare failed. Expected: true")
+				}
+				shs[i] = *sh
+			}
+			reconstructed := ReconstructSecretBig(c, shs)
+			if constant.Cmp(reconstructed) != 0 {
+				t.Errorf("ReconstructSecretBig failed. Expected: %s, Got: %s", constant.String(), reconstructed.String())
+			}
+		})
 	}
 }
 
 func TestMarshal(t *testing.T) {
-	constant, err := rand.Int(rand.Reader, bn256.Order)
-	if err != nil {
-		t.Error(err.Error())
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			constant, err := c.NewScalar().SetRandom(rand.Reader)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			s, err := GenerateSecret(rand.Reader, constant, deg)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			pi, err := GeneratePublicInfo(rand.Reader, s)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			b, err := pi.Marshal()
+			if err != nil {
+				t.Error(err.Error())
+			}
+			var rPi PublicInfo
+			err = rPi.Unmarshal(b)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			vi, err := rPi.VerifierInfo()
+			if err != nil {
+				t.Error(err.Error())
+			}
+			b, err = vi.Marshal()
+			if err != nil {
+				t.Error(err.Error())
+			}
+			var rVi VerifierInfo
+			err = rVi.Unmarshal(b)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			for i := 0; i < deg; i++ {
+				index := curve.NewScalar(c, int64(i))
+				sh, err := GenerateShare(pi, s, index)
+				if err != nil {
+					t.Error(err.Error())
+				}
+				b, err = sh.Marshal()
+				if err != nil {
+					t.Error(err.Error())
+				}
+				var rSh Share
+				err = rSh.Unmarshal(b)
+				if err != nil {
+					t.Error(err.Error())
+				}
+				flag := VerifyShare(&rVi, &rSh)
+				if !flag {
+					t.Error("VerifyShare failed. Expected: true")
+				}
+				sh.Result.SetRandom(rand.Reader)
+				flag = VerifyShare(&rVi, sh)
+				if flag {
+					t.Error("VerifyShare failed. Expected: false")
+				}
+			}
+		})
 	}
-	s, err := GenerateSecret(rand.Reader, constant, deg)
-	if err != nil {
-		t.Error(err.Error())
+}
+
+// A share with index 1, result 5 and witness g^3, encoded by the first
+// version of the package, which wrote the scalars with big.Int.Bytes.
+const baselineShare = "0a01011201051a400769bf9ac56bea3ff40232bcb1b6bd159315d84715b8e679f2d355961915abf02ab799bee0489429554fdb7c8d086475319e63b40b9c5b57cdf1ff3dd9fe2261"
+
+func TestUnmarshalBaseline(t *testing.T) {
+	b, _ := hex.DecodeString(baselineShare)
+	var sh Share
+	if err := sh.Unmarshal(b); err != nil {
+		t.Fatal(err.Error())
 	}
-	pi, err := GeneratePublicInfo(rand.Reader, s)
-	if err != nil {
-		t.Error(err.Error())
+	c := curve.BN256
+	if sh.Index.Curve() != c || !sh.Index.Equal(curve.NewScalar(c, 1)) || !sh.Result.Equal(curve.NewScalar(c, 5)) {
+		t.Error("Unmarshal failed. Wrong scalars.")
 	}
-	b, err := pi.Marshal()
-	if err != nil {
-		t.Error(err.Error())
+	if !sh.Witness.Equal(c.NewG1().ScalarBaseMult(big.NewInt(3))) {
+		t.Error("Unmarshal failed. Wrong witness.")
 	}
-	var rPi PublicInfo
-	err = rPi.Unmarshal(b)
-	if err != nil {
-		t.Error(err.Error())
+}
+
+func TestMarshalCompressed(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			constant, err := c.NewScalar().SetRandom(rand.Reader)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			s, err := GenerateSecret(rand.Reader, constant, deg)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			for _, mode := range []Mode{ModeG2, ModeG1} {
+				pi, err := GeneratePublicInfoMode(rand.Reader, s, mode)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				b, err := pi.MarshalFormat(point.Compressed)
+				if err != nil {
+					t.Error(err.Error())
+				}
+				u, _ := pi.Marshal()
+				if len(b) >= len(u) {
+					t.Error("MarshalFormat failed. Compressed public information is not smaller.")
+				}
+				var rPi PublicInfo
+				if err = rPi.Unmarshal(b); err != nil {
+					t.Error(err.Error())
+				}
+				if m, _ := rPi.Marshal(); !bytes.Equal(m, u) {
+					t.Error("Marshal does not generate equal result.")
+				}
+				vi, err := rPi.VerifierInfo()
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				b, err = vi.MarshalFormat(point.Compressed)
+				if err != nil {
+					t.Error(err.Error())
+				}
+				var rVi VerifierInfo
+				if err = rVi.Unmarshal(b); err != nil {
+					t.Error(err.Error())
+				}
+				index := curve.NewScalar(c, 1)
+				sh, err := GenerateShare(pi, s, index)
+				if err != nil {
+					t.Error(err.Error())
+				}
+				b, err = sh.MarshalFormat(point.Compressed)
+				if err != nil {
+					t.Error(err.Error())
+				}
+				var rSh Share
+				if err = rSh.Unmarshal(b); err != nil {
+					t.Error(err.Error())
+				}
+				if !VerifyShare(&rVi, &rSh) {
+					t.Error("VerifyShare failed. Expected: true")
+				}
+				dk, err := rPi.DegreeKey(deg)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				if !VerifyShareDegree(&rVi, dk, &rSh) {
+					t.Error("VerifyShareDegree failed. Expected: true")
+				}
+			}
+		})
 	}
-	for i := 0; i < deg; i++ {
-		index := big.NewInt(int64(i))
-		sh, err := GenerateShare(pi, s, index)
-		if err != nil {
-			t.Error(err.Error())
-		}
-		b, err = sh.Marshal()
-		if err != nil {
-			t.Error(err.Error())
-		}
-		var rSh Share
-		err = rSh.Unmarshal(b)
-		if err != nil {
-			t.Error(err.Error())
-		}
-		flag := VerifyShare(&rPi, &rSh)
-		if !flag {
-			t.Error("VerifyShare failed. Expected: true")
-		}
-		r, _ := rand.Int(rand.Reader, bn256.Order)
-		sh.Result = *r
-		flag = VerifyShare(&rPi, sh)
-		if flag {
-			t.Error("VerifyShare failed. Expected: false")
-		}
+}
+
+func TestModeG1(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			constant, err := c.NewScalar().SetRandom(rand.Reader)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			s, err := GenerateSecret(rand.Reader, constant, deg)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if _, err = GeneratePublicInfoMode(rand.Reader, s, Mode(7)); err == nil {
+				t.Error("GeneratePublicInfoMode accepted an unknown mode.")
+			}
+			pi, err := GeneratePublicInfoMode(rand.Reader, s, ModeG1)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			b, err := pi.Marshal()
+			if err != nil {
+				t.Error(err.Error())
+			}
+			var rPi PublicInfo
+			err = rPi.Unmarshal(b)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			vi, err := rPi.VerifierInfo()
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			b, err = vi.Marshal()
+			if err != nil {
+				t.Error(err.Error())
+			}
+			var rVi VerifierInfo
+			err = rVi.Unmarshal(b)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if rVi.Mode != ModeG1 {
+				t.Error("Unmarshal failed. Wrong mode.")
+			}
+			shs := make([]Share, deg)
+			for i := 0; i < deg; i++ {
+				index := curve.NewScalar(c, int64(i+1))
+				sh, err := GenerateShare(&rPi, s, index)
+				if err != nil {
+					t.Error(err.Error())
+				}
+				if !VerifyShare(&rVi, sh) {
+					t.Error("VerifyShare failed. Expected: true")
+				}
+				shs[i] = *sh
+			}
+			if !constant.Equal(ReconstructSecret(c, shs)) {
+				t.Error("ReconstructSecret failed.")
+			}
+			shs[0].Result.SetRandom(rand.Reader)
+			if VerifyShare(&rVi, &shs[0]) {
+				t.Error("VerifyShare failed. Expected: false")
+			}
+		})
+	}
+}
+
+func TestGeneratePublicInfoContext(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			constant, err := c.NewScalar().SetRandom(rand.Reader)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			s, err := GenerateSecret(rand.Reader, constant, deg)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			seed := make([]byte, 1024)
+			rand.Read(seed)
+			for _, mode := range []Mode{ModeG2, ModeG1} {
+				pi, err := GeneratePublicInfoThreshold(bytes.NewReader(seed), s, mode, 2*deg, deg)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				pic, err := GeneratePublicInfoContext(context.Background(), bytes.NewReader(seed), s, mode, 2*deg, deg, 0)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				b, _ := pi.Marshal()
+				bc, _ := pic.Marshal()
+				if !bytes.Equal(b, bc) {
+					t.Error("GeneratePublicInfoContext failed. Expected the public information of GeneratePublicInfoThreshold.")
+				}
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if _, err = GeneratePublicInfoContext(ctx, rand.Reader, s, ModeG2, deg, deg, 0); err != context.Canceled {
+				t.Error("GeneratePublicInfoContext failed. Expected: context.Canceled.")
+			}
+		})
 	}
 }
@@ -0,0 +1,420 @@
+// Package fr implements arithmetic in the scalar field of bn256,
+// i.e. the integers modulo bn256.Order, on fixed-width elements of four
+// 64-bit limbs kept in Montgomery form.
+
+package fr
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"math/bits"
+)
+
+// Element is an element of the scalar field of bn256, stored as
+// little-endian 64-bit limbs of x * 2^256 mod q.
+// The zero value is the field element 0.
+type Element [4]uint64
+
+const (
+	// Size is the size in bytes of the canonical encoding of an Element.
+	Size = 32
+	// Bits is the bit length of the modulus.
+	Bits = 254
+)
+
+// q is the modulus bn256.Order in little-endian limbs.
+var q = Element{0x43e1f593f0000001, 0x2833e84879b97091, 0xb85045b68181585d, 0x30644e72e131a029}
+
+// qInvNeg is -q^-1 mod 2^64.
+const qInvNeg = 0xc2e1f593efffffff
+
+// rSquare is 2^512 mod q, used to convert into Montgomery form.
+var rSquare = Element{0x1bb8e645ae216da7, 0x53fe3ab1e35c59e3, 0x8c49833d53bb8085, 0x0216d0b17f4e44a5}
+
+// one is 1 in Montgomery form, i.e. 2^256 mod q.
+var one = Element{0xac96341c4ffffffb, 0x36fc76959f60cd29, 0x666ea36f7879462e, 0x0e0a77c19a07df2f}
+
+// modulus is q as a big.Int.
+var modulus, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+// Return a copy of the modulus q.
+func Modulus() *big.Int {
+	return new(big.Int).Set(modulus)
+}
+
+// Return the element v.
+func NewElement(v int64) Element {
+	var z Element
+	z.SetInt64(v)
+	return z
+}
+
+// Set z to 0 and return z.
+func (z *Element) SetZero() *Element {
+	*z = Element{}
+	return z
+}
+
+// Set z to 1 and return z.
+func (z *Element) SetOne() *Element {
+	*z = one
+	return z
+}
+
+// Set z to x and return z.
+func (z *Element) Set(x *Element) *Element {
+	*z = *x
+	return z
+}
+
+// Set z to v and return z.
+func (z *Element) SetUint64(v uint64) *Element {
+	*z = Element{v}
+	return z.Mul(z, &rSquare)
+}
+
+// Set z to v mod q and return z.
+func (z *Element) SetInt64(v int64) *Element {
+	if v >= 0 {
+		return z.SetUint64(uint64(v))
+	}
+	z.SetUint64(uint64(-(v + 1)) + 1)
+	return z.Neg(z)
+}
+
+// Set z to v mod q and return z. v may be negative or unreduced.
+func (z *Element) SetBigInt(v *big.Int) *Element {
+	if v.Sign() >= 0 && v.Cmp(modulus) < 0 {
+		z.setBig(v)
+	} else {
+		z.setBig(new(big.Int).Mod(v, modulus))
+	}
+	return z
+}
+
+// Set z to v, which must be in [0, q).
+func (z *Element) setBig(v *big.Int) {
+	*z = Element{}
+	words := v.Bits()
+	if bits.UintSize == 64 {
+		for i := 0; i < len(words) && i < 4; i++ {
+			z[i] = uint64(words[i])
+		}
+	} else {
+		for i := 0; i < len(words) && i < 8; i++ {
+			z[i/2] |= uint64(words[i]) << (32 * uint(i%2))
+		}
+	}
+	z.Mul(z, &rSquare)
+}
+
+// Set res to z as an integer in [0, q) and return res.
+// A new big.Int is allocated if res is nil.
+func (z *Element) BigInt(res *big.Int) *big.Int {
+	if res == nil {
+		res = new(big.Int)
+	}
+	b := z.Bytes()
+	return res.SetBytes(b[:])
+}
+
+// Return the little-endian limbs of z as an integer in [0, q).
+func (z *Element) Limbs() [4]uint64 {
+	var r Element
+	r.fromMont(z)
+	return r
+}
+
+// Return the canonical big-endian encoding of z.
+func (z *Element) Bytes() (b [Size]byte) {
+	r := z.Limbs()
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 8; j++ {
+			b[Size-1-8*i-j] = byte(r[i] >> (8 * uint(j)))
+		}
+	}
+	return
+}
+
+// Return the canonical big-endian encoding of z as a slice.
+func (z *Element) Marshal() []byte {
+	b := z.Bytes()
+	return b[:]
+}
+
+// Set z to the big-endian integer b and return z.
+// b may be shorter than Size but must encode an integer less than q.
+func (z *Element) SetBytes(b []byte) (*Element, error) {
+	if len(b) > Size {
+		return nil, errors.New("fr: encoding is too long")
+	}
+	var r Element
+	for i := range b {
+		k := len(b) - 1 - i
+		r[k/8] |= uint64(b[i]) << (8 * uint(k%8))
+	}
+	if !r.less(&q) {
+		return nil, errors.New("fr: encoding is not reduced")
+	}
+	*z = r
+	z.Mul(z, &rSquare)
+	return z, nil
+}
+
+// Set z to the big-endian integer b reduced mod q and return z.
+// b may have any length, so that wide inputs give nearly uniform results.
+func (z *Element) SetBytesReduce(b []byte) *Element {
+	return z.SetBigInt(new(big.Int).SetBytes(b))
+}
+
+// Set z to a uniformly random element read from r and return z.
+func (z *Element) SetRandom(r io.Reader) (*Element, error) {
+	var b [Size]byte
+	for {
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, err
+		}
+		// Rejection sampling on Bits bits keeps the result uniform.
+		b[0] &= 0xff >> (8*Size - Bits)
+		if _, err := z.SetBytes(b[:]); err == nil {
+			return z, nil
+		}
+	}
+}
+
+// Return whether z is 0.
+func (z *Element) IsZero() bool {
+	return (z[0] | z[1] | z[2] | z[3]) == 0
+}
+
+// Return whether z is 1.
+func (z *Element) IsOne() bool {
+	return *z == one
+}
+
+// Return whether z equals x.
+func (z *Element) Equal(x *Element) bool {
+	return *z == *x
+}
+
+// Return z as a decimal string.
+func (z *Element) String() string {
+	return z.BigInt(nil).String()
+}
+
+// Return whether z < x as integers on the limbs.
+func (z *Element) less(x *Element) bool {
+	_, b := bits.Sub64(z[0], x[0], 0)
+	_, b = bits.Sub64(z[1], x[1], b)
+	_, b = bits.Sub64(z[2], x[2], b)
+	_, b = bits.Sub64(z[3], x[3], b)
+	return b != 0
+}
+
+// Subtract q from z if z >= q.
+func (z *Element) reduce() {
+	var r Element
+	var b uint64
+	r[0], b = bits.Sub64(z[0], q[0], 0)
+	r[1], b = bits.Sub64(z[1], q[1], b)
+	r[2], b = bits.Sub64(z[2], q[2], b)
+	r[3], b = bits.Sub64(z[3], q[3], b)
+	if b == 0 {
+		*z = r
+	}
+}
+
+// Set z to x + y and return z.
+func (z *Element) Add(x, y *Element) *Element {
+	// q < 2^254, so the sum never overflows the limbs.
+	var c uint64
+	z[0], c = bits.Add64(x[0], y[0], 0)
+	z[1], c = bits.Add64(x[1], y[1], c)
+	z[2], c = bits.Add64(x[2], y[2], c)
+	z[3], _ = bits.Add64(x[3], y[3], c)
+	z.reduce()
+	return z
+}
+
+// Set z to 2 * x and return z.
+func (z *Element) Double(x *Element) *Element {
+	return z.Add(x, x)
+}
+
+// Set z to x - y and return z.
+func (z *Element) Sub(x, y *Element) *Element {
+	var b uint64
+	z[0], b = bits.Sub64(x[0], y[0], 0)
+	z[1], b = bits.Sub64(x[1], y[1], b)
+	z[2], b = bits.Sub64(x[2], y[2], b)
+	z[3], b = bits.Sub64(x[3], y[3], b)
+	if b != 0 {
+		var c uint64
+		z[0], c = bits.Add64(z[0], q[0], 0)
+		z[1], c = bits.Add64(z[1], q[1], c)
+		z[2], c = bits.Add64(z[2], q[2], c)
+		z[3], _ = bits.Add64(z[3], q[3], c)
+	}
+	return z
+}
+
+// Set z to -x and return z.
+func (z *Element) Neg(x *Element) *Element {
+	if x.IsZero() {
+		return z.SetZero()
+	}
+	return z.Sub(&q, x)
+}
+
+// Return the high and low words of a * b + c.
+func madd1(a, b, c uint64) (hi, lo uint64) {
+	var carry uint64
+	hi, lo = bits.Mul64(a, b)
+	lo, carry = bits.Add64(lo, c, 0)
+	hi += carry
+	return
+}
+
+// Return the high and low words of a * b + c + d.
+func madd2(a, b, c, d uint64) (hi, lo uint64) {
+	var carry uint64
+	hi, lo = bits.Mul64(a, b)
+	c, carry = bits.Add64(c, d, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, c, 0)
+	hi += carry
+	return
+}
+
+// Return the high and low words of a * b + c + d + e * 2^64.
+func madd3(a, b, c, d, e uint64) (hi, lo uint64) {
+	var carry uint64
+	hi, lo = bits.Mul64(a, b)
+	c, carry = bits.Add64(c, d, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, c, 0)
+	hi, _ = bits.Add64(hi, e, carry)
+	return
+}
+
+// Set z to x * y and return z.
+func (z *Element) Mul(x, y *Element) *Element {
+	// Coarsely integrated operand scanning Montgomery multiplication,
+	// t = x * y * 2^-256 mod q. Since the top limb of q is below 2^62,
+	// the intermediate result fits in four limbs without an extra carry word.
+	var t [4]uint64
+	var c0, c1, c2, m uint64
+	c1, c0 = bits.Mul64(x[0], y[0])
+	m = c0 * qInvNeg
+	c2, _ = madd1(m, q[0], c0)
+	c1, c0 = madd1(x[0], y[1], c1)
+	c2, t[0] = madd2(m, q[1], c2, c0)
+	c1, c0 = madd1(x[0], y[2], c1)
+	c2, t[1] = madd2(m, q[2], c2, c0)
+	c1, c0 = madd1(x[0], y[3], c1)
+	t[3], t[2] = madd3(m, q[3], c0, c2, c1)
+	for i := 1; i < 4; i++ {
+		c1, c0 = madd1(x[i], y[0], t[0])
+		m = c0 * qInvNeg
+		c2, _ = madd1(m, q[0], c0)
+		c1, c0 = madd2(x[i], y[1], c1, t[1])
+		c2, t[0] = madd2(m, q[1], c2, c0)
+		c1, c0 = madd2(x[i], y[2], c1, t[2])
+		c2, t[1] = madd2(m, q[2], c2, c0)
+		c1, c0 = madd2(x[i], y[3], c1, t[3])
+		t[3], t[2] = madd3(m, q[3], c0, c2, c1)
+	}
+	z[0], z[1], z[2], z[3] = t[0], t[1], t[2], t[3]
+	z.reduce()
+	return z
+}
+
+// Set z to x * x and return z.
+func (z *Element) Square(x *Element) *Element {
+	return z.Mul(x, x)
+}
+
+// Set z to x * 2^-256, i.e. convert out of Montgomery form.
+func (z *Element) fromMont(x *Element) {
+	z.Mul(x, &Element{1})
+}
+
+// Set z to x^e and return z. A negative e inverts x first.
+func (z *Element) Exp(x *Element, e *big.Int) *Element {
+	base := *x
+	if e.Sign() < 0 {
+		base.Inverse(&base)
+		e = new(big.Int).Neg(e)
+	}
+	res := one
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res.Square(&res)
+		if e.Bit(i) == 1 {
+			res.Mul(&res, &base)
+		}
+	}
+	*z = res
+	return z
+}
+
+// Set z to x^-1 and return z. The inverse of 0 is 0.
+func (z *Element) Inverse(x *Element) *Element {
+	if x.IsZero() {
+		return z.SetZero()
+	}
+	// The binary extended Euclidean algorithm of math/big is several times
+	// faster than exponentiation by q - 2.
+	v := x.BigInt(nil)
+	z.setBig(v.ModInverse(v, modulus))
+	return z
+}
+
+// Set z to x / y and return z.
+func (z *Element) Div(x, y *Element) *Element {
+	var inv Element
+	inv.Inverse(y)
+	return z.Mul(x, &inv)
+}
+
+// Invert every element of a in place with a single inversion.
+// Zero elements are left as 0.
+func BatchInvert(a []Element) {
+	prefix := make([]Element, len(a))
+	acc := one
+	for i := range a {
+		prefix[i] = acc
+		if !a[i].IsZero() {
+			acc.Mul(&acc, &a[i])
+		}
+	}
+	acc.Inverse(&acc)
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i].IsZero() {
+			continue
+		}
+		// a[i]^-1 = (a_0 ... a_(i - 1)) * (a_0 ... a_i)^-1
+		var inv Element
+		inv.Mul(&prefix[i], &acc)
+		acc.Mul(&acc, &a[i])
+		a[i] = inv
+	}
+}
+
+// Convert every big.Int in v into an Element reduced mod q.
+func FromBigInts(v []big.Int) []Element {
+	ret := make([]Element, len(v))
+	for i := range v {
+		ret[i].SetBigInt(&v[i])
+	}
+	return ret
+}
+
+// Convert every Element in v into a big.Int in [0, q).
+func ToBigInts(v []Element) []big.Int {
+	ret := make([]big.Int, len(v))
+	for i := range v {
+		v[i].BigInt(&ret[i])
+	}
+	return ret
+}
@@ -0,0 +1,192 @@
+package fr
+
+import (
+	"testing"
+
+	"bytes"
+	"crypto/rand"
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"math"
+	"math/big"
+)
+
+func randomPair(t testing.TB) (*Element, *big.Int) {
+	v, err := rand.Int(rand.Reader, bn256.Order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e Element
+	e.SetBigInt(v)
+	return &e, v
+}
+
+func TestModulus(t *testing.T) {
+	if Modulus().Cmp(bn256.Order) != 0 {
+		t.Error("Modulus differs from bn256.Order.")
+	}
+	if Modulus().BitLen() != Bits {
+		t.Error("Bits differs from the bit length of the modulus.")
+	}
+	var z Element
+	z.SetBigInt(bn256.Order)
+	if !z.IsZero() {
+		t.Error("SetBigInt does not reduce the modulus to 0.")
+	}
+	if !new(Element).SetOne().IsOne() || new(Element).SetOne().BigInt(nil).Cmp(big.NewInt(1)) != 0 {
+		t.Error("SetOne failed.")
+	}
+}
+
+func TestConversion(t *testing.T) {
+	for _, v := range []int64{0, 1, -1, 5, -5, math.MaxInt64, math.MinInt64} {
+		e := NewElement(v)
+		expected := new(big.Int).Mod(big.NewInt(v), bn256.Order)
+		if e.BigInt(nil).Cmp(expected) != 0 {
+			t.Errorf("NewElement failed on %d. Got: %s", v, e.String())
+		}
+	}
+	for i := 0; i < 100; i++ {
+		e, v := randomPair(t)
+		if e.BigInt(nil).Cmp(v) != 0 {
+			t.Fatal("BigInt does not invert SetBigInt.")
+		}
+		var n Element
+		n.SetBigInt(new(big.Int).Sub(v, bn256.Order))
+		if !n.Equal(e) {
+			t.Fatal("SetBigInt does not reduce negative values.")
+		}
+	}
+	v := []big.Int{*big.NewInt(-3), *big.NewInt(7)}
+	back := ToBigInts(FromBigInts(v))
+	if back[0].Cmp(new(big.Int).Sub(bn256.Order, big.NewInt(3))) != 0 || back[1].Cmp(big.NewInt(7)) != 0 {
+		t.Error("FromBigInts and ToBigInts failed.")
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	r := new(big.Int)
+	for i := 0; i < 1000; i++ {
+		x, xv := randomPair(t)
+		y, yv := randomPair(t)
+		var z Element
+		if z.Add(x, y).BigInt(nil).Cmp(r.Mod(r.Add(xv, yv), bn256.Order)) != 0 {
+			t.Fatal("Add failed.")
+		}
+		if z.Sub(x, y).BigInt(nil).Cmp(r.Mod(r.Sub(xv, yv), bn256.Order)) != 0 {
+			t.Fatal("Sub failed.")
+		}
+		if z.Mul(x, y).BigInt(nil).Cmp(r.Mod(r.Mul(xv, yv), bn256.Order)) != 0 {
+			t.Fatal("Mul failed.")
+		}
+		if z.Square(x).BigInt(nil).Cmp(r.Mod(r.Mul(xv, xv), bn256.Order)) != 0 {
+			t.Fatal("Square failed.")
+		}
+		if z.Neg(x).BigInt(nil).Cmp(r.Mod(r.Neg(xv), bn256.Order)) != 0 {
+			t.Fatal("Neg failed.")
+		}
+		if z.Double(x).BigInt(nil).Cmp(r.Mod(r.Add(xv, xv), bn256.Order)) != 0 {
+			t.Fatal("Double failed.")
+		}
+		// Aliased operands.
+		z.Set(x)
+		if z.Mul(&z, &z).BigInt(nil).Cmp(r.Mod(r.Mul(xv, xv), bn256.Order)) != 0 {
+			t.Fatal("Mul failed on aliased operands.")
+		}
+	}
+	var zero Element
+	if !new(Element).Neg(&zero).IsZero() {
+		t.Error("Neg failed on 0.")
+	}
+}
+
+func TestInverse(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		x, xv := randomPair(t)
+		var z Element
+		if z.Inverse(x).BigInt(nil).Cmp(new(big.Int).ModInverse(xv, bn256.Order)) != 0 {
+			t.Fatal("Inverse failed.")
+		}
+		y, _ := randomPair(t)
+		if !z.Mul(z.Div(y, x), x).Equal(y) {
+			t.Fatal("Div failed.")
+		}
+		e, ev := randomPair(t)
+		_ = e
+		if z.Exp(x, ev).BigInt(nil).Cmp(new(big.Int).Exp(xv, ev, bn256.Order)) != 0 {
+			t.Fatal("Exp failed.")
+		}
+		var inv Element
+		inv.Inverse(x)
+		if !z.Exp(x, big.NewInt(-1)).Equal(&inv) {
+			t.Fatal("Exp failed on a negative exponent.")
+		}
+	}
+	a := make([]Element, 10)
+	for i := range a {
+		if i != 3 {
+			a[i].SetRandom(rand.Reader)
+		}
+	}
+	b := make([]Element, len(a))
+	copy(b, a)
+	BatchInvert(b)
+	for i := range a {
+		var z Element
+		if i == 3 && !b[i].IsZero() || i != 3 && !z.Mul(&a[i], &b[i]).IsOne() {
+			t.Error("BatchInvert failed.")
+		}
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		x, xv := randomPair(t)
+		b := x.Bytes()
+		expected := make([]byte, Size)
+		xv.FillBytes(expected)
+		if !bytes.Equal(b[:], expected) || !bytes.Equal(x.Marshal(), expected) {
+			t.Fatal("Bytes does not produce the big-endian encoding.")
+		}
+		var y Element
+		if _, err := y.SetBytes(b[:]); err != nil || !y.Equal(x) {
+			t.Fatal("SetBytes does not invert Bytes.")
+		}
+		// Minimal encodings as produced by big.Int.Bytes are accepted as well.
+		if _, err := y.SetBytes(xv.Bytes()); err != nil || !y.Equal(x) {
+			t.Fatal("SetBytes rejects a short encoding.")
+		}
+	}
+	var y Element
+	if _, err := y.SetBytes(bn256.Order.Bytes()); err == nil {
+		t.Error("SetBytes accepted an unreduced encoding.")
+	}
+	if _, err := y.SetBytes(make([]byte, Size+1)); err == nil {
+		t.Error("SetBytes accepted a long encoding.")
+	}
+	wide := make([]byte, 64)
+	rand.Read(wide)
+	expected := new(big.Int).Mod(new(big.Int).SetBytes(wide), bn256.Order)
+	if y.SetBytesReduce(wide).BigInt(nil).Cmp(expected) != 0 {
+		t.Error("SetBytesReduce failed.")
+	}
+	if _, err := y.SetRandom(rand.Reader); err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkMul(b *testing.B) {
+	x, _ := randomPair(b)
+	y, _ := randomPair(b)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		x.Mul(x, y)
+	}
+}
+
+func BenchmarkInverse(b *testing.B) {
+	x, _ := randomPair(b)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		x.Inverse(x)
+	}
+}
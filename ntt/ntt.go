@@ -0,0 +1,262 @@
+// Package ntt implements the radix-2 number-theoretic transform over the
+// scalar field of a curve, i.e. the integers modulo its order,
+// together with polynomial multiplication built on it.
+
+package ntt
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+)
+
+const (
+	// Below this size polynomials are multiplied directly.
+	naiveThreshold = 64
+)
+
+// Return the largest k such that the order of c minus 1 is divisible by 2^k.
+func TwoAdicity(c curve.Curve) int {
+	e := new(big.Int).Sub(c.Order(), big.NewInt(1))
+	return int(e.TrailingZeroBits())
+}
+
+// Struct Domain implements the evaluation domain of the n-th roots of unity
+// in the scalar field of Curve, where n is a power of 2.
+// Coset domains are shifted by the multiplicative generator of the curve.
+type Domain struct {
+	Curve        curve.Curve
+	Size         int
+	Generator    curve.Scalar
+	GeneratorInv curve.Scalar
+	SizeInv      curve.Scalar
+	// Elements[j] = Generator^j.
+	Elements []curve.Scalar
+	index    map[string]int
+}
+
+// Create the evaluation domain of the n-th roots of unity of the curve c.
+func NewDomain(c curve.Curve, n int) (*Domain, error) {
+	if n < 1 || n&(n-1) != 0 {
+		return nil, errors.New("Domain size is not a power of 2")
+	}
+	k := 0
+	for 1<<uint(k) < n {
+		k++
+	}
+	if k > TwoAdicity(c) {
+		return nil, errors.New("Domain size exceeds the 2-adicity of the scalar field")
+	}
+	d := new(Domain)
+	d.Curve = c
+	d.Size = n
+	// omega = g^((r - 1) / n) has order exactly n.
+	e := new(big.Int).Sub(c.Order(), big.NewInt(1))
+	e.Rsh(e, uint(k))
+	d.Generator = c.NewScalar().Exp(c.MultiplicativeGenerator(), e)
+	d.GeneratorInv = c.NewScalar().Inverse(d.Generator)
+	d.SizeInv = curve.NewScalar(c, int64(n))
+	d.SizeInv.Inverse(d.SizeInv)
+	d.Elements = make([]curve.Scalar, n)
+	d.index = make(map[string]int, n)
+	d.Elements[0] = c.NewScalar().SetOne()
+	d.index[string(d.Elements[0].Marshal())] = 0
+	for j := 1; j < n; j++ {
+		d.Elements[j] = c.NewScalar().Mul(d.Elements[j-1], d.Generator)
+		d.index[string(d.Elements[j].Marshal())] = j
+	}
+	return d, nil
+}
+
+// Return the smallest domain of the curve c holding at least n elements.
+func NewDomainAtLeast(c curve.Curve, n int) (*Domain, error) {
+	size := 1
+	for size < n {
+		size <<= 1
+	}
+	return NewDomain(c, size)
+}
+
+// Return the index j such that z = Generator^j, if z is in the domain.
+func (d *Domain) Index(z curve.Scalar) (int, bool) {
+	j, ok := d.index[string(z.Marshal())]
+	return j, ok
+}
+
+// Evaluate the polynomial given by its evaluations evals over the domain at z
+// with the barycentric formula
+// f(z) = (z^n - 1) / n * sum_j f_j * w^j / (z - w^j).
+func (d *Domain) Evaluate(evals []curve.Scalar, z curve.Scalar) (curve.Scalar, error) {
+	if len(evals) != d.Size {
+		return nil, errors.New("Number of evaluations does not match the domain")
+	}
+	if j, ok := d.Index(z); ok {
+		return d.Curve.NewScalar().Set(evals[j]), nil
+	}
+	denom := make([]curve.Scalar, d.Size)
+	for j := range denom {
+		denom[j] = d.Curve.NewScalar().Sub(z, d.Elements[j])
+	}
+	curve.BatchInvert(denom)
+	res := d.Curve.NewScalar()
+	term := d.Curve.NewScalar()
+	for j := range evals {
+		term.Mul(evals[j], d.Elements[j])
+		term.Mul(term, denom[j])
+		res.Add(res, term)
+	}
+	zn := d.Curve.NewScalar().Exp(z, big.NewInt(int64(d.Size)))
+	zn.Sub(zn, d.Curve.NewScalar().SetOne())
+	res.Mul(res, zn)
+	return res.Mul(res, d.SizeInv), nil
+}
+
+// Reverse the order of the elements of a by the bits of their indices.
+func bitReverse(a []curve.Scalar) {
+	n := len(a)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+}
+
+// Compute a[i] = sum_j a[j] * w^(ij) in place, where w has order len(a).
+// twiddles[j] must hold w^j for j < len(a) / 2.
+func (d *Domain) transform(a []curve.Scalar, twiddles []curve.Scalar) {
+	n := len(a)
+	bitReverse(a)
+	t := d.Curve.NewScalar()
+	for m := 2; m <= n; m <<= 1 {
+		step := n / m
+		for k := 0; k < n; k += m {
+			for j := 0; j < m/2; j++ {
+				u, v := a[k+j], a[k+j+m/2]
+				t.Mul(v, twiddles[j*step])
+				v.Sub(u, t)
+				u.Add(u, t)
+			}
+		}
+	}
+}
+
+// Copy a into new scalars of the domain size, padding it with zeros.
+func (d *Domain) pad(a []curve.Scalar) ([]curve.Scalar, error) {
+	if len(a) > d.Size {
+		return nil, errors.New("Input is larger than the domain")
+	}
+	ret := curve.NewScalars(d.Curve, d.Size)
+	for i := range a {
+		ret[i].Set(a[i])
+	}
+	return ret, nil
+}
+
+// Return the first n / 2 powers of w.
+func (d *Domain) twiddles(w curve.Scalar) []curve.Scalar {
+	tw := make([]curve.Scalar, (d.Size+1)/2)
+	if len(tw) > 0 {
+		tw[0] = d.Curve.NewScalar().SetOne()
+	}
+	for j := 1; j < len(tw); j++ {
+		tw[j] = d.Curve.NewScalar().Mul(tw[j-1], w)
+	}
+	return tw
+}
+
+// Evaluate the polynomial with coefficients coeffs at every element of the domain.
+// coeffs must not hold more elements than the domain and is left untouched.
+func (d *Domain) NTT(coeffs []curve.Scalar) ([]curve.Scalar, error) {
+	a, err := d.pad(coeffs)
+	if err != nil {
+		return nil, err
+	}
+	d.transform(a, d.twiddles(d.Generator))
+	return a, nil
+}
+
+// Interpolate the coefficients of the polynomial with evaluations evals
+// over the domain. evals is left untouched.
+func (d *Domain) INTT(evals []curve.Scalar) ([]curve.Scalar, error) {
+	if len(evals) != d.Size {
+		return nil, errors.New("Number of evaluations does not match the domain")
+	}
+	a, _ := d.pad(evals)
+	d.transform(a, d.twiddles(d.GeneratorInv))
+	for i := range a {
+		a[i].Mul(a[i], d.SizeInv)
+	}
+	return a, nil
+}
+
+// Evaluate the polynomial with coefficients coeffs over the coset
+// g * Domain, where g is the multiplicative generator of the curve.
+func (d *Domain) CosetNTT(coeffs []curve.Scalar) ([]curve.Scalar, error) {
+	a, err := d.pad(coeffs)
+	if err != nil {
+		return nil, err
+	}
+	// f(g * x) has coefficients f_i * g^i.
+	shift := d.Curve.NewScalar().SetOne()
+	g := d.Curve.MultiplicativeGenerator()
+	for i := range a {
+		a[i].Mul(a[i], shift)
+		shift.Mul(shift, g)
+	}
+	d.transform(a, d.twiddles(d.Generator))
+	return a, nil
+}
+
+// Interpolate the coefficients of the polynomial with evaluations evals
+// over the coset g * Domain, where g is the multiplicative generator of the curve.
+func (d *Domain) CosetINTT(evals []curve.Scalar) ([]curve.Scalar, error) {
+	a, err := d.INTT(evals)
+	if err != nil {
+		return nil, err
+	}
+	gInv := d.Curve.MultiplicativeGenerator()
+	gInv.Inverse(gInv)
+	shift := d.Curve.NewScalar().SetOne()
+	for i := range a {
+		a[i].Mul(a[i], shift)
+		shift.Mul(shift, gInv)
+	}
+	return a, nil
+}
+
+// Multiply the polynomials a and b given by their coefficients,
+// in the scalar field of the curve of their coefficients.
+func Multiply(a []curve.Scalar, b []curve.Scalar) ([]curve.Scalar, error) {
+	if len(a) == 0 || len(b) == 0 {
+		return []curve.Scalar{}, nil
+	}
+	c := a[0].Curve()
+	n := len(a) + len(b) - 1
+	if len(a) < naiveThreshold || len(b) < naiveThreshold {
+		ret := curve.NewScalars(c, n)
+		term := c.NewScalar()
+		for i := range a {
+			for j := range b {
+				ret[i+j].Add(ret[i+j], term.Mul(a[i], b[j]))
+			}
+		}
+		return ret, nil
+	}
+	d, err := NewDomainAtLeast(c, n)
+	if err != nil {
+		return nil, err
+	}
+	ea, _ := d.NTT(a)
+	eb, _ := d.NTT(b)
+	for i := range ea {
+		ea[i].Mul(ea[i], eb[i])
+	}
+	ret, _ := d.INTT(ea)
+	return ret[:n], nil
+}
@@ -0,0 +1,185 @@
+package ntt
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"io"
+	"math/big"
+)
+
+const (
+	size = 256
+)
+
+var curves = []curve.Curve{curve.BN256, curve.BN256Google, curve.BLS12381}
+
+func generatePoly(c curve.Curve, r io.Reader, degree int) []curve.Scalar {
+	poly := curve.NewScalars(c, degree)
+	for i := range poly {
+		poly[i].SetRandom(r)
+	}
+	return poly
+}
+
+func randomScalar(c curve.Curve) curve.Scalar {
+	z, _ := c.NewScalar().SetRandom(rand.Reader)
+	return z
+}
+
+// Evaluate the polynomial poly at x with Horner's rule.
+func evaluate(poly []curve.Scalar, x curve.Scalar) curve.Scalar {
+	res := x.Curve().NewScalar()
+	for j := len(poly) - 1; j >= 0; j-- {
+		res.Mul(res, x)
+		res.Add(res, poly[j])
+	}
+	return res
+}
+
+func TestTwoAdicity(t *testing.T) {
+	if TwoAdicity(curve.BN256) != 28 || TwoAdicity(curve.BLS12381) != 32 {
+		t.Error("TwoAdicity failed. Expected: 28 for bn256 and 32 for BLS12-381.")
+	}
+}
+
+func TestDomain(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			if _, err := NewDomain(c, 12); err == nil {
+				t.Error("NewDomain accepted a size that is not a power of 2.")
+			}
+			if _, err := NewDomain(c, 1<<uint(TwoAdicity(c)+1)); err == nil {
+				t.Error("NewDomain accepted a size beyond the 2-adicity.")
+			}
+			d, err := NewDomainAtLeast(c, size-1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.Size != size {
+				t.Errorf("NewDomainAtLeast failed. Expected: %d, Got: %d", size, d.Size)
+			}
+			e := c.NewScalar()
+			if !e.Exp(d.Generator, big.NewInt(size)).IsOne() || e.Exp(d.Generator, big.NewInt(size/2)).IsOne() {
+				t.Error("Generator does not have order equal to the domain size.")
+			}
+			for j := range d.Elements {
+				if k, ok := d.Index(d.Elements[j]); !ok || k != j {
+					t.Error("Index failed on a domain element.")
+				}
+			}
+			if _, ok := d.Index(c.MultiplicativeGenerator()); ok {
+				t.Error("Index succeeded on an element outside the domain.")
+			}
+		})
+	}
+}
+
+func TestNTT(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			d, _ := NewDomain(c, size)
+			poly := generatePoly(c, rand.Reader, size-3)
+			evals, err := d.NTT(poly)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for j := range evals {
+				if !evals[j].Equal(evaluate(poly, d.Elements[j])) {
+					t.Fatal("NTT failed. Wrong evaluation result.")
+				}
+			}
+			z := randomScalar(c)
+			res, err := d.Evaluate(evals, z)
+			if err != nil {
+				t.Error(err)
+			}
+			if !res.Equal(evaluate(poly, z)) {
+				t.Error("Evaluate failed. Wrong evaluation result.")
+			}
+			coeffs, err := d.INTT(evals)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range coeffs {
+				if i < len(poly) && !coeffs[i].Equal(poly[i]) || i >= len(poly) && !coeffs[i].IsZero() {
+					t.Fatal("INTT failed. Wrong coefficient.")
+				}
+			}
+			if _, err := d.NTT(generatePoly(c, rand.Reader, size+1)); err == nil {
+				t.Error("NTT accepted a polynomial larger than the domain.")
+			}
+		})
+	}
+}
+
+func TestCosetNTT(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			d, _ := NewDomain(c, size)
+			poly := generatePoly(c, rand.Reader, size)
+			evals, err := d.CosetNTT(poly)
+			if err != nil {
+				t.Fatal(err)
+			}
+			x := c.NewScalar()
+			for j := range evals {
+				x.Mul(d.Elements[j], c.MultiplicativeGenerator())
+				if !evals[j].Equal(evaluate(poly, x)) {
+					t.Fatal("CosetNTT failed. Wrong evaluation result.")
+				}
+			}
+			coeffs, err := d.CosetINTT(evals)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range coeffs {
+				if !coeffs[i].Equal(poly[i]) {
+					t.Fatal("CosetINTT failed. Wrong coefficient.")
+				}
+			}
+		})
+	}
+}
+
+func TestMultiply(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			for _, n := range [][2]int{{1, 1}, {3, 5}, {100, 200}, {size, size}} {
+				a := generatePoly(c, rand.Reader, n[0])
+				b := generatePoly(c, rand.Reader, n[1])
+				p, err := Multiply(a, b)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(p) != n[0]+n[1]-1 {
+					t.Fatalf("Multiply failed. Expected length: %d, Got: %d", n[0]+n[1]-1, len(p))
+				}
+				z := randomScalar(c)
+				expected := c.NewScalar().Mul(evaluate(a, z), evaluate(b, z))
+				if !evaluate(p, z).Equal(expected) {
+					t.Errorf("Multiply failed with degrees %d and %d.", n[0], n[1])
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkNTT(b *testing.B) {
+	d, _ := NewDomain(curve.BN256, 1<<12)
+	poly := generatePoly(curve.BN256, rand.Reader, d.Size)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		d.NTT(poly)
+	}
+}
+
+func BenchmarkMultiply(b *testing.B) {
+	p := generatePoly(curve.BN256, rand.Reader, 1<<11)
+	q := generatePoly(curve.BN256, rand.Reader, 1<<11)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		Multiply(p, q)
+	}
+}
@@ -0,0 +1,138 @@
+package point
+
+// This file implements the square roots in the base field of bn256 and its
+// quadratic extension needed to decompress points.
+
+import (
+	"math/big"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+var (
+	// (p + 1) / 4, (p - 3) / 4 and (p - 1) / 2.
+	pPlus1Over4  = new(big.Int).Rsh(new(big.Int).Add(bn256.P, big.NewInt(1)), 2)
+	pMinus3Over4 = new(big.Int).Rsh(new(big.Int).Sub(bn256.P, big.NewInt(3)), 2)
+	pMinus1Over2 = new(big.Int).Rsh(new(big.Int).Sub(bn256.P, big.NewInt(1)), 1)
+	// 3 / (9 + u) = (27 - 3u) / 82
+	twistB = func() *fp2 {
+		inv := new(big.Int).ModInverse(big.NewInt(82), bn256.P)
+		b := new(fp2)
+		b.c0.Mod(b.c0.Mul(big.NewInt(27), inv), bn256.P)
+		b.c1.Mod(b.c1.Mul(big.NewInt(-3), inv), bn256.P)
+		return b
+	}()
+)
+
+// Return the square root of a modulo p, or nil if there is none.
+// Since p = 3 mod 4 the root is a^((p + 1) / 4).
+func fpSqrt(a *big.Int) *big.Int {
+	y := new(big.Int).Exp(a, pPlus1Over4, bn256.P)
+	check := new(big.Int).Mul(y, y)
+	if check.Mod(check, bn256.P).Cmp(new(big.Int).Mod(a, bn256.P)) != 0 {
+		return nil
+	}
+	return y
+}
+
+// Return whether y is greater than -y.
+func fpGreater(y *big.Int) bool {
+	return y.Cmp(pMinus1Over2) > 0
+}
+
+// Struct fp2 implements c0 + c1 * u with u^2 = -1.
+type fp2 struct {
+	c0 big.Int
+	c1 big.Int
+}
+
+func (z *fp2) set(x *fp2) *fp2 {
+	z.c0.Set(&x.c0)
+	z.c1.Set(&x.c1)
+	return z
+}
+
+func (z *fp2) add(x, y *fp2) *fp2 {
+	z.c0.Mod(z.c0.Add(&x.c0, &y.c0), bn256.P)
+	z.c1.Mod(z.c1.Add(&x.c1, &y.c1), bn256.P)
+	return z
+}
+
+func (z *fp2) neg(x *fp2) *fp2 {
+	z.c0.Mod(z.c0.Neg(&x.c0), bn256.P)
+	z.c1.Mod(z.c1.Neg(&x.c1), bn256.P)
+	return z
+}
+
+func (z *fp2) mul(x, y *fp2) *fp2 {
+	// (a + bu)(c + du) = (ac - bd) + (ad + bc)u
+	ac := new(big.Int).Mul(&x.c0, &y.c0)
+	bd := new(big.Int).Mul(&x.c1, &y.c1)
+	ad := new(big.Int).Mul(&x.c0, &y.c1)
+	bc := new(big.Int).Mul(&x.c1, &y.c0)
+	z.c0.Mod(ac.Sub(ac, bd), bn256.P)
+	z.c1.Mod(ad.Add(ad, bc), bn256.P)
+	return z
+}
+
+func (z *fp2) exp(x *fp2, e *big.Int) *fp2 {
+	base := new(fp2).set(x)
+	res := new(fp2)
+	res.c0.SetInt64(1)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res.mul(res, res)
+		if e.Bit(i) == 1 {
+			res.mul(res, base)
+		}
+	}
+	return z.set(res)
+}
+
+func (z *fp2) equal(x *fp2) bool {
+	return z.c0.Cmp(&x.c0) == 0 && z.c1.Cmp(&x.c1) == 0
+}
+
+// Return whether z is greater than -z, comparing c1 first.
+func (z *fp2) greater() bool {
+	if z.c1.Sign() != 0 {
+		return fpGreater(&z.c1)
+	}
+	return fpGreater(&z.c0)
+}
+
+// Set z to a square root of x and return whether one exists,
+// with algorithm 9 of
+// G. Adj, F. Rodriguez-Henriquez.
+// Square Root Computation over Even Extension Fields.
+func (z *fp2) sqrt(x *fp2) bool {
+	minus1 := new(fp2)
+	minus1.c0.Sub(bn256.P, big.NewInt(1))
+	a1 := new(fp2).exp(x, pMinus3Over4)
+	alpha := new(fp2).mul(a1, a1)
+	alpha.mul(alpha, x)
+	// alpha^p is the conjugate of alpha.
+	a0 := new(fp2).set(alpha)
+	a0.c1.Mod(a0.c1.Neg(&a0.c1), bn256.P)
+	a0.mul(a0, alpha)
+	if a0.equal(minus1) {
+		return false
+	}
+	x0 := new(fp2).mul(a1, x)
+	res := new(fp2)
+	if alpha.equal(minus1) {
+		// u * x0
+		res.c0.Mod(res.c0.Neg(&x0.c1), bn256.P)
+		res.c1.Set(&x0.c0)
+	} else {
+		b := new(fp2).set(alpha)
+		b.c0.Mod(b.c0.Add(&b.c0, big.NewInt(1)), bn256.P)
+		b.exp(b, pMinus1Over2)
+		res.mul(b, x0)
+	}
+	check := new(fp2).mul(res, res)
+	if !check.equal(x) {
+		return false
+	}
+	z.set(res)
+	return true
+}
@@ -0,0 +1,238 @@
+// Package point implements the point formats of serialized messages and
+// the encodings of the points of bn256 in them, shared by both bn256
+// backends of the curve package. Other curves, such as BLS12-381, implement
+// their encodings in the curve package, and curve.Encode picks the one of
+// the point's curve, so messages record their curve next to the format.
+//
+// Besides the uncompressed form of Marshal, a bn256 point can be compressed
+// to its x-coordinate, 32 bytes in G1 and 64 bytes in G2, in the layout of
+// the Perpetual Powers of Tau response files: coordinates are big-endian,
+// G2 coordinates are written as c1 followed by c0, and the top two bits of
+// the first byte flag the greater y-coordinate and the point at infinity
+// respectively. Both bits are free since the modulus of the base field is
+// below 2^254.
+
+package point
+
+import (
+	"errors"
+	"math/big"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// Format selects the encoding of the points in a serialized message.
+type Format uint32
+
+const (
+	// The 64-byte G1 and 128-byte G2 encodings of Marshal.
+	Uncompressed Format = iota
+	// The 32-byte G1 and 64-byte G2 encodings of the x-coordinate.
+	Compressed
+)
+
+const (
+	// Size of a coordinate in bytes.
+	fpSize = 32
+	// Sizes of the compressed encodings.
+	G1Size = fpSize
+	G2Size = 2 * fpSize
+	// Sizes of the uncompressed encodings.
+	G1UncompressedSize = 2 * G1Size
+	G2UncompressedSize = 2 * G2Size
+
+	flagGreater  = 0x80
+	flagInfinity = 0x40
+)
+
+// Return whether the encoding b of Marshal is the point at infinity.
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Encode v as a big-endian coordinate.
+func padBytes(v *big.Int) []byte {
+	b := make([]byte, fpSize)
+	return v.FillBytes(b)
+}
+
+// Compress the point p in G1.
+func CompressG1(p *bn256.G1) []byte {
+	m := p.Marshal()
+	b := make([]byte, G1Size)
+	if isZero(m) {
+		b[0] = flagInfinity
+		return b
+	}
+	copy(b, m[:fpSize])
+	if fpGreater(new(big.Int).SetBytes(m[fpSize:])) {
+		b[0] |= flagGreater
+	}
+	return b
+}
+
+// Compress the point p in G2.
+func CompressG2(p *bn256.G2) []byte {
+	m := p.Marshal()
+	b := make([]byte, G2Size)
+	if isZero(m) {
+		b[0] = flagInfinity
+		return b
+	}
+	copy(b, m[:2*fpSize])
+	var y fp2
+	y.c1.SetBytes(m[2*fpSize : 3*fpSize])
+	y.c0.SetBytes(m[3*fpSize:])
+	if y.greater() {
+		b[0] |= flagGreater
+	}
+	return b
+}
+
+// Split the flags off the first byte of the compressed point b into a copy,
+// checking that the point at infinity is encoded canonically.
+func splitFlags(b []byte) (m []byte, greater bool, infinity bool, err error) {
+	m = make([]byte, len(b))
+	copy(m, b)
+	greater = m[0]&flagGreater != 0
+	infinity = m[0]&flagInfinity != 0
+	m[0] &^= flagGreater | flagInfinity
+	if infinity && (greater || !isZero(m)) {
+		return nil, false, false, errors.New("Point at infinity is not encoded as zero")
+	}
+	return m, greater, infinity, nil
+}
+
+// Decompress the point b in G1 into g, checking that it is on the curve.
+func DecompressG1(g *bn256.G1, b []byte) error {
+	if len(b) != G1Size {
+		return errors.New("Compressed point in G1 has a wrong length")
+	}
+	m, greater, infinity, err := splitFlags(b)
+	if err != nil {
+		return err
+	}
+	if infinity {
+		g.ScalarBaseMult(new(big.Int))
+		return nil
+	}
+	x := new(big.Int).SetBytes(m)
+	if x.Cmp(bn256.P) >= 0 {
+		return errors.New("Coordinate exceeds the modulus")
+	}
+	// y^2 = x^3 + 3
+	y := new(big.Int).Exp(x, big.NewInt(3), bn256.P)
+	y.Add(y, big.NewInt(3))
+	y = fpSqrt(y)
+	if y == nil {
+		return errors.New("Point is not on the curve")
+	}
+	if fpGreater(y) != greater {
+		y.Sub(bn256.P, y)
+	}
+	_, err = g.Unmarshal(append(m, padBytes(y)...))
+	return err
+}
+
+// Decompress the point b in G2 into g, checking that it is on the curve
+// and, as Unmarshal does, in the subgroup of order r.
+func DecompressG2(g *bn256.G2, b []byte) error {
+	if len(b) != G2Size {
+		return errors.New("Compressed point in G2 has a wrong length")
+	}
+	m, greater, infinity, err := splitFlags(b)
+	if err != nil {
+		return err
+	}
+	if infinity {
+		g.ScalarBaseMult(new(big.Int))
+		return nil
+	}
+	var x fp2
+	x.c1.SetBytes(m[:fpSize])
+	x.c0.SetBytes(m[fpSize:])
+	if x.c0.Cmp(bn256.P) >= 0 || x.c1.Cmp(bn256.P) >= 0 {
+		return errors.New("Coordinate exceeds the modulus")
+	}
+	// y^2 = x^3 + b / (9 + u)
+	var y fp2
+	y.mul(&x, &x)
+	y.mul(&y, &x)
+	y.add(&y, twistB)
+	if !y.sqrt(&y) {
+		return errors.New("Point is not on the curve")
+	}
+	if y.greater() != greater {
+		y.neg(&y)
+	}
+	m = append(m, padBytes(&y.c1)...)
+	_, err = g.Unmarshal(append(m, padBytes(&y.c0)...))
+	return err
+}
+
+// Check that the point g in G1 is on the curve.
+// Since the cofactor of G1 is 1, g is then in the subgroup of order r.
+func CheckG1(g *bn256.G1) error {
+	_, err := new(bn256.G1).Unmarshal(g.Marshal())
+	return err
+}
+
+// Check that the point g in G2 is on the curve and in the subgroup of order r,
+// both of which Unmarshal checks.
+func CheckG2(g *bn256.G2) error {
+	_, err := new(bn256.G2).Unmarshal(g.Marshal())
+	return err
+}
+
+// Encode the point p in G1 in the format f.
+func EncodeG1(p *bn256.G1, f Format) []byte {
+	if f == Compressed {
+		return CompressG1(p)
+	}
+	return p.Marshal()
+}
+
+// Encode the point p in G2 in the format f.
+func EncodeG2(p *bn256.G2, f Format) []byte {
+	if f == Compressed {
+		return CompressG2(p)
+	}
+	return p.Marshal()
+}
+
+// Decode the point b in G1 encoded in the format f into g.
+// The encoding must have the exact size of the format.
+func DecodeG1(g *bn256.G1, b []byte, f Format) error {
+	switch f {
+	case Uncompressed:
+		if len(b) != G1UncompressedSize {
+			return errors.New("Uncompressed point in G1 has a wrong length")
+		}
+		_, err := g.Unmarshal(b)
+		return err
+	case Compressed:
+		return DecompressG1(g, b)
+	}
+	return errors.New("Unknown point format")
+}
+
+// Decode the point b in G2 encoded in the format f into g.
+// The encoding must have the exact size of the format.
+func DecodeG2(g *bn256.G2, b []byte, f Format) error {
+	switch f {
+	case Uncompressed:
+		if len(b) != G2UncompressedSize {
+			return errors.New("Uncompressed point in G2 has a wrong length")
+		}
+		_, err := g.Unmarshal(b)
+		return err
+	case Compressed:
+		return DecompressG2(g, b)
+	}
+	return errors.New("Unknown point format")
+}
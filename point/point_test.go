@@ -0,0 +1,166 @@
+package point
+
+import (
+	"testing"
+
+	"bytes"
+	"crypto/rand"
+	"math/big"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+func TestCompressG1(t *testing.T) {
+	for i := 0; i < 32; i++ {
+		_, g, _ := bn256.RandomG1(rand.Reader)
+		if i == 0 {
+			g.ScalarBaseMult(new(big.Int))
+		}
+		// Cover both signs of y.
+		if i%2 == 1 {
+			g.Neg(g)
+		}
+		b := CompressG1(g)
+		if len(b) != G1Size {
+			t.Error("CompressG1 failed, expected: 32 bytes.")
+		}
+		d := new(bn256.G1)
+		if err := DecompressG1(d, b); err != nil || !bytes.Equal(d.Marshal(), g.Marshal()) {
+			t.Error("DecompressG1 failed, expected: true.")
+		}
+	}
+}
+
+func TestCompressG2(t *testing.T) {
+	for i := 0; i < 32; i++ {
+		_, g, _ := bn256.RandomG2(rand.Reader)
+		if i == 0 {
+			g.ScalarBaseMult(new(big.Int))
+		}
+		if i%2 == 1 {
+			g.Neg(g)
+		}
+		b := CompressG2(g)
+		if len(b) != G2Size {
+			t.Error("CompressG2 failed, expected: 64 bytes.")
+		}
+		d := new(bn256.G2)
+		if err := DecompressG2(d, b); err != nil || !bytes.Equal(d.Marshal(), g.Marshal()) {
+			t.Error("DecompressG2 failed, expected: true.")
+		}
+	}
+}
+
+func TestDecompressInvalid(t *testing.T) {
+	_, g1, _ := bn256.RandomG1(rand.Reader)
+	_, g2, _ := bn256.RandomG2(rand.Reader)
+	if DecompressG1(new(bn256.G1), CompressG1(g1)[1:]) == nil {
+		t.Error("DecompressG1 failed. Wrong length is accepted.")
+	}
+	if DecompressG2(new(bn256.G2), CompressG2(g2)[1:]) == nil {
+		t.Error("DecompressG2 failed. Wrong length is accepted.")
+	}
+	// Infinity with a nonzero coordinate or the sign flag.
+	b := make([]byte, G1Size)
+	b[0], b[G1Size-1] = flagInfinity, 1
+	if DecompressG1(new(bn256.G1), b) == nil {
+		t.Error("DecompressG1 failed. Noncanonical infinity is accepted.")
+	}
+	b = make([]byte, G2Size)
+	b[0] = flagInfinity | flagGreater
+	if DecompressG2(new(bn256.G2), b) == nil {
+		t.Error("DecompressG2 failed. Noncanonical infinity is accepted.")
+	}
+	// x = p exceeds the modulus.
+	b = padBytes(bn256.P)
+	if DecompressG1(new(bn256.G1), b) == nil {
+		t.Error("DecompressG1 failed. Coordinate above the modulus is accepted.")
+	}
+	if DecompressG2(new(bn256.G2), append(b, make([]byte, fpSize)...)) == nil {
+		t.Error("DecompressG2 failed. Coordinate above the modulus is accepted.")
+	}
+	// x^3 + 3 is not a square for x = 0 in G1 and x^3 + b' is not one for x = 3 in G2.
+	if DecompressG1(new(bn256.G1), make([]byte, G1Size)) == nil {
+		t.Error("DecompressG1 failed. Point off the curve is accepted.")
+	}
+	b = make([]byte, G2Size)
+	b[G2Size-1] = 3
+	if DecompressG2(new(bn256.G2), b) == nil {
+		t.Error("DecompressG2 failed. Point off the curve is accepted.")
+	}
+}
+
+// Find the compressed encoding of a point on the twist with a small x,
+// which lies outside of the subgroup of order r.
+func twistPoint() []byte {
+	var x, y fp2
+	for i := int64(1); ; i++ {
+		x.c0.SetInt64(i)
+		y.mul(&x, &x)
+		y.mul(&y, &x)
+		y.add(&y, twistB)
+		if y.sqrt(&y) {
+			return append(padBytes(&x.c1), padBytes(&x.c0)...)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	_, g1, _ := bn256.RandomG1(rand.Reader)
+	_, g2, _ := bn256.RandomG2(rand.Reader)
+	if CheckG1(g1) != nil {
+		t.Error("CheckG1 failed, expected: true.")
+	}
+	if CheckG2(g2) != nil {
+		t.Error("CheckG2 failed, expected: true.")
+	}
+	if CheckG2(new(bn256.G2).ScalarBaseMult(new(big.Int))) != nil {
+		t.Error("CheckG2 failed with infinity, expected: true.")
+	}
+	if DecompressG2(new(bn256.G2), twistPoint()) == nil {
+		t.Error("DecompressG2 failed. Point outside of the subgroup is accepted.")
+	}
+}
+
+func TestEncode(t *testing.T) {
+	_, g1, _ := bn256.RandomG1(rand.Reader)
+	_, g2, _ := bn256.RandomG2(rand.Reader)
+	for _, f := range []Format{Uncompressed, Compressed} {
+		d1, d2 := new(bn256.G1), new(bn256.G2)
+		if err := DecodeG1(d1, EncodeG1(g1, f), f); err != nil || !bytes.Equal(d1.Marshal(), g1.Marshal()) {
+			t.Error("DecodeG1 failed, expected: true.")
+		}
+		if err := DecodeG2(d2, EncodeG2(g2, f), f); err != nil || !bytes.Equal(d2.Marshal(), g2.Marshal()) {
+			t.Error("DecodeG2 failed, expected: true.")
+		}
+	}
+	for _, f := range []Format{Uncompressed, Compressed} {
+		if DecodeG1(new(bn256.G1), append(EncodeG1(g1, f), 0), f) == nil {
+			t.Error("DecodeG1 failed. Trailing bytes are accepted.")
+		}
+		if DecodeG2(new(bn256.G2), append(EncodeG2(g2, f), 0), f) == nil {
+			t.Error("DecodeG2 failed. Trailing bytes are accepted.")
+		}
+	}
+	if DecodeG1(new(bn256.G1), EncodeG1(g1, Compressed), Format(2)) == nil {
+		t.Error("DecodeG1 failed. Unknown format is accepted.")
+	}
+}
+
+func BenchmarkDecompressG1(b *testing.B) {
+	_, g, _ := bn256.RandomG1(rand.Reader)
+	m := CompressG1(g)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DecompressG1(new(bn256.G1), m)
+	}
+}
+
+func BenchmarkDecompressG2(b *testing.B) {
+	_, g, _ := bn256.RandomG2(rand.Reader)
+	m := CompressG2(g)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DecompressG2(new(bn256.G2), m)
+	}
+}
@@ -0,0 +1,267 @@
+// Package poly implements polynomials over the scalar field of a curve,
+// i.e. the integers modulo its order, with the arithmetic shared by
+// the commitment schemes and the protocols built on them.
+
+package poly
+
+import (
+	"errors"
+	"io"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/ntt"
+)
+
+// Polynomial implements a polynomial by its coefficients in increasing
+// degree, i.e. p[i] is the coefficient of x^i.
+// Any []curve.Scalar can be used as a Polynomial and vice versa.
+// Results are allocated in the curve of the inputs, and never alias them.
+type Polynomial []curve.Scalar
+
+// Generate a uniformly random polynomial with n coefficients over the curve c.
+func Random(c curve.Curve, r io.Reader, n int) (Polynomial, error) {
+	p := curve.NewScalars(c, n)
+	for i := range p {
+		if _, err := p[i].SetRandom(r); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Return the degree of p, which is -1 for the zero polynomial.
+func (p Polynomial) Degree() int {
+	d := len(p) - 1
+	for d >= 0 && p[d].IsZero() {
+		d--
+	}
+	return d
+}
+
+// Return p without its leading zero coefficients.
+func (p Polynomial) Trim() Polynomial {
+	return p[:p.Degree()+1]
+}
+
+// Return a copy of p.
+func (p Polynomial) Clone() Polynomial {
+	return curve.CloneScalars(p)
+}
+
+// Return whether p and q are the same polynomial, ignoring leading zeros.
+func (p Polynomial) Equal(q Polynomial) bool {
+	p, q = p.Trim(), q.Trim()
+	if len(p) != len(q) {
+		return false
+	}
+	for i := range p {
+		if !p[i].Equal(q[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Evaluate p at x with Horner's rule.
+func (p Polynomial) Evaluate(x curve.Scalar) curve.Scalar {
+	res := x.Curve().NewScalar()
+	for j := len(p) - 1; j >= 0; j-- {
+		res.Mul(res, x)
+		res.Add(res, p[j])
+	}
+	return res
+}
+
+// Evaluate p at every point.
+func (p Polynomial) EvaluateMulti(points []curve.Scalar) []curve.Scalar {
+	ret := make([]curve.Scalar, len(points))
+	for k := range points {
+		ret[k] = p.Evaluate(points[k])
+	}
+	return ret
+}
+
+// Return the sum of a and b.
+func Add(a Polynomial, b Polynomial) Polynomial {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	ret := a.Clone()
+	for i := range b {
+		ret[i].Add(ret[i], b[i])
+	}
+	return ret
+}
+
+// Return the difference of a and b.
+func Sub(a Polynomial, b Polynomial) Polynomial {
+	ret := a.Clone()
+	for i := len(a); i < len(b); i++ {
+		ret = append(ret, b[i].Curve().NewScalar())
+	}
+	for i := range b {
+		ret[i].Sub(ret[i], b[i])
+	}
+	return ret
+}
+
+// Return a multiplied by the scalar c.
+func Scale(a Polynomial, c curve.Scalar) Polynomial {
+	ret := make(Polynomial, len(a))
+	for i := range a {
+		ret[i] = c.Curve().NewScalar().Mul(a[i], c)
+	}
+	return ret
+}
+
+// Return the product of a and b, computed with ntt.Multiply.
+func Mul(a Polynomial, b Polynomial) Polynomial {
+	ret, err := ntt.Multiply(a, b)
+	if err == nil {
+		return ret
+	}
+	// Only products beyond the 2-adicity of the field get here.
+	c := a[0].Curve()
+	ret = curve.NewScalars(c, len(a)+len(b)-1)
+	term := c.NewScalar()
+	for i := range a {
+		for j := range b {
+			ret[i+j].Add(ret[i+j], term.Mul(a[i], b[j]))
+		}
+	}
+	return ret
+}
+
+// Divide a by b with long division. The remainder has len(b) - 1
+// coefficients after trimming b, unless a is shorter, in which case it is a.
+func DivRem(a Polynomial, b Polynomial) (quotient Polynomial, remainder Polynomial, err error) {
+	b = b.Trim()
+	if len(b) == 0 {
+		return nil, nil, errors.New("Division by the zero polynomial")
+	}
+	remainder = a.Clone()
+	if len(a) < len(b) {
+		return Polynomial{}, remainder, nil
+	}
+	c := b[0].Curve()
+	lead := c.NewScalar().Inverse(b[len(b)-1])
+	quotient = curve.NewScalars(c, len(a)-len(b)+1)
+	term := c.NewScalar()
+	for i := len(quotient) - 1; i >= 0; i-- {
+		quotient[i].Mul(remainder[i+len(b)-1], lead)
+		for j := range b {
+			remainder[i+j].Sub(remainder[i+j], term.Mul(quotient[i], b[j]))
+		}
+	}
+	return quotient, remainder[:len(b)-1], nil
+}
+
+// Divide a by (x - z) with synthetic division, returning the quotient and a(z).
+func DivLinear(a Polynomial, z curve.Scalar) (quotient Polynomial, res curve.Scalar) {
+	c := z.Curve()
+	if len(a) == 0 {
+		return Polynomial{}, c.NewScalar()
+	}
+	// a(x) - a(z) always divides (x - z) since the latter is a root of the former.
+	quotient = make(Polynomial, len(a)-1)
+	res = c.NewScalar().Set(a[len(a)-1])
+	for j := len(a) - 2; j >= 0; j-- {
+		// q_j = a_(j + 1) + q_(j + 1) * z
+		quotient[j] = c.NewScalar().Set(res)
+		res.Mul(res, z)
+		res.Add(res, a[j])
+	}
+	return quotient, res
+}
+
+// Return the vanishing polynomial of points over the curve c,
+// i.e. the product of (x - points[i]).
+// Large products are multiplied pairwise in a product tree with Mul.
+func Vanishing(c curve.Curve, points []curve.Scalar) Polynomial {
+	if len(points) == 0 {
+		return Polynomial{c.NewScalar().SetOne()}
+	}
+	level := make([]Polynomial, len(points))
+	for i := range points {
+		level[i] = Polynomial{c.NewScalar().Neg(points[i]), c.NewScalar().SetOne()}
+	}
+	for len(level) > 1 {
+		next := make([]Polynomial, (len(level)+1)/2)
+		for i := range next {
+			if 2*i+1 < len(level) {
+				next[i] = Mul(level[2*i], level[2*i+1])
+			} else {
+				next[i] = level[2*i]
+			}
+		}
+		level = next
+	}
+	return level[0]
+}
+
+// Divide a by the vanishing polynomial of points over the curve c.
+// The remainder agrees with a at every point.
+func DivVanishing(c curve.Curve, a Polynomial, points []curve.Scalar) (quotient Polynomial, remainder Polynomial) {
+	// The vanishing polynomial is monic, so the division cannot fail.
+	quotient, remainder, _ = DivRem(a, Vanishing(c, points))
+	return quotient, remainder
+}
+
+// Return the Lagrange coefficients of xs at z, i.e.
+// prod_(j != i) (z - xs[j]) / (xs[i] - xs[j]) for every i,
+// so that sum_i ys[i] * ret[i] is the interpolation of (xs, ys) at z.
+// The elements of xs must be distinct.
+func LagrangeCoefficients(xs []curve.Scalar, z curve.Scalar) []curve.Scalar {
+	c := z.Curve()
+	num := make([]curve.Scalar, len(xs))
+	den := make([]curve.Scalar, len(xs))
+	term := c.NewScalar()
+	for i := range xs {
+		num[i] = c.NewScalar().SetOne()
+		den[i] = c.NewScalar().SetOne()
+		for j := range xs {
+			if i != j {
+				num[i].Mul(num[i], term.Sub(z, xs[j]))
+				den[i].Mul(den[i], term.Sub(xs[i], xs[j]))
+			}
+		}
+	}
+	curve.BatchInvert(den)
+	for i := range num {
+		num[i].Mul(num[i], den[i])
+	}
+	return num
+}
+
+// Return the polynomial with fewer than len(xs) coefficients that
+// evaluates to ys[i] at xs[i] for every i.
+func Interpolate(xs []curve.Scalar, ys []curve.Scalar) (Polynomial, error) {
+	if len(xs) != len(ys) {
+		return nil, errors.New("Number of points does not match the evaluations")
+	}
+	if len(xs) == 0 {
+		return Polynomial{}, nil
+	}
+	c := xs[0].Curve()
+	z := Vanishing(c, xs)
+	// p = sum_i ys[i] / z'(xs[i]) * z / (x - xs[i])
+	quotients := make([]Polynomial, len(xs))
+	den := make([]curve.Scalar, len(xs))
+	for i := range xs {
+		quotients[i], _ = DivLinear(z, xs[i])
+		den[i] = quotients[i].Evaluate(xs[i])
+		if den[i].IsZero() {
+			return nil, errors.New("Points are not distinct")
+		}
+	}
+	curve.BatchInvert(den)
+	ret := curve.NewScalars(c, len(xs))
+	w, term := c.NewScalar(), c.NewScalar()
+	for i := range xs {
+		w.Mul(ys[i], den[i])
+		for j := range quotients[i] {
+			ret[j].Add(ret[j], term.Mul(quotients[i][j], w))
+		}
+	}
+	return ret, nil
+}
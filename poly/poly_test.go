@@ -0,0 +1,153 @@
+package poly
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+)
+
+const (
+	deg = 128
+)
+
+var curves = []curve.Curve{curve.BN256, curve.BN256Google, curve.BLS12381}
+
+func randomScalar(c curve.Curve) curve.Scalar {
+	e, _ := c.NewScalar().SetRandom(rand.Reader)
+	return e
+}
+
+func TestArithmetic(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			a, err := Random(c, rand.Reader, deg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := Random(c, rand.Reader, deg/2)
+			z := randomScalar(c)
+			expected := c.NewScalar()
+			if !Add(a, b).Evaluate(z).Equal(expected.Add(a.Evaluate(z), b.Evaluate(z))) {
+				t.Error("Add failed. Wrong evaluation result.")
+			}
+			if !Sub(b, a).Evaluate(z).Equal(expected.Sub(b.Evaluate(z), a.Evaluate(z))) {
+				t.Error("Sub failed. Wrong evaluation result.")
+			}
+			if !Mul(a, b).Evaluate(z).Equal(expected.Mul(a.Evaluate(z), b.Evaluate(z))) {
+				t.Error("Mul failed. Wrong evaluation result.")
+			}
+			if !Scale(a, z).Evaluate(z).Equal(expected.Mul(a.Evaluate(z), z)) {
+				t.Error("Scale failed. Wrong evaluation result.")
+			}
+			if Sub(a, a).Degree() != -1 || !Sub(a, a).Equal(Polynomial{}) {
+				t.Error("Sub failed. Expected the zero polynomial.")
+			}
+			if (Polynomial{curve.NewScalar(c, 1), curve.NewScalar(c, 2), c.NewScalar()}).Degree() != 1 {
+				t.Error("Degree failed. Expected: 1")
+			}
+			evals := a.EvaluateMulti([]curve.Scalar{z, expected})
+			if !evals[0].Equal(a.Evaluate(z)) || !evals[1].Equal(a.Evaluate(expected)) {
+				t.Error("EvaluateMulti failed. Wrong evaluation result.")
+			}
+			// Results must not alias the inputs.
+			s := Add(a, Polynomial{})
+			s[0].SetZero()
+			if a[0].IsZero() {
+				t.Error("Add failed. Result aliases the input.")
+			}
+		})
+	}
+}
+
+func TestDivision(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			a, _ := Random(c, rand.Reader, deg)
+			b, _ := Random(c, rand.Reader, deg/3)
+			q, r, err := DivRem(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(r) != len(b)-1 || !Add(Mul(q, b), r).Equal(a) {
+				t.Error("DivRem failed. Expected: a = q * b + r.")
+			}
+			if _, _, err = DivRem(a, Polynomial{c.NewScalar()}); err == nil {
+				t.Error("DivRem accepted the zero polynomial.")
+			}
+			z := randomScalar(c)
+			ql, res := DivLinear(a, z)
+			if !res.Equal(a.Evaluate(z)) {
+				t.Error("DivLinear failed. Wrong evaluation result.")
+			}
+			lin := Polynomial{c.NewScalar().Neg(z), curve.NewScalar(c, 1)}
+			if !Add(Mul(ql, lin), Polynomial{res}).Equal(a) {
+				t.Error("DivLinear failed. Expected: a = q * (x - z) + a(z).")
+			}
+			points, _ := Random(c, rand.Reader, deg/4)
+			v := Vanishing(c, points)
+			if v.Degree() != len(points) || !v.EvaluateMulti(points)[len(points)-1].IsZero() {
+				t.Error("Vanishing failed. Expected a root at every point.")
+			}
+			if !Vanishing(c, nil).Equal(Polynomial{curve.NewScalar(c, 1)}) {
+				t.Error("Vanishing failed. Expected 1 without points.")
+			}
+			qv, rv := DivVanishing(c, a, points)
+			if !Add(Mul(qv, v), rv).Equal(a) {
+				t.Error("DivVanishing failed. Expected: a = q * v + r.")
+			}
+			for k := range points {
+				if !rv.Evaluate(points[k]).Equal(a.Evaluate(points[k])) {
+					t.Error("DivVanishing failed. Remainder does not agree with a.")
+				}
+			}
+		})
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			a, _ := Random(c, rand.Reader, deg)
+			xs, _ := Random(c, rand.Reader, deg)
+			p, err := Interpolate(xs, a.EvaluateMulti(xs))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !p.Equal(a) {
+				t.Error("Interpolate failed. Wrong polynomial.")
+			}
+			z := randomScalar(c)
+			lambda := LagrangeCoefficients(xs, z)
+			sum, term := c.NewScalar(), c.NewScalar()
+			for i := range xs {
+				sum.Add(sum, term.Mul(lambda[i], a.Evaluate(xs[i])))
+			}
+			if !sum.Equal(a.Evaluate(z)) {
+				t.Error("LagrangeCoefficients failed. Wrong evaluation result.")
+			}
+			xs[1].Set(xs[0])
+			if _, err = Interpolate(xs, a.EvaluateMulti(xs)); err == nil {
+				t.Error("Interpolate accepted duplicate points.")
+			}
+		})
+	}
+}
+
+func BenchmarkInterpolate(b *testing.B) {
+	a, _ := Random(curve.BN256, rand.Reader, deg)
+	xs, _ := Random(curve.BN256, rand.Reader, deg)
+	ys := a.EvaluateMulti(xs)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		Interpolate(xs, ys)
+	}
+}
+
+func BenchmarkVanishing(b *testing.B) {
+	points, _ := Random(curve.BN256, rand.Reader, 1<<10)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		Vanishing(curve.BN256, points)
+	}
+}
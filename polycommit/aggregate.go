@@ -0,0 +1,133 @@
+package polycommit
+
+// This file implements the opening of many committed polynomials at the same
+// point with a single witness. The polynomials are combined with the powers
+// of a challenge gamma derived by Fiat-Shamir from the commitments, the point
+// and the claimed evaluations, and the witness opens the combination.
+
+import (
+	"errors"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/poly"
+	"github.com/zhtluo/libpolycrypto/transcript"
+)
+
+// Derive the challenge with the label from the transcript t
+// as a scalar of the curve c.
+func challengeScalar(c curve.Curve, t *transcript.Transcript, label string) curve.Scalar {
+	return c.NewScalar().SetBytesReduce(t.ChallengeScalarBytes(label))
+}
+
+// Derive the challenge gamma from the serialized commitments, the point i
+// and the evaluations res.
+func aggregateChallenge(commits [][]byte, i curve.Scalar, res []curve.Scalar) curve.Scalar {
+	t := transcript.New("polycommit-aggregate")
+	t.AppendUint64("n", uint64(len(commits)))
+	for k := range commits {
+		t.AppendMessage("commit", commits[k])
+	}
+	t.AppendScalar("point", i)
+	for k := range res {
+		t.AppendScalar("eval", res[k])
+	}
+	return challengeScalar(i.Curve(), t, "gamma")
+}
+
+// Return the first n powers of gamma.
+func powers(gamma curve.Scalar, n int) []curve.Scalar {
+	ret := curve.NewScalars(gamma.Curve(), n)
+	if n > 0 {
+		ret[0].SetOne()
+	}
+	for k := 1; k < n; k++ {
+		ret[k].Mul(ret[k-1], gamma)
+	}
+	return ret
+}
+
+// Evaluate every polynomial of polys at i and combine them with the powers
+// of the challenge.
+func aggregatePolys(polys [][]curve.Scalar, commits [][]byte, i curve.Scalar) (res []curve.Scalar, combined []curve.Scalar) {
+	res = make([]curve.Scalar, len(polys))
+	for k := range polys {
+		res[k] = poly.Polynomial(polys[k]).Evaluate(i)
+	}
+	gamma := powers(aggregateChallenge(commits, i, res), len(polys))
+	for k := range polys {
+		combined = poly.Add(combined, poly.Scale(polys[k], gamma[k]))
+	}
+	return res, combined
+}
+
+// Combine the evaluations res with the powers of the challenge.
+func aggregateRes(commits [][]byte, i curve.Scalar, res []curve.Scalar) (gamma []curve.Scalar, sum curve.Scalar) {
+	gamma = powers(aggregateChallenge(commits, i, res), len(res))
+	sum = i.Curve().NewScalar()
+	term := i.Curve().NewScalar()
+	for k := range res {
+		sum.Add(sum, term.Mul(res[k], gamma[k]))
+	}
+	return gamma, sum
+}
+
+// Serialize every commitment.
+func marshalPoints(ps []curve.Point) [][]byte {
+	ret := make([][]byte, len(ps))
+	for k := range ps {
+		ret[k] = ps[k].Marshal()
+	}
+	return ret
+}
+
+// Create a single witness g1 to the evaluations res of the polynomials polys
+// with the commitments commits at i.
+func (pk *Pk) CreateAggregateWitness(polys [][]curve.Scalar, commits []curve.Point, i curve.Scalar) (res []curve.Scalar, g1 curve.Point, err error) {
+	if len(polys) == 0 || len(polys) != len(commits) {
+		return nil, nil, errors.New("Number of polynomials does not match the commitments")
+	}
+	for k := range polys {
+		if err = pk.checkPoly(polys[k]); err != nil {
+			return nil, nil, err
+		}
+	}
+	res, combined := aggregatePolys(polys, marshalPoints(commits), i)
+	quotient, _ := poly.DivLinear(combined, i)
+	return res, MultiExpG1(pk.Curve, pk.G1P, quotient), nil
+}
+
+// Verify the evaluations res at i of the polynomials with the commitments
+// commits against the aggregated witness g1.
+func (vk *VerifierKey) VerifyAggregateEval(commits []curve.Point, i curve.Scalar, res []curve.Scalar, g1 curve.Point) bool {
+	if len(commits) == 0 || len(commits) != len(res) {
+		return false
+	}
+	gamma, sum := aggregateRes(marshalPoints(commits), i, res)
+	return vk.VerifyEval(MultiExpG2(vk.Curve, commits, gamma), i, sum, g1)
+}
+
+// Create a single witness g1 to the evaluations res of the polynomials polys
+// with the commitments commits in G1 at i.
+func (pk *G1Pk) CreateAggregateWitness(polys [][]curve.Scalar, commits []curve.Point, i curve.Scalar) (res []curve.Scalar, g1 curve.Point, err error) {
+	if len(polys) == 0 || len(polys) != len(commits) {
+		return nil, nil, errors.New("Number of polynomials does not match the commitments")
+	}
+	for k := range polys {
+		if err = pk.checkPoly(polys[k]); err != nil {
+			return nil, nil, err
+		}
+	}
+	res, combined := aggregatePolys(polys, marshalPoints(commits), i)
+	quotient, _ := poly.DivLinear(combined, i)
+	return res, MultiExpG1(pk.Curve, pk.G1P, quotient), nil
+}
+
+// Verify the evaluations res at i of the polynomials with the commitments
+// commits in G1 against the aggregated witness g1.
+func (vk *VerifierKey) VerifyAggregateEvalG1(commits []curve.Point, i curve.Scalar, res []curve.Scalar, g1 curve.Point) bool {
+	if len(commits) == 0 || len(commits) != len(res) {
+		return false
+	}
+	gamma, sum := aggregateRes(marshalPoints(commits), i, res)
+	return vk.VerifyEvalG1(MultiExpG1(vk.Curve, commits, gamma), i, sum, g1)
+}
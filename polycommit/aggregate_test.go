@@ -0,0 +1,125 @@
+package polycommit
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+)
+
+const (
+	aggregateSize = 8
+)
+
+func TestAggregateWitness(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			vk, _ := pk.VerifierKey()
+			polys := make([][]curve.Scalar, aggregateSize)
+			commits := make([]curve.Point, aggregateSize)
+			for k := range polys {
+				// Polynomials of different degrees.
+				polys[k] = generatePoly(c, rand.Reader)[:deg-k]
+				com, err := pk.Commit(polys[k])
+				if err != nil {
+					t.Error(err.Error())
+				}
+				commits[k] = com
+			}
+			i := randomElement(c, rand.Reader)
+			res, g1, err := pk.CreateAggregateWitness(polys, commits, i)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			for k := range polys {
+				if !res[k].Equal(Evaluate(polys[k], i)) {
+					t.Error("CreateAggregateWitness failed. Wrong evaluation result.")
+				}
+			}
+			if vk.VerifyAggregateEval(commits, i, res, g1) != true {
+				t.Error("VerifyAggregateEval failed, expected: true.")
+			}
+			if vk.VerifyAggregateEval(commits, randomElement(c, rand.Reader), res, g1) != false {
+				t.Error("VerifyAggregateEval failed, expected: false.")
+			}
+			if vk.VerifyAggregateEval(commits[1:], i, res[1:], g1) != false {
+				t.Error("VerifyAggregateEval failed with a missing polynomial, expected: false.")
+			}
+			res[aggregateSize-1].SetRandom(rand.Reader)
+			if vk.VerifyAggregateEval(commits, i, res, g1) != false {
+				t.Error("VerifyAggregateEval failed, expected: false.")
+			}
+			if _, _, err = pk.CreateAggregateWitness(polys, commits[1:], i); err == nil {
+				t.Error("CreateAggregateWitness accepted mismatched commitments.")
+			}
+		})
+	}
+}
+
+func TestAggregateWitnessG1(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk G1Pk
+			pk.Setup(c, rand.Reader, deg)
+			vk, _ := pk.VerifierKey()
+			polys := make([][]curve.Scalar, aggregateSize)
+			commits := make([]curve.Point, aggregateSize)
+			for k := range polys {
+				polys[k] = generatePoly(c, rand.Reader)
+				com, err := pk.Commit(polys[k])
+				if err != nil {
+					t.Error(err.Error())
+				}
+				commits[k] = com
+			}
+			i := randomElement(c, rand.Reader)
+			res, g1, err := pk.CreateAggregateWitness(polys, commits, i)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if vk.VerifyAggregateEvalG1(commits, i, res, g1) != true {
+				t.Error("VerifyAggregateEvalG1 failed, expected: true.")
+			}
+			res[0].SetRandom(rand.Reader)
+			if vk.VerifyAggregateEvalG1(commits, i, res, g1) != false {
+				t.Error("VerifyAggregateEvalG1 failed, expected: false.")
+			}
+		})
+	}
+}
+
+func BenchmarkCreateAggregateWitness(b *testing.B) {
+	var pk Pk
+	pk.Setup(curve.BN256, rand.Reader, deg)
+	polys := make([][]curve.Scalar, aggregateSize)
+	commits := make([]curve.Point, aggregateSize)
+	for k := range polys {
+		polys[k] = generatePoly(pk.Curve, rand.Reader)
+		commits[k], _ = pk.Commit(polys[k])
+	}
+	i := randomElement(pk.Curve, rand.Reader)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		pk.CreateAggregateWitness(polys, commits, i)
+	}
+}
+
+func BenchmarkVerifyAggregateEval(b *testing.B) {
+	var pk Pk
+	pk.Setup(curve.BN256, rand.Reader, deg)
+	vk, _ := pk.VerifierKey()
+	polys := make([][]curve.Scalar, aggregateSize)
+	commits := make([]curve.Point, aggregateSize)
+	for k := range polys {
+		polys[k] = generatePoly(pk.Curve, rand.Reader)
+		commits[k], _ = pk.Commit(polys[k])
+	}
+	i := randomElement(pk.Curve, rand.Reader)
+	res, g1, _ := pk.CreateAggregateWitness(polys, commits, i)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		vk.VerifyAggregateEval(commits, i, res, g1)
+	}
+}
@@ -0,0 +1,142 @@
+package polycommit
+
+// This file implements the batch verification of evaluation proofs.
+// Every proof is scaled by a random coefficient and all of them are folded
+// into a single multi-pairing, so that a batch with an invalid proof passes
+// only with negligible probability.
+
+import (
+	"crypto/rand"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+)
+
+// Struct EvalProof implements the claim that the polynomial committed in
+// Commit evaluates to Res at I, with the witness Witness.
+type EvalProof struct {
+	Commit  curve.Point
+	I       curve.Scalar
+	Res     curve.Scalar
+	Witness curve.Point
+}
+
+// Struct G1EvalProof implements EvalProof for commitments in G1.
+type G1EvalProof struct {
+	Commit  curve.Point
+	I       curve.Scalar
+	Res     curve.Scalar
+	Witness curve.Point
+}
+
+// Generate n random coefficients of the curve c for a batch.
+func batchCoefficients(c curve.Curve, n int) ([]curve.Scalar, error) {
+	rho := curve.NewScalars(c, n)
+	for k := range rho {
+		if _, err := rho[k].SetRandom(rand.Reader); err != nil {
+			return nil, err
+		}
+	}
+	return rho, nil
+}
+
+// Verify all the evaluation proofs at once.
+func (vk *VerifierKey) VerifyEvalBatch(proofs []EvalProof) bool {
+	if len(proofs) == 0 {
+		return true
+	}
+	rho, err := batchCoefficients(vk.Curve, len(proofs))
+	if err != nil {
+		return false
+	}
+	// e(sum r_k w_k, g^alpha) * e(sum r_k (y_k g - z_k w_k), g) * e(-g, sum r_k C_k) = 1
+	k := vk.Curve
+	n := len(proofs)
+	w := make([]curve.Point, n)
+	c := make([]curve.Point, n)
+	points := make([]curve.Point, n+1)
+	scalars := curve.NewScalars(k, n+1)
+	sumRes := k.NewScalar()
+	term := k.NewScalar()
+	for j := range proofs {
+		w[j] = proofs[j].Witness
+		c[j] = proofs[j].Commit
+		points[j] = proofs[j].Witness
+		scalars[j].Neg(term.Mul(rho[j], proofs[j].I))
+		sumRes.Add(sumRes, term.Mul(rho[j], proofs[j].Res))
+	}
+	points[n] = vk.G1
+	scalars[n].Set(sumRes)
+	return k.PairingCheck(
+		[]curve.Point{MultiExpG1(k, w, rho), MultiExpG1(k, points, scalars), k.NewG1().Neg(vk.G1)},
+		[]curve.Point{vk.G2Alpha, vk.G2, MultiExpG2(k, c, rho)})
+}
+
+// Verify all the evaluation proofs with commitments in G1 at once.
+func (vk *VerifierKey) VerifyEvalBatchG1(proofs []G1EvalProof) bool {
+	if len(proofs) == 0 {
+		return true
+	}
+	rho, err := batchCoefficients(vk.Curve, len(proofs))
+	if err != nil {
+		return false
+	}
+	// e(sum r_k (C_k - y_k g + z_k w_k), g) * e(-sum r_k w_k, g^alpha) = 1
+	c := vk.Curve
+	n := len(proofs)
+	w := make([]curve.Point, n)
+	points := make([]curve.Point, 2*n+1)
+	scalars := curve.NewScalars(c, 2*n+1)
+	sumRes := c.NewScalar()
+	term := c.NewScalar()
+	for k := range proofs {
+		w[k] = proofs[k].Witness
+		points[2*k] = proofs[k].Commit
+		scalars[2*k].Set(rho[k])
+		points[2*k+1] = proofs[k].Witness
+		scalars[2*k+1].Mul(rho[k], proofs[k].I)
+		sumRes.Add(sumRes, term.Mul(rho[k], proofs[k].Res))
+	}
+	points[2*n] = vk.G1
+	scalars[2*n].Neg(sumRes)
+	sw := MultiExpG1(c, w, rho)
+	return c.PairingCheck(
+		[]curve.Point{MultiExpG1(c, points, scalars), sw.Neg(sw)},
+		[]curve.Point{vk.G2, vk.G2Alpha})
+}
+
+// Return the indices in [0, n) of the invalid proofs found by bisection with
+// check. Halves that pass the batch check are discarded whole.
+func bisect(n int, check func(lo, hi int) bool) []int {
+	bad := make([]int, 0)
+	var search func(lo, hi int)
+	search = func(lo, hi int) {
+		if lo >= hi || check(lo, hi) {
+			return
+		}
+		if hi-lo == 1 {
+			bad = append(bad, lo)
+			return
+		}
+		mid := (lo + hi) / 2
+		search(lo, mid)
+		search(mid, hi)
+	}
+	search(0, n)
+	return bad
+}
+
+// Return the indices of the invalid evaluation proofs in increasing order,
+// which is empty if the whole batch verifies.
+func (vk *VerifierKey) FindInvalidEvals(proofs []EvalProof) []int {
+	return bisect(len(proofs), func(lo, hi int) bool {
+		return vk.VerifyEvalBatch(proofs[lo:hi])
+	})
+}
+
+// Return the indices of the invalid evaluation proofs with commitments in G1
+// in increasing order, which is empty if the whole batch verifies.
+func (vk *VerifierKey) FindInvalidEvalsG1(proofs []G1EvalProof) []int {
+	return bisect(len(proofs), func(lo, hi int) bool {
+		return vk.VerifyEvalBatchG1(proofs[lo:hi])
+	})
+}
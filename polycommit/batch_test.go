@@ -0,0 +1,110 @@
+package polycommit
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"reflect"
+)
+
+const (
+	batchSize = 16
+)
+
+// Generate batchSize valid evaluation proofs, each on its own polynomial.
+func generateEvalProofs(pk *Pk) []EvalProof {
+	proofs := make([]EvalProof, batchSize)
+	for k := range proofs {
+		poly := generatePoly(pk.Curve, rand.Reader)[:deg/4]
+		proofs[k].Commit, _ = pk.Commit(poly)
+		proofs[k].I = randomElement(pk.Curve, rand.Reader)
+		proofs[k].Res, proofs[k].Witness, _ = pk.CreateWitness(poly, proofs[k].I)
+	}
+	return proofs
+}
+
+func TestVerifyEvalBatch(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			vk, _ := pk.VerifierKey()
+			proofs := generateEvalProofs(&pk)
+			if vk.VerifyEvalBatch(proofs) != true {
+				t.Error("VerifyEvalBatch failed, expected: true.")
+			}
+			if vk.VerifyEvalBatch(nil) != true {
+				t.Error("VerifyEvalBatch failed on an empty batch, expected: true.")
+			}
+			if len(vk.FindInvalidEvals(proofs)) != 0 {
+				t.Error("FindInvalidEvals failed, expected no invalid proof.")
+			}
+			proofs[3].Res.SetRandom(rand.Reader)
+			proofs[12].I.SetRandom(rand.Reader)
+			if vk.VerifyEvalBatch(proofs) != false {
+				t.Error("VerifyEvalBatch failed, expected: false.")
+			}
+			if bad := vk.FindInvalidEvals(proofs); !reflect.DeepEqual(bad, []int{3, 12}) {
+				t.Errorf("FindInvalidEvals failed. Expected: [3 12], Got: %v", bad)
+			}
+			// Two invalid proofs must not cancel each other out.
+			proofs = generateEvalProofs(&pk)
+			proofs[0].Witness, proofs[1].Witness = proofs[1].Witness, proofs[0].Witness
+			if vk.VerifyEvalBatch(proofs) != false {
+				t.Error("VerifyEvalBatch failed with swapped witnesses, expected: false.")
+			}
+		})
+	}
+}
+
+func TestVerifyEvalBatchG1(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk G1Pk
+			pk.Setup(c, rand.Reader, deg)
+			vk, _ := pk.VerifierKey()
+			proofs := make([]G1EvalProof, batchSize)
+			for k := range proofs {
+				poly := generatePoly(c, rand.Reader)[:deg/4]
+				proofs[k].Commit, _ = pk.Commit(poly)
+				proofs[k].I = randomElement(c, rand.Reader)
+				proofs[k].Res, proofs[k].Witness, _ = pk.CreateWitness(poly, proofs[k].I)
+			}
+			if vk.VerifyEvalBatchG1(proofs) != true {
+				t.Error("VerifyEvalBatchG1 failed, expected: true.")
+			}
+			proofs[batchSize-1].Res.SetRandom(rand.Reader)
+			if vk.VerifyEvalBatchG1(proofs) != false {
+				t.Error("VerifyEvalBatchG1 failed, expected: false.")
+			}
+			if bad := vk.FindInvalidEvalsG1(proofs); !reflect.DeepEqual(bad, []int{batchSize - 1}) {
+				t.Errorf("FindInvalidEvalsG1 failed. Expected: [%d], Got: %v", batchSize-1, bad)
+			}
+		})
+	}
+}
+
+func BenchmarkVerifyEvalBatch(b *testing.B) {
+	var pk Pk
+	pk.Setup(curve.BN256, rand.Reader, deg)
+	vk, _ := pk.VerifierKey()
+	proofs := generateEvalProofs(&pk)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		vk.VerifyEvalBatch(proofs)
+	}
+}
+
+func BenchmarkVerifyEvalIndividually(b *testing.B) {
+	var pk Pk
+	pk.Setup(curve.BN256, rand.Reader, deg)
+	vk, _ := pk.VerifierKey()
+	proofs := generateEvalProofs(&pk)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		for k := range proofs {
+			vk.VerifyEval(proofs[k].Commit, proofs[k].I, proofs[k].Res, proofs[k].Witness)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package polycommit
+
+// This file implements math/big convenience wrappers around the scalar based API.
+// Inputs may be negative or unreduced; they are taken modulo the order of the
+// curve of the key.
+
+import (
+	"math/big"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+)
+
+// Generate the commitment of the polynomial poly.
+func (pk *Pk) CommitBig(poly []big.Int) (curve.Point, error) {
+	return pk.Commit(curve.FromBigInts(pk.Curve, poly))
+}
+
+// Verify that the commitment g2 is consistent with the polynomial poly.
+func (pk *Pk) VerifyPolyBig(poly []big.Int, g2 curve.Point) bool {
+	return pk.VerifyPoly(curve.FromBigInts(pk.Curve, poly), g2)
+}
+
+// Create a witness g1 to the evaluation of the polynomial poly at i.
+func (pk *Pk) CreateWitnessBig(poly []big.Int, i *big.Int) (res *big.Int, g1 curve.Point, err error) {
+	r, g1, err := pk.CreateWitness(curve.FromBigInts(pk.Curve, poly), pk.Curve.NewScalar().SetBigInt(i))
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.BigInt(), g1, nil
+}
+
+// Verify the evaluation of the polynomial with the commitment g2 and the witness g1.
+func (vk *VerifierKey) VerifyEvalBig(g2 curve.Point, i *big.Int, res *big.Int, g1 curve.Point) bool {
+	c := vk.Curve
+	return vk.VerifyEval(g2, c.NewScalar().SetBigInt(i), c.NewScalar().SetBigInt(res), g1)
+}
+
+// Create a witness g1 to the evaluations of the polynomial poly at all points.
+func (pk *Pk) CreateBatchWitnessBig(poly []big.Int, points []big.Int) (rem []big.Int, g1 curve.Point, err error) {
+	r, g1, err := pk.CreateBatchWitness(curve.FromBigInts(pk.Curve, poly), curve.FromBigInts(pk.Curve, points))
+	if err != nil {
+		return nil, nil, err
+	}
+	return curve.ToBigInts(r), g1, nil
+}
+
+// Verify the evaluations of the polynomial at all points with the commitment g2,
+// the remainder rem and the witness g1.
+func (pk *Pk) VerifyBatchEvalBig(g2 curve.Point, points []big.Int, rem []big.Int, g1 curve.Point) bool {
+	return pk.VerifyBatchEval(g2, curve.FromBigInts(pk.Curve, points), curve.FromBigInts(pk.Curve, rem), g1)
+}
+
+// Generate the commitment of the polynomial poly blinded by the polynomial blind.
+func (pk *PedPk) CommitBig(poly []big.Int, blind []big.Int) (curve.Point, error) {
+	return pk.Commit(curve.FromBigInts(pk.Curve, poly), curve.FromBigInts(pk.Curve, blind))
+}
+
+// Create a witness g1 to the evaluation of the polynomial poly at i.
+func (pk *PedPk) CreateWitnessBig(poly []big.Int, blind []big.Int, i *big.Int) (res *big.Int, blindRes *big.Int, g1 curve.Point, err error) {
+	c := pk.Curve
+	r, br, g1, err := pk.CreateWitness(curve.FromBigInts(c, poly), curve.FromBigInts(c, blind), c.NewScalar().SetBigInt(i))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return r.BigInt(), br.BigInt(), g1, nil
+}
+
+// Verify the evaluation of the polynomial with the commitment g2 and the witness g1.
+func (vk *PedVerifierKey) VerifyEvalBig(g2 curve.Point, i *big.Int, res *big.Int, blindRes *big.Int, g1 curve.Point) bool {
+	c := vk.Curve
+	return vk.VerifyEval(g2, c.NewScalar().SetBigInt(i), c.NewScalar().SetBigInt(res), c.NewScalar().SetBigInt(blindRes), g1)
+}
+
+// Evaluate the polynomial poly over the curve c at i.
+func EvaluateBig(c curve.Curve, poly []big.Int, i *big.Int) *big.Int {
+	return Evaluate(curve.FromBigInts(c, poly), c.NewScalar().SetBigInt(i)).BigInt()
+}
+
+// Generate the commitment in G1 of the polynomial poly.
+func (pk *G1Pk) CommitBig(poly []big.Int) (curve.Point, error) {
+	return pk.Commit(curve.FromBigInts(pk.Curve, poly))
+}
+
+// Create a witness g1 to the evaluation of the polynomial poly at i.
+func (pk *G1Pk) CreateWitnessBig(poly []big.Int, i *big.Int) (res *big.Int, g1 curve.Point, err error) {
+	r, g1, err := pk.CreateWitness(curve.FromBigInts(pk.Curve, poly), pk.Curve.NewScalar().SetBigInt(i))
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.BigInt(), g1, nil
+}
+
+// Verify the evaluation of the polynomial with the commitment c in G1 and the witness g1.
+func (vk *VerifierKey) VerifyEvalG1Big(c curve.Point, i *big.Int, res *big.Int, g1 curve.Point) bool {
+	return vk.VerifyEvalG1(c, vk.Curve.NewScalar().SetBigInt(i), vk.Curve.NewScalar().SetBigInt(res), g1)
+}
@@ -0,0 +1,184 @@
+package polycommit
+
+// This file implements proofs that a committed polynomial has a degree less
+// than a bound d below the degree n of the public key. The proof is the
+// commitment in G1 to x^(n - d) * p(x), which only exists when p has fewer
+// than d coefficients since the public key stops at alpha^(n - 1).
+// Commitments in G1 are checked against G2^(alpha^(n - d)), so a G1Pk only
+// supports the bounds it was set up with.
+
+import (
+	"errors"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/point"
+	pb "github.com/zhtluo/libpolycrypto/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Struct DegreeKey implements the verifier key of degree proofs for the bound
+// Degree, holding G1Shift = g^(alpha^(n - Degree)), the generator G2 and
+// G2Shift = G2^(alpha^(n - Degree)) of Curve.
+// It must be derived from a trusted public key, as the proofs are only
+// sound for the shift of the public key the commitments are made with.
+type DegreeKey struct {
+	Curve   curve.Curve
+	Degree  int
+	G1Shift curve.Point
+	G2      curve.Point
+	G2Shift curve.Point
+}
+
+// Check that d is a valid degree bound of a public key of degree n.
+func checkDegreeBound(n int, d int) error {
+	if d < 1 {
+		return errors.New("Degree bound is not positive")
+	}
+	if n < d {
+		return errors.New("Public key has a degree less than the degree bound")
+	}
+	return nil
+}
+
+// Check that d is a valid degree bound of the public key.
+func (pk *Pk) checkDegreeBound(d int) error {
+	if len(pk.G2P) < pk.Degree() {
+		return errors.New("Public key has powers of different lengths")
+	}
+	return checkDegreeBound(pk.Degree(), d)
+}
+
+// Extract the verifier key of degree proofs for the bound d from the public key.
+func (pk *Pk) DegreeKey(d int) (*DegreeKey, error) {
+	if err := pk.checkDegreeBound(d); err != nil {
+		return nil, err
+	}
+	dk := new(DegreeKey)
+	dk.Curve = pk.Curve
+	dk.Degree = d
+	dk.G1Shift = pk.G1P[pk.Degree()-d]
+	dk.G2 = pk.G2P[0]
+	dk.G2Shift = pk.G2P[pk.Degree()-d]
+	return dk, nil
+}
+
+// Return the index n - d of the first power of the degree proof
+// of the polynomial poly for the bound d with a public key of degree n.
+func degreeShift(n int, poly []curve.Scalar, d int) (int, error) {
+	if err := checkDegreeBound(n, d); err != nil {
+		return 0, err
+	}
+	if len(poly) < 1 {
+		return 0, errors.New("Polynomial is empty")
+	}
+	if len(poly) > d {
+		return 0, errors.New("Polynomial has a degree not less than the degree bound")
+	}
+	return n - d, nil
+}
+
+// Create a proof that the polynomial poly has fewer than d coefficients,
+// i.e. a degree less than d.
+func (pk *Pk) CreateDegreeProof(poly []curve.Scalar, d int) (curve.Point, error) {
+	shift, err := degreeShift(pk.Degree(), poly, d)
+	if err != nil {
+		return nil, err
+	}
+	return MultiExpG1(pk.Curve, pk.G1P[shift:shift+len(poly)], poly), nil
+}
+
+// Verify that the polynomial committed in g2 has a degree less than d.
+func (pk *Pk) VerifyDegree(g2 curve.Point, d int, proof curve.Point) bool {
+	dk, err := pk.DegreeKey(d)
+	if err != nil {
+		return false
+	}
+	return dk.VerifyDegree(g2, proof)
+}
+
+// Verify that the polynomial committed in g2 has a degree less than dk.Degree.
+func (dk *DegreeKey) VerifyDegree(g2 curve.Point, proof curve.Point) bool {
+	c := dk.Curve
+	// e(proof, g) * e(-g^(alpha^(n - d)), C) = 1
+	return c.PairingCheck([]curve.Point{proof, c.NewG1().Neg(dk.G1Shift)}, []curve.Point{dk.G2, g2})
+}
+
+// Extract the verifier key of degree proofs for the bound d from the public key,
+// which must have been set up with d among its bounds.
+func (pk *G1Pk) DegreeKey(d int) (*DegreeKey, error) {
+	if err := checkDegreeBound(pk.Degree(), d); err != nil {
+		return nil, err
+	}
+	for k := range pk.Bounds {
+		if pk.Bounds[k] == d && k < len(pk.G2Shift) {
+			dk := new(DegreeKey)
+			dk.Curve = pk.Curve
+			dk.Degree = d
+			dk.G1Shift = pk.G1P[pk.Degree()-d]
+			dk.G2 = pk.G2
+			dk.G2Shift = pk.G2Shift[k]
+			return dk, nil
+		}
+	}
+	return nil, errors.New("Public key does not support the degree bound")
+}
+
+// Create a proof that the polynomial poly has fewer than d coefficients,
+// i.e. a degree less than d.
+func (pk *G1Pk) CreateDegreeProof(poly []curve.Scalar, d int) (curve.Point, error) {
+	shift, err := degreeShift(pk.Degree(), poly, d)
+	if err != nil {
+		return nil, err
+	}
+	return MultiExpG1(pk.Curve, pk.G1P[shift:shift+len(poly)], poly), nil
+}
+
+// Verify that the polynomial committed in c in G1 has a degree less than dk.Degree.
+func (dk *DegreeKey) VerifyDegreeG1(c curve.Point, proof curve.Point) bool {
+	// e(proof, g) * e(-C, g^(alpha^(n - d))) = 1
+	neg := dk.Curve.NewG1().Neg(c)
+	return dk.Curve.PairingCheck([]curve.Point{proof, neg}, []curve.Point{dk.G2, dk.G2Shift})
+}
+
+// Serialize the degree key.
+func (dk *DegreeKey) Marshal() ([]byte, error) {
+	return dk.MarshalFormat(point.Uncompressed)
+}
+
+// Serialize the degree key with the points encoded in the format f.
+func (dk *DegreeKey) MarshalFormat(f point.Format) ([]byte, error) {
+	var sDk pb.DegreeKey
+	sDk.Degree = uint32(dk.Degree)
+	sDk.G1Shift = curve.Encode(dk.G1Shift, f)
+	sDk.G2 = curve.Encode(dk.G2, f)
+	sDk.G2Shift = curve.Encode(dk.G2Shift, f)
+	sDk.Format = uint32(f)
+	sDk.Curve = dk.Curve.Name()
+	return proto.Marshal(&sDk)
+}
+
+// Deserialize the degree key.
+func (dk *DegreeKey) Unmarshal(b []byte) error {
+	var sDk pb.DegreeKey
+	err := proto.Unmarshal(b, &sDk)
+	if err != nil {
+		return err
+	}
+	c, err := curve.ByName(sDk.Curve)
+	if err != nil {
+		return err
+	}
+	f := point.Format(sDk.Format)
+	if dk.G1Shift, err = decodePoint(c.NewG1, sDk.G1Shift, f); err != nil {
+		return err
+	}
+	if dk.G2, err = decodePoint(c.NewG2, sDk.G2, f); err != nil {
+		return err
+	}
+	if dk.G2Shift, err = decodePoint(c.NewG2, sDk.G2Shift, f); err != nil {
+		return err
+	}
+	dk.Curve = c
+	dk.Degree = int(sDk.Degree)
+	return nil
+}
@@ -0,0 +1,152 @@
+package polycommit
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+)
+
+func TestDegreeProof(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			poly := generatePoly(c, rand.Reader)[:deg/2]
+			g2, err := pk.Commit(poly)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			proof, err := pk.CreateDegreeProof(poly, deg/2)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if pk.VerifyDegree(g2, deg/2, proof) != true {
+				t.Error("VerifyDegree failed, expected: true.")
+			}
+			// The proof for one bound does not pass for a tighter one.
+			if pk.VerifyDegree(g2, deg/2-1, proof) != false {
+				t.Error("VerifyDegree failed with a tighter bound, expected: false.")
+			}
+			if _, err = pk.CreateDegreeProof(poly, deg/2-1); err == nil {
+				t.Error("CreateDegreeProof accepted a polynomial beyond the bound.")
+			}
+			if _, err = pk.CreateDegreeProof(poly, deg+1); err == nil {
+				t.Error("CreateDegreeProof accepted a bound beyond the public key.")
+			}
+			loose, err := pk.CreateDegreeProof(poly, deg)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if pk.VerifyDegree(g2, deg, loose) != true {
+				t.Error("VerifyDegree failed with the full bound, expected: true.")
+			}
+			dk, err := pk.DegreeKey(deg / 2)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			b, err := dk.Marshal()
+			if err != nil {
+				t.Error(err.Error())
+			}
+			var rDk DegreeKey
+			if err = rDk.Unmarshal(b); err != nil {
+				t.Error(err.Error())
+			}
+			if rDk.Degree != deg/2 || rDk.VerifyDegree(g2, proof) != true {
+				t.Error("DegreeKey Unmarshal failed.")
+			}
+			if rDk.VerifyDegree(g2, loose) != false {
+				t.Error("VerifyDegree failed with a proof for another bound, expected: false.")
+			}
+		})
+	}
+}
+
+func TestDegreeProofG1(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			var sPk G1Pk
+			sPk.Setup(c, rand.Reader, deg, deg/2)
+			cPk, err := pk.G1Pk(deg / 2)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			b, err := cPk.Marshal()
+			if err != nil {
+				t.Error(err.Error())
+			}
+			var rPk G1Pk
+			if err = rPk.Unmarshal(b); err != nil {
+				t.Error(err.Error())
+			}
+			poly := generatePoly(c, rand.Reader)[:deg/2]
+			for _, gpk := range []*G1Pk{&sPk, cPk, &rPk} {
+				com, err := gpk.Commit(poly)
+				if err != nil {
+					t.Error(err.Error())
+				}
+				proof, err := gpk.CreateDegreeProof(poly, deg/2)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				dk, err := gpk.DegreeKey(deg / 2)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				if dk.VerifyDegreeG1(com, proof) != true {
+					t.Error("VerifyDegreeG1 failed, expected: true.")
+				}
+				loose, err := gpk.CreateDegreeProof(poly, deg)
+				if err != nil {
+					t.Error(err.Error())
+				}
+				if dk.VerifyDegreeG1(com, loose) != false {
+					t.Error("VerifyDegreeG1 failed with a proof for another bound, expected: false.")
+				}
+				if _, err = gpk.DegreeKey(deg); err == nil {
+					t.Error("DegreeKey accepted a bound the public key was not set up with.")
+				}
+			}
+			// The degree key of the Pk verifies the commitments of the converted key.
+			dk, _ := pk.DegreeKey(deg / 2)
+			com, _ := cPk.Commit(poly)
+			proof, _ := cPk.CreateDegreeProof(poly, deg/2)
+			if dk.VerifyDegreeG1(com, proof) != true {
+				t.Error("VerifyDegreeG1 failed with the degree key of the Pk, expected: true.")
+			}
+			// Trimming keeps the shift, which now belongs to a tighter bound.
+			tPk, err := cPk.Trim(deg - 1)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if len(tPk.Bounds) != 1 || tPk.Bounds[0] != deg/2-1 {
+				t.Fatal("Trim failed. Wrong degree bounds.")
+			}
+			tdk, err := tPk.DegreeKey(deg/2 - 1)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			com, _ = tPk.Commit(poly[:deg/2-1])
+			proof, _ = tPk.CreateDegreeProof(poly[:deg/2-1], deg/2-1)
+			if tdk.VerifyDegreeG1(com, proof) != true {
+				t.Error("VerifyDegreeG1 failed after Trim, expected: true.")
+			}
+			if sPk.Setup(c, rand.Reader, deg, deg+1) == nil {
+				t.Error("Setup accepted a bound beyond the public key.")
+			}
+		})
+	}
+}
+
+func BenchmarkCreateDegreeProof(b *testing.B) {
+	var pk Pk
+	pk.Setup(curve.BN256, rand.Reader, deg)
+	poly := generatePoly(pk.Curve, rand.Reader)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		pk.CreateDegreeProof(poly, deg)
+	}
+}
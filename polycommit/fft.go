@@ -0,0 +1,46 @@
+package polycommit
+
+// This file implements the radix-2 Fourier transform in the exponent,
+// where every butterfly costs one scalar multiplication.
+
+import (
+	"math/big"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+)
+
+// Reverse the order of the elements by the bits of their indices.
+func bitReverse(n int, swap func(i, j int)) {
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			swap(i, j)
+		}
+	}
+}
+
+// Compute p[i] = sum_j p[j] * w^(ij) in place in the group built by newPoint,
+// where w has order len(p). The elements of p are replaced by new points,
+// so the points they held are left untouched.
+func fft(newPoint func() curve.Point, p []curve.Point, w curve.Scalar) {
+	n := len(p)
+	bitReverse(n, func(i, j int) { p[i], p[j] = p[j], p[i] })
+	c := w.Curve()
+	for m := 2; m <= n; m <<= 1 {
+		wm := c.NewScalar().Exp(w, big.NewInt(int64(n/m)))
+		for k := 0; k < n; k += m {
+			wj := c.NewScalar().SetOne()
+			for j := 0; j < m/2; j++ {
+				t := newPoint().ScalarMult(p[k+j+m/2], wj.BigInt())
+				u := p[k+j]
+				p[k+j] = newPoint().Add(u, t)
+				p[k+j+m/2] = newPoint().Add(u, t.Neg(t))
+				wj.Mul(wj, wm)
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package polycommit
+
+// This file implements the computation of the witnesses at every point of a
+// domain in O(n log n) group operations,
+// D. Feist, D. Khovratovich.
+// Fast Amortized Kate Proofs.
+
+import (
+	"errors"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/ntt"
+)
+
+// Create the witnesses to the evaluations of the polynomial poly at every
+// element of the domain d, in domain order, i.e. g1[k] and res[k] are the
+// witness and the evaluation at d.Elements[k] as in CreateWitness.
+//
+// The witness at z is sum_i z^i * h_i with h_i = sum_(j > i) p_j * g^(alpha^(j - i - 1)),
+// so all of them are the Fourier transform of h over d, and h is a Toeplitz
+// matrix-vector product computed as a convolution of twice the size.
+func (pk *Pk) CreateAllWitnesses(poly []curve.Scalar, d *ntt.Domain) (res []curve.Scalar, g1 []curve.Point, err error) {
+	err = pk.checkPoly(poly)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(poly) > d.Size {
+		return nil, nil, errors.New("Domain has a size less than the polynomial")
+	}
+	res, err = d.NTT(poly)
+	if err != nil {
+		return nil, nil, err
+	}
+	c := pk.Curve
+	h := make([]curve.Point, d.Size)
+	for i := range h {
+		h[i] = c.NewG1()
+	}
+	deg := len(poly) - 1
+	if deg > 0 {
+		cd, err := ntt.NewDomainAtLeast(c, 2*deg)
+		if err != nil {
+			return nil, nil, err
+		}
+		// h_i = (p * r)_(deg + i) with r_k = g^(alpha^(deg - 1 - k)).
+		r := make([]curve.Point, cd.Size)
+		for k := range r {
+			if k < deg {
+				r[k] = pk.G1P[deg-1-k]
+			} else {
+				r[k] = c.NewG1()
+			}
+		}
+		fft(c.NewG1, r, cd.Generator)
+		pc, err := cd.NTT(poly)
+		if err != nil {
+			return nil, nil, err
+		}
+		for k := range r {
+			r[k] = c.NewG1().ScalarMult(r[k], pc[k].BigInt())
+		}
+		fft(c.NewG1, r, cd.GeneratorInv)
+		e := cd.SizeInv.BigInt()
+		for i := 0; i < deg; i++ {
+			h[i].ScalarMult(r[deg+i], e)
+		}
+	}
+	fft(c.NewG1, h, d.Generator)
+	return res, h, nil
+}
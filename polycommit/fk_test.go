@@ -0,0 +1,78 @@
+package polycommit
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/ntt"
+)
+
+func TestCreateAllWitnesses(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, domainSize)
+			vk, _ := pk.VerifierKey()
+			d, _ := ntt.NewDomain(c, domainSize)
+			for _, n := range []int{1, 2, 7, domainSize} {
+				poly := generatePoly(c, rand.Reader)[:n]
+				g2, err := pk.Commit(poly)
+				if err != nil {
+					t.Error(err)
+				}
+				res, g1, err := pk.CreateAllWitnesses(poly, d)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(res) != d.Size || len(g1) != d.Size {
+					t.Fatal("CreateAllWitnesses failed. Wrong number of witnesses.")
+				}
+				for k := range g1 {
+					sres, sg1, _ := pk.CreateWitness(poly, d.Elements[k])
+					if !res[k].Equal(sres) {
+						t.Error("CreateAllWitnesses failed. Wrong evaluation result.")
+					}
+					if !g1[k].Equal(sg1) {
+						t.Errorf("CreateAllWitnesses failed. Wrong witness at %d for %d coefficients.", k, n)
+					}
+				}
+				if vk.VerifyEval(g2, d.Elements[3], res[3], g1[3]) != true {
+					t.Error("VerifyEval failed, expected: true.")
+				}
+				res[3].Add(res[3], curve.NewScalar(c, 1))
+				if vk.VerifyEval(g2, d.Elements[3], res[3], g1[3]) != false {
+					t.Error("VerifyEval failed, expected: false.")
+				}
+			}
+			small, _ := ntt.NewDomain(c, domainSize/2)
+			if _, _, err := pk.CreateAllWitnesses(generatePoly(c, rand.Reader)[:domainSize], small); err == nil {
+				t.Error("CreateAllWitnesses accepted a domain smaller than the polynomial.")
+			}
+		})
+	}
+}
+
+func BenchmarkCreateAllWitnesses(b *testing.B) {
+	var pk Pk
+	pk.Setup(curve.BN256, rand.Reader, deg)
+	d, _ := ntt.NewDomain(pk.Curve, deg)
+	poly := generatePoly(pk.Curve, rand.Reader)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		pk.CreateAllWitnesses(poly, d)
+	}
+}
+
+func BenchmarkCreateWitnessAll(b *testing.B) {
+	var pk Pk
+	pk.Setup(curve.BN256, rand.Reader, deg)
+	d, _ := ntt.NewDomain(pk.Curve, deg)
+	poly := generatePoly(pk.Curve, rand.Reader)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		for k := range d.Elements {
+			pk.CreateWitness(poly, d.Elements[k])
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package polycommit
+
+// This file implements the standard mode of polycommit_dl, where both the
+// commitments and the witnesses live in G1 and only two powers in G2 are
+// kept for the verifier. Commitments take the size of a point of G1 instead
+// of G2, and no G2 multiplications are needed outside of Setup.
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/point"
+	"github.com/zhtluo/libpolycrypto/poly"
+	pb "github.com/zhtluo/libpolycrypto/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Struct G1Pk implements a public key committing in G1 of Curve.
+// G2 and G2Alpha = G2^alpha are the only powers kept in G2, besides
+// G2Shift[k] = G2^(alpha^(n - Bounds[k])) for the degree bounds Bounds
+// the key supports degree proofs for.
+type G1Pk struct {
+	Curve   curve.Curve
+	G1P     []curve.Point
+	G2      curve.Point
+	G2Alpha curve.Point
+	Bounds  []int
+	G2Shift []curve.Point
+}
+
+func (pk *G1Pk) checkPoly(poly []curve.Scalar) error {
+	if len(poly) < 1 {
+		return errors.New("Polynomial is empty")
+	}
+	if pk.Degree() < len(poly) {
+		return errors.New("Public key has a degree less than the polynomial")
+	}
+	return nil
+}
+
+// Check that every bound is a valid degree bound of a public key of degree t.
+func checkBounds(t int, bounds []int) error {
+	for _, d := range bounds {
+		if err := checkDegreeBound(t, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set the powers in G2 of the degree bounds of a public key of degree t
+// with the trapdoor alpha.
+func (pk *G1Pk) setBounds(alpha curve.Scalar, t int, bounds []int) {
+	pk.Bounds = make([]int, len(bounds))
+	pk.G2Shift = make([]curve.Point, len(bounds))
+	for k, d := range bounds {
+		pk.Bounds[k] = d
+		e := pk.Curve.NewScalar().Exp(alpha, big.NewInt(int64(t-d)))
+		pk.G2Shift[k] = pk.Curve.NewG2().ScalarBaseMult(e.BigInt())
+	}
+}
+
+// Create a new public key for commitment on the curve c,
+// with the randomness generated in reader r and degree t,
+// supporting degree proofs for the degree bounds bounds.
+func (pk *G1Pk) Setup(c curve.Curve, r io.Reader, t int, bounds ...int) error {
+	if err := checkBounds(t, bounds); err != nil {
+		return err
+	}
+	alpha, err := randomScalar(c, r)
+	if err != nil {
+		return err
+	}
+	pk.Curve = c
+	pk.G1P = make([]curve.Point, t)
+	am := c.NewScalar().SetOne()
+	for i := 0; i < t; i++ {
+		pk.G1P[i] = c.NewG1().ScalarBaseMult(am.BigInt())
+		am.Mul(am, alpha)
+	}
+	pk.G2 = c.NewG2().ScalarBaseMult(big.NewInt(1))
+	pk.G2Alpha = c.NewG2().ScalarBaseMult(alpha.BigInt())
+	pk.setBounds(alpha, t, bounds)
+	return nil
+}
+
+// Convert the public key into one committing in G1 with the same alpha,
+// supporting degree proofs for the degree bounds bounds.
+func (pk *Pk) G1Pk(bounds ...int) (*G1Pk, error) {
+	if pk.Degree() < 2 || len(pk.G2P) < pk.Degree() {
+		return nil, errors.New("Public key has a degree less than 2")
+	}
+	if err := checkBounds(pk.Degree(), bounds); err != nil {
+		return nil, err
+	}
+	gpk := new(G1Pk)
+	gpk.Curve = pk.Curve
+	gpk.G1P = append([]curve.Point{}, pk.G1P...)
+	gpk.G2 = pk.G2P[0]
+	gpk.G2Alpha = pk.G2P[1]
+	gpk.Bounds = make([]int, len(bounds))
+	gpk.G2Shift = make([]curve.Point, len(bounds))
+	for k, d := range bounds {
+		gpk.Bounds[k] = d
+		gpk.G2Shift[k] = pk.G2P[pk.Degree()-d]
+	}
+	return gpk, nil
+}
+
+// Return the degree of the current public key.
+func (pk *G1Pk) Degree() int {
+	return len(pk.G1P)
+}
+
+// Return a copy of the first degree powers of the public key.
+// The shift of a degree bound d stays that of the bound d - (n - degree),
+// and the bounds it leaves no longer positive are dropped.
+func (pk *G1Pk) Trim(degree int) (*G1Pk, error) {
+	if degree < 1 {
+		return nil, errors.New("Degree is not positive")
+	}
+	if pk.Degree() < degree {
+		return nil, errors.New("Public key has a degree less than the requested one")
+	}
+	tpk := new(G1Pk)
+	tpk.Curve = pk.Curve
+	tpk.G1P = append([]curve.Point{}, pk.G1P[:degree]...)
+	tpk.G2 = pk.G2
+	tpk.G2Alpha = pk.G2Alpha
+	for k := range pk.Bounds {
+		if d := pk.Bounds[k] - (pk.Degree() - degree); d >= 1 && k < len(pk.G2Shift) {
+			tpk.Bounds = append(tpk.Bounds, d)
+			tpk.G2Shift = append(tpk.G2Shift, pk.G2Shift[k])
+		}
+	}
+	return tpk, nil
+}
+
+// Extract the verifier key from the public key.
+// It is the same as the one of the Pk the key was converted from.
+func (pk *G1Pk) VerifierKey() (*VerifierKey, error) {
+	if pk.Degree() < 1 {
+		return nil, errors.New("Public key is empty")
+	}
+	vk := new(VerifierKey)
+	vk.Curve = pk.Curve
+	vk.G1 = pk.G1P[0]
+	vk.G2 = pk.G2
+	vk.G2Alpha = pk.G2Alpha
+	return vk, nil
+}
+
+// Generate the commitment of the polynomial poly.
+func (pk *G1Pk) Commit(poly []curve.Scalar) (curve.Point, error) {
+	err := pk.checkPoly(poly)
+	if err != nil {
+		return nil, err
+	}
+	return MultiExpG1(pk.Curve, pk.G1P, poly), nil
+}
+
+// Verify that the commitment g1 is consistent with the polynomial poly.
+func (pk *G1Pk) VerifyPoly(poly []curve.Scalar, g1 curve.Point) bool {
+	g1c, err := pk.Commit(poly)
+	if err != nil {
+		return false
+	}
+	return g1.Equal(g1c)
+}
+
+// Create a witness g1 to the evaluation of the polynomial p at i.
+func (pk *G1Pk) CreateWitness(p []curve.Scalar, i curve.Scalar) (res curve.Scalar, g1 curve.Point, err error) {
+	err = pk.checkPoly(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	quotient, res := poly.DivLinear(p, i)
+	return res, MultiExpG1(pk.Curve, pk.G1P, quotient), nil
+}
+
+// Verify the evaluation of the polynomial with the commitment c in G1 and the witness g1.
+func (vk *VerifierKey) VerifyEvalG1(c curve.Point, i curve.Scalar, res curve.Scalar, g1 curve.Point) bool {
+	k := vk.Curve
+	// e(C - g^res + w^i, g) * e(-w, g^alpha) = 1
+	lhs := MultiExpG1(k, []curve.Point{c, vk.G1, g1}, []curve.Scalar{k.NewScalar().SetOne(), k.NewScalar().Neg(res), i})
+	return k.PairingCheck([]curve.Point{lhs, k.NewG1().Neg(g1)}, []curve.Point{vk.G2, vk.G2Alpha})
+}
+
+// Serialize the specified public key
+func (pk *G1Pk) Marshal() ([]byte, error) {
+	return pk.MarshalFormat(point.Uncompressed)
+}
+
+// Serialize the specified public key with the points encoded in the format f.
+func (pk *G1Pk) MarshalFormat(f point.Format) ([]byte, error) {
+	var sPk pb.G1Pk
+	sPk.G1P = encodePoints(pk.G1P, f)
+	sPk.G2 = curve.Encode(pk.G2, f)
+	sPk.G2Alpha = curve.Encode(pk.G2Alpha, f)
+	if len(pk.Bounds) != len(pk.G2Shift) {
+		return nil, errors.New("Public key has bounds and shifts of different lengths")
+	}
+	sPk.Bounds = make([]uint32, len(pk.Bounds))
+	for k := range pk.Bounds {
+		sPk.Bounds[k] = uint32(pk.Bounds[k])
+	}
+	sPk.G2Shift = encodePoints(pk.G2Shift, f)
+	sPk.Format = uint32(f)
+	sPk.Curve = pk.Curve.Name()
+	return proto.Marshal(&sPk)
+}
+
+// Deserialize the specified public key
+func (pk *G1Pk) Unmarshal(b []byte) error {
+	var sPk pb.G1Pk
+	err := proto.Unmarshal(b, &sPk)
+	if err != nil {
+		return err
+	}
+	c, err := curve.ByName(sPk.Curve)
+	if err != nil {
+		return err
+	}
+	f := point.Format(sPk.Format)
+	if pk.G1P, err = decodePoints(c.NewG1, sPk.G1P, f); err != nil {
+		return err
+	}
+	if pk.G2, err = decodePoint(c.NewG2, sPk.G2, f); err != nil {
+		return err
+	}
+	if pk.G2Alpha, err = decodePoint(c.NewG2, sPk.G2Alpha, f); err != nil {
+		return err
+	}
+	if len(sPk.Bounds) != len(sPk.G2Shift) {
+		return errors.New("Public key has bounds and shifts of different lengths")
+	}
+	pk.Bounds = make([]int, len(sPk.Bounds))
+	for k := range sPk.Bounds {
+		pk.Bounds[k] = int(sPk.Bounds[k])
+	}
+	if pk.G2Shift, err = decodePoints(c.NewG2, sPk.G2Shift, f); err != nil {
+		return err
+	}
+	pk.Curve = c
+	return checkBounds(len(pk.G1P), pk.Bounds)
+}
@@ -0,0 +1,170 @@
+package polycommit
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+)
+
+func TestG1Commit(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk G1Pk
+			pk.Setup(c, rand.Reader, deg)
+			poly := generatePoly(c, rand.Reader)
+			g1, err := pk.Commit(poly)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if pk.VerifyPoly(poly, g1) != true {
+				t.Error("VerifyPoly failed, expected: true.")
+			}
+			if pk.VerifyPoly(generatePoly(c, rand.Reader), g1) != false {
+				t.Error("VerifyPoly failed, expected: false.")
+			}
+		})
+	}
+}
+
+func TestG1Witness(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk G1Pk
+			pk.Setup(c, rand.Reader, deg)
+			vk, _ := pk.VerifierKey()
+			// x^3 - 2x^2 + 7x - 5
+			poly := []curve.Scalar{curve.NewScalar(c, -5), curve.NewScalar(c, 7), curve.NewScalar(c, -2), curve.NewScalar(c, 1)}
+			com, err := pk.Commit(poly)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			i := curve.NewScalar(c, 3)
+			res, g1, err := pk.CreateWitness(poly, i)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if !res.Equal(curve.NewScalar(c, 25)) {
+				t.Error("CreateWitness failed. Wrong evaluation result.")
+			}
+			if vk.VerifyEvalG1(com, i, res, g1) != true {
+				t.Error("VerifyEvalG1 failed, expected: true.")
+			}
+			if vk.VerifyEvalG1(com, i, curve.NewScalar(c, 24), g1) != false {
+				t.Error("VerifyEvalG1 failed, expected: false.")
+			}
+			// random polynomial
+			poly = generatePoly(c, rand.Reader)
+			com, err = pk.Commit(poly)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			i.SetRandom(rand.Reader)
+			res, g1, err = pk.CreateWitness(poly, i)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if vk.VerifyEvalG1(com, i, res, g1) != true {
+				t.Error("VerifyEvalG1 failed, expected: true.")
+			}
+			if vk.VerifyEvalG1(com, randomElement(c, rand.Reader), res, g1) != false {
+				t.Error("VerifyEvalG1 failed, expected: false.")
+			}
+		})
+	}
+}
+
+func TestG1Pk(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			gpk, err := pk.G1Pk()
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Both modes share the verifier key.
+			vk, _ := pk.VerifierKey()
+			gvk, _ := gpk.VerifierKey()
+			if !vk.G1.Equal(gvk.G1) || !vk.G2.Equal(gvk.G2) ||
+				!vk.G2Alpha.Equal(gvk.G2Alpha) {
+				t.Error("G1Pk failed. Verifier keys differ.")
+			}
+			poly := generatePoly(c, rand.Reader)
+			com, _ := gpk.Commit(poly)
+			i := randomElement(c, rand.Reader)
+			res, g1, _ := gpk.CreateWitness(poly, i)
+			_, mg1, _ := pk.CreateWitness(poly, i)
+			if !g1.Equal(mg1) {
+				t.Error("CreateWitness failed. Witnesses differ between modes.")
+			}
+			if vk.VerifyEvalG1(com, i, res, g1) != true {
+				t.Error("VerifyEvalG1 failed, expected: true.")
+			}
+			tpk, err := gpk.Trim(2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tpk.Degree() != 2 || tpk.VerifyPoly(poly[:2], com) != false {
+				t.Error("Trim failed.")
+			}
+		})
+	}
+}
+
+func TestG1Marshal(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk, rPk G1Pk
+			pk.Setup(c, rand.Reader, deg)
+			b, err := pk.Marshal()
+			if err != nil {
+				t.Error(err)
+			}
+			err = rPk.Unmarshal(b)
+			if err != nil {
+				t.Error(err)
+			}
+			if rPk.Degree() != pk.Degree() || !pk.G2.Equal(rPk.G2) ||
+				!pk.G2Alpha.Equal(rPk.G2Alpha) {
+				t.Fatal("Marshal does not generate equal result.")
+			}
+			for i := range pk.G1P {
+				if !pk.G1P[i].Equal(rPk.G1P[i]) {
+					t.Error("Marshal does not generate equal result.")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkG1Setup(b *testing.B) {
+	for t := 0; t < b.N; t++ {
+		var pk G1Pk
+		pk.Setup(curve.BN256, rand.Reader, deg)
+	}
+}
+
+func BenchmarkG1Commit(b *testing.B) {
+	var pk G1Pk
+	pk.Setup(curve.BN256, rand.Reader, deg)
+	poly := generatePoly(pk.Curve, rand.Reader)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		pk.Commit(poly)
+	}
+}
+
+func BenchmarkG1VerifyEval(b *testing.B) {
+	var pk G1Pk
+	pk.Setup(curve.BN256, rand.Reader, deg)
+	vk, _ := pk.VerifierKey()
+	poly := generatePoly(pk.Curve, rand.Reader)
+	com, _ := pk.Commit(poly)
+	i := randomElement(pk.Curve, rand.Reader)
+	res, g1, _ := pk.CreateWitness(poly, i)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		vk.VerifyEvalG1(com, i, res, g1)
+	}
+}
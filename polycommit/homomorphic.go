@@ -0,0 +1,107 @@
+package polycommit
+
+// This file implements arithmetic on commitments and witnesses. Both are
+// linear in the polynomial, so the commitment and the witness of a linear
+// combination of polynomials are the same combination of theirs, and need
+// not be computed from the coefficients again.
+
+import (
+	"errors"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+)
+
+// Return the commitment to p + q from the commitments a to p and b to q on the curve c.
+func AddCommit(c curve.Curve, a curve.Point, b curve.Point) curve.Point {
+	return c.NewG2().Add(a, b)
+}
+
+// Return the commitment to p - q from the commitments a to p and b to q on the curve c.
+func SubCommit(c curve.Curve, a curve.Point, b curve.Point) curve.Point {
+	neg := c.NewG2().Neg(b)
+	return neg.Add(a, neg)
+}
+
+// Return the commitment to k * p from the commitment a to p on the curve c.
+func ScaleCommit(c curve.Curve, a curve.Point, k curve.Scalar) curve.Point {
+	return c.NewG2().ScalarMult(a, k.BigInt())
+}
+
+// Return the witness to (p + q)(i) from the witnesses a to p(i) and b to q(i) on the curve c.
+// Commitments in G1 are added the same way.
+func AddWitness(c curve.Curve, a curve.Point, b curve.Point) curve.Point {
+	return c.NewG1().Add(a, b)
+}
+
+// Return the witness to (p - q)(i) from the witnesses a to p(i) and b to q(i) on the curve c.
+// Commitments in G1 are subtracted the same way.
+func SubWitness(c curve.Curve, a curve.Point, b curve.Point) curve.Point {
+	neg := c.NewG1().Neg(b)
+	return neg.Add(a, neg)
+}
+
+// Return the witness to (k * p)(i) from the witness a to p(i) on the curve c.
+// Commitments in G1 are scaled the same way.
+func ScaleWitness(c curve.Curve, a curve.Point, k curve.Scalar) curve.Point {
+	return c.NewG1().ScalarMult(a, k.BigInt())
+}
+
+// Check that there is a coefficient for every proof and all the proofs are at the same point.
+func checkCombination(coeffs []curve.Scalar, n int, point func(k int) curve.Scalar) error {
+	if n == 0 || len(coeffs) != n {
+		return errors.New("Number of coefficients does not match the proofs")
+	}
+	for k := 1; k < n; k++ {
+		if !point(k).Equal(point(0)) {
+			return errors.New("Evaluation proofs are at different points")
+		}
+	}
+	return nil
+}
+
+// Combine the evaluation proofs of polynomials p_k at the same point into
+// the evaluation proof of sum_k coeffs[k] * p_k at that point on the curve c.
+func CombineEvalProofs(c curve.Curve, coeffs []curve.Scalar, proofs []EvalProof) (*EvalProof, error) {
+	err := checkCombination(coeffs, len(proofs), func(k int) curve.Scalar { return proofs[k].I })
+	if err != nil {
+		return nil, err
+	}
+	commits := make([]curve.Point, len(proofs))
+	witnesses := make([]curve.Point, len(proofs))
+	ret := new(EvalProof)
+	ret.Res = c.NewScalar()
+	term := c.NewScalar()
+	for k := range proofs {
+		commits[k] = proofs[k].Commit
+		witnesses[k] = proofs[k].Witness
+		ret.Res.Add(ret.Res, term.Mul(coeffs[k], proofs[k].Res))
+	}
+	ret.Commit = MultiExpG2(c, commits, coeffs)
+	ret.I = c.NewScalar().Set(proofs[0].I)
+	ret.Witness = MultiExpG1(c, witnesses, coeffs)
+	return ret, nil
+}
+
+// Combine the evaluation proofs of polynomials p_k committed in G1 at the
+// same point into the evaluation proof of sum_k coeffs[k] * p_k at that point
+// on the curve c.
+func CombineEvalProofsG1(c curve.Curve, coeffs []curve.Scalar, proofs []G1EvalProof) (*G1EvalProof, error) {
+	err := checkCombination(coeffs, len(proofs), func(k int) curve.Scalar { return proofs[k].I })
+	if err != nil {
+		return nil, err
+	}
+	commits := make([]curve.Point, len(proofs))
+	witnesses := make([]curve.Point, len(proofs))
+	ret := new(G1EvalProof)
+	ret.Res = c.NewScalar()
+	term := c.NewScalar()
+	for k := range proofs {
+		commits[k] = proofs[k].Commit
+		witnesses[k] = proofs[k].Witness
+		ret.Res.Add(ret.Res, term.Mul(coeffs[k], proofs[k].Res))
+	}
+	ret.Commit = MultiExpG1(c, commits, coeffs)
+	ret.I = c.NewScalar().Set(proofs[0].I)
+	ret.Witness = MultiExpG1(c, witnesses, coeffs)
+	return ret, nil
+}
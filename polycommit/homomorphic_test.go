@@ -0,0 +1,119 @@
+package polycommit
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/poly"
+)
+
+func TestHomomorphic(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			vk, _ := pk.VerifierKey()
+			p, q := generatePoly(c, rand.Reader), generatePoly(c, rand.Reader)[:deg/2]
+			cp, _ := pk.Commit(p)
+			cq, _ := pk.Commit(q)
+			i := randomElement(c, rand.Reader)
+			rp, wp, _ := pk.CreateWitness(p, i)
+			rq, wq, _ := pk.CreateWitness(q, i)
+			k := randomElement(c, rand.Reader)
+			res := c.NewScalar()
+			// p + q
+			sum, _ := pk.Commit(poly.Add(p, q))
+			if !AddCommit(c, cp, cq).Equal(sum) {
+				t.Error("AddCommit failed. Wrong commitment.")
+			}
+			if vk.VerifyEval(AddCommit(c, cp, cq), i, res.Add(rp, rq), AddWitness(c, wp, wq)) != true {
+				t.Error("VerifyEval failed on the sum, expected: true.")
+			}
+			// p - q
+			if vk.VerifyEval(SubCommit(c, cp, cq), i, res.Sub(rp, rq), SubWitness(c, wp, wq)) != true {
+				t.Error("VerifyEval failed on the difference, expected: true.")
+			}
+			// p - p is the zero polynomial.
+			if vk.VerifyEval(SubCommit(c, cp, cp), i, c.NewScalar(), SubWitness(c, wp, wp)) != true {
+				t.Error("VerifyEval failed on the zero polynomial, expected: true.")
+			}
+			// c * p
+			if vk.VerifyEval(ScaleCommit(c, cp, k), i, res.Mul(rp, k), ScaleWitness(c, wp, k)) != true {
+				t.Error("VerifyEval failed on the multiple, expected: true.")
+			}
+			if vk.VerifyEval(ScaleCommit(c, cp, k), i, rp, ScaleWitness(c, wp, k)) != false {
+				t.Error("VerifyEval failed on the multiple, expected: false.")
+			}
+		})
+	}
+}
+
+func TestCombineEvalProofs(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			vk, _ := pk.VerifierKey()
+			i := randomElement(c, rand.Reader)
+			polys := make([][]curve.Scalar, aggregateSize)
+			proofs := make([]EvalProof, aggregateSize)
+			coeffs := generatePoly(c, rand.Reader)[:aggregateSize]
+			var combined poly.Polynomial
+			for k := range proofs {
+				polys[k] = generatePoly(c, rand.Reader)
+				proofs[k].Commit, _ = pk.Commit(polys[k])
+				proofs[k].I = c.NewScalar().Set(i)
+				proofs[k].Res, proofs[k].Witness, _ = pk.CreateWitness(polys[k], i)
+				combined = poly.Add(combined, poly.Scale(polys[k], coeffs[k]))
+			}
+			pr, err := CombineEvalProofs(c, coeffs, proofs)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if !pr.Res.Equal(Evaluate(combined, i)) || pk.VerifyPoly(combined, pr.Commit) != true {
+				t.Error("CombineEvalProofs failed. Wrong combination.")
+			}
+			if vk.VerifyEval(pr.Commit, pr.I, pr.Res, pr.Witness) != true {
+				t.Error("VerifyEval failed on the combination, expected: true.")
+			}
+			proofs[1].I.SetRandom(rand.Reader)
+			if _, err = CombineEvalProofs(c, coeffs, proofs); err == nil {
+				t.Error("CombineEvalProofs accepted proofs at different points.")
+			}
+			if _, err = CombineEvalProofs(c, coeffs[1:], proofs); err == nil {
+				t.Error("CombineEvalProofs accepted mismatched coefficients.")
+			}
+		})
+	}
+}
+
+func TestCombineEvalProofsG1(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk G1Pk
+			pk.Setup(c, rand.Reader, deg)
+			vk, _ := pk.VerifierKey()
+			i := randomElement(c, rand.Reader)
+			proofs := make([]G1EvalProof, aggregateSize)
+			coeffs := generatePoly(c, rand.Reader)[:aggregateSize]
+			for k := range proofs {
+				p := generatePoly(c, rand.Reader)
+				proofs[k].Commit, _ = pk.Commit(p)
+				proofs[k].I = c.NewScalar().Set(i)
+				proofs[k].Res, proofs[k].Witness, _ = pk.CreateWitness(p, i)
+			}
+			pr, err := CombineEvalProofsG1(c, coeffs, proofs)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if vk.VerifyEvalG1(pr.Commit, pr.I, pr.Res, pr.Witness) != true {
+				t.Error("VerifyEvalG1 failed on the combination, expected: true.")
+			}
+			if vk.VerifyEvalG1(AddWitness(c, proofs[0].Commit, proofs[1].Commit), i,
+				c.NewScalar().Add(proofs[0].Res, proofs[1].Res), AddWitness(c, proofs[0].Witness, proofs[1].Witness)) != true {
+				t.Error("VerifyEvalG1 failed on the sum, expected: true.")
+			}
+		})
+	}
+}
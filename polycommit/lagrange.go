@@ -0,0 +1,109 @@
+package polycommit
+
+// This file implements commitments to polynomials given by their evaluations
+// over an ntt.Domain, i.e. in the Lagrange basis of the n-th roots of unity.
+
+import (
+	"errors"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/ntt"
+)
+
+// Struct LagrangePk implements a public key in the Lagrange basis of a domain.
+// L1P[j] and L2P[j] hold the Lagrange polynomial of the j-th domain element
+// evaluated at alpha, in the exponent of G1 and G2 respectively.
+// Vk is kept for verification.
+type LagrangePk struct {
+	Curve  curve.Curve
+	Domain *ntt.Domain
+	L1P    []curve.Point
+	L2P    []curve.Point
+	Vk     VerifierKey
+}
+
+// Derive the public key in the Lagrange basis of the domain d from pk.
+// The inverse Fourier transform is applied in the exponent, so the result
+// commits to exactly the same values as pk.
+func (pk *Pk) Lagrange(d *ntt.Domain) (*LagrangePk, error) {
+	if pk.Degree() < d.Size || pk.Degree() < 2 {
+		return nil, errors.New("Public key has a degree less than the domain")
+	}
+	c := pk.Curve
+	lpk := new(LagrangePk)
+	lpk.Curve = c
+	lpk.Domain = d
+	lpk.L1P = append([]curve.Point{}, pk.G1P[:d.Size]...)
+	lpk.L2P = append([]curve.Point{}, pk.G2P[:d.Size]...)
+	// L_j(alpha) = 1/n * sum_i w^(-ij) * alpha^i
+	fft(c.NewG1, lpk.L1P, d.GeneratorInv)
+	fft(c.NewG2, lpk.L2P, d.GeneratorInv)
+	sizeInv := d.SizeInv.BigInt()
+	for j := 0; j < d.Size; j++ {
+		lpk.L1P[j] = c.NewG1().ScalarMult(lpk.L1P[j], sizeInv)
+		lpk.L2P[j] = c.NewG2().ScalarMult(lpk.L2P[j], sizeInv)
+	}
+	vk, err := pk.VerifierKey()
+	if err != nil {
+		return nil, err
+	}
+	lpk.Vk = *vk
+	return lpk, nil
+}
+
+// Generate the commitment of the polynomial given by its evaluations evals
+// over the domain. The commitment is equal to Pk.Commit on its coefficients.
+func (lpk *LagrangePk) Commit(evals []curve.Scalar) (curve.Point, error) {
+	if len(evals) != lpk.Domain.Size {
+		return nil, errors.New("Number of evaluations does not match the domain")
+	}
+	return MultiExpG2(lpk.Curve, lpk.L2P, evals), nil
+}
+
+// Verify that the commitment g2 is consistent with the evaluations evals.
+func (lpk *LagrangePk) VerifyPoly(evals []curve.Scalar, g2 curve.Point) bool {
+	g2c, err := lpk.Commit(evals)
+	if err != nil {
+		return false
+	}
+	return g2.Equal(g2c)
+}
+
+// Create a witness g1 to the evaluation at i of the polynomial given by
+// its evaluations evals over the domain.
+// The quotient (f(x) - f(i)) / (x - i) is computed in evaluation form in O(n).
+func (lpk *LagrangePk) CreateWitness(evals []curve.Scalar, i curve.Scalar) (res curve.Scalar, g1 curve.Point, err error) {
+	d := lpk.Domain
+	if len(evals) != d.Size {
+		return nil, nil, errors.New("Number of evaluations does not match the domain")
+	}
+	res, err = d.Evaluate(evals, i)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, inDomain := d.Index(i)
+	// q_j = (f_j - f(i)) / (w^j - i) for every w^j != i.
+	c := lpk.Curve
+	quotient := curve.NewScalars(c, d.Size)
+	denom := curve.NewScalars(c, d.Size)
+	for j := range denom {
+		denom[j].Sub(d.Elements[j], i)
+	}
+	curve.BatchInvert(denom)
+	for j := range quotient {
+		quotient[j].Sub(evals[j], res)
+		quotient[j].Mul(quotient[j], denom[j])
+	}
+	if inDomain {
+		// q_m = -sum_(j != m) q_j * w^(j - m), the limit of the quotient at w^m.
+		quotient[m].SetZero()
+		term := c.NewScalar()
+		for j := range quotient {
+			if j != m {
+				term.Mul(quotient[j], d.Elements[(j-m+d.Size)%d.Size])
+				quotient[m].Sub(quotient[m], term)
+			}
+		}
+	}
+	return res, MultiExpG1(c, lpk.L1P, quotient), nil
+}
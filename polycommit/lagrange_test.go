@@ -0,0 +1,98 @@
+package polycommit
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/ntt"
+)
+
+const (
+	domainSize = 32
+)
+
+// Generate a random polynomial with fewer coefficients than the domain
+// and return its coefficients and evaluations over the domain.
+func generateDomainPoly(d *ntt.Domain) ([]curve.Scalar, []curve.Scalar) {
+	poly := generatePoly(d.Curve, rand.Reader)[:d.Size]
+	evals := make([]curve.Scalar, d.Size)
+	for j := range evals {
+		evals[j] = Evaluate(poly, d.Elements[j])
+	}
+	return poly, evals
+}
+
+func TestLagrangeCommit(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, domainSize)
+			d, _ := ntt.NewDomain(c, domainSize)
+			lpk, err := pk.Lagrange(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			poly, evals := generateDomainPoly(d)
+			g2, err := pk.Commit(poly)
+			if err != nil {
+				t.Error(err)
+			}
+			lg2, err := lpk.Commit(evals)
+			if err != nil {
+				t.Error(err)
+			}
+			if !g2.Equal(lg2) {
+				t.Error("Commit failed. Lagrange commitment differs from the monomial one.")
+			}
+			if lpk.VerifyPoly(evals, g2) != true {
+				t.Error("VerifyPoly failed, expected: true.")
+			}
+			evals[0].Add(evals[0], curve.NewScalar(c, 1))
+			if lpk.VerifyPoly(evals, g2) != false {
+				t.Error("VerifyPoly failed, expected: false.")
+			}
+		})
+	}
+}
+
+func TestLagrangeWitness(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, domainSize)
+			d, _ := ntt.NewDomain(c, domainSize)
+			lpk, err := pk.Lagrange(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			poly, evals := generateDomainPoly(d)
+			g2, err := lpk.Commit(evals)
+			if err != nil {
+				t.Error(err)
+			}
+			z := randomElement(c, rand.Reader)
+			one := curve.NewScalar(c, 1)
+			for _, i := range []curve.Scalar{d.Elements[0], d.Elements[5], z} {
+				res, g1, err := lpk.CreateWitness(evals, i)
+				if err != nil {
+					t.Error(err)
+				}
+				if !res.Equal(Evaluate(poly, i)) {
+					t.Error("CreateWitness failed. Wrong evaluation result.")
+				}
+				_, mg1, _ := pk.CreateWitness(poly, i)
+				if !g1.Equal(mg1) {
+					t.Error("CreateWitness failed. Lagrange witness differs from the monomial one.")
+				}
+				if lpk.Vk.VerifyEval(g2, i, res, g1) != true {
+					t.Error("VerifyEval failed, expected: true.")
+				}
+				res.Add(res, one)
+				if lpk.Vk.VerifyEval(g2, i, res, g1) != false {
+					t.Error("VerifyEval failed, expected: false.")
+				}
+			}
+		})
+	}
+}
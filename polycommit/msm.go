@@ -0,0 +1,132 @@
+package polycommit
+
+// This file implements multi-scalar multiplication with the bucket method,
+// N. Pippenger.
+// On the Evaluation of Powers and Monomials.
+// Scalars are recoded into signed digits so that only half of the buckets
+// are needed and negative coefficients are handled by negating the point.
+
+import (
+	"github.com/zhtluo/libpolycrypto/curve"
+)
+
+// Choose the window size in bits for n points.
+func msmWindow(n int) uint {
+	// Roughly log2(n) - 2, which balances bucket accumulation and aggregation.
+	c := uint(0)
+	for ; n > 1; n >>= 1 {
+		c++
+	}
+	if c < 5 {
+		return 3
+	}
+	if c > 18 {
+		return 16
+	}
+	return c - 2
+}
+
+// Recode the scalar k into signed digits of c bits,
+// each in [-2^(c-1), 2^(c-1)], from the least significant window.
+func signedDigits(k curve.Scalar, c uint, windows int) []int32 {
+	v := k.BigInt()
+	digits := make([]int32, windows)
+	full := uint64(1) << c
+	half := full >> 1
+	carry := uint64(0)
+	for w := 0; w < windows; w++ {
+		// Extract bits [w * c, (w + 1) * c) of the scalar.
+		d := uint64(0)
+		for b := uint(0); b < c; b++ {
+			if v.Bit(int(uint(w)*c+b)) == 1 {
+				d |= 1 << b
+			}
+		}
+		d += carry
+		if d > half {
+			digits[w] = int32(d) - int32(full)
+			carry = 1
+		} else {
+			digits[w] = int32(d)
+			carry = 0
+		}
+	}
+	return digits
+}
+
+// Recode every scalar of bits bits and return the number of windows used.
+func recodeScalars(scalars []curve.Scalar, c uint, bits int) ([][]int32, int) {
+	// One extra window absorbs the final carry.
+	windows := (bits+int(c)-1)/int(c) + 1
+	digits := make([][]int32, len(scalars))
+	for i := range scalars {
+		digits[i] = signedDigits(scalars[i], c, windows)
+	}
+	return digits, windows
+}
+
+// Compute the sum of scalars[i] * points[i] in the group built by newPoint,
+// whose order has bits bits.
+func multiExp(newPoint func() curve.Point, bits int, points []curve.Point, scalars []curve.Scalar) curve.Point {
+	ret := newPoint()
+	n := len(scalars)
+	if len(points) < n {
+		n = len(points)
+	}
+	if n == 0 {
+		return ret
+	}
+	c := msmWindow(n)
+	digits, windows := recodeScalars(scalars[:n], c, bits)
+	neg := make([]curve.Point, n)
+	for i := 0; i < n; i++ {
+		neg[i] = newPoint().Neg(points[i])
+	}
+	// A nil bucket is empty.
+	buckets := make([]curve.Point, 1<<(c-1))
+	running := newPoint()
+	sum := newPoint()
+	for w := windows - 1; w >= 0; w-- {
+		for j := uint(0); j < c; j++ {
+			ret.Add(ret, ret)
+		}
+		for b := range buckets {
+			buckets[b] = nil
+		}
+		for i := 0; i < n; i++ {
+			d := digits[i][w]
+			p := points[i]
+			if d == 0 {
+				continue
+			} else if d < 0 {
+				d, p = -d, neg[i]
+			}
+			if buckets[d-1] != nil {
+				buckets[d-1].Add(buckets[d-1], p)
+			} else {
+				buckets[d-1] = newPoint().Set(p)
+			}
+		}
+		// sum_b b * bucket[b] via running sums from the top bucket down.
+		running.SetInfinity()
+		sum.SetInfinity()
+		for b := len(buckets) - 1; b >= 0; b-- {
+			if buckets[b] != nil {
+				running.Add(running, buckets[b])
+			}
+			sum.Add(sum, running)
+		}
+		ret.Add(ret, sum)
+	}
+	return ret
+}
+
+// Compute the sum of scalars[i] * points[i] in G1 of the curve c.
+func MultiExpG1(c curve.Curve, points []curve.Point, scalars []curve.Scalar) curve.Point {
+	return multiExp(c.NewG1, c.Order().BitLen(), points, scalars)
+}
+
+// Compute the sum of scalars[i] * points[i] in G2 of the curve c.
+func MultiExpG2(c curve.Curve, points []curve.Point, scalars []curve.Scalar) curve.Point {
+	return multiExp(c.NewG2, c.Order().BitLen(), points, scalars)
+}
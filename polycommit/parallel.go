@@ -0,0 +1,220 @@
+package polycommit
+
+// This file implements the context-aware variants of Setup, Commit and
+// CreateWitness. They split the work among a pool of goroutines, stop as
+// soon as the context is done and give the same results as the sequential
+// versions.
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"runtime"
+	"sync"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/poly"
+)
+
+var (
+	// Number of powers generated by a task of Setup.
+	setupChunk = 1 << 6
+	// Number of terms of a task of the multi-exponentiations.
+	msmChunk = 1 << 12
+)
+
+// Run f on the ranges [lo, hi) covering [0, n) in chunks of size chunk,
+// with workers goroutines or one per processor if workers is not positive.
+// No chunk is started once ctx is done, in which case its error is returned.
+func parallel(ctx context.Context, n int, chunk int, workers int, f func(lo, hi int)) error {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	tasks := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for lo := range tasks {
+				hi := lo + chunk
+				if hi > n {
+					hi = n
+				}
+				f(lo, hi)
+			}
+		}()
+	}
+	err := ctx.Err()
+	for lo := 0; lo < n && err == nil; lo += chunk {
+		select {
+		case tasks <- lo:
+			err = ctx.Err()
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+	close(tasks)
+	wg.Wait()
+	return err
+}
+
+// Compute the sum of scalars[i] * points[i] in the group built by newPoint
+// with workers goroutines, splitting the terms among calls of msm.
+func multiExpContext(ctx context.Context, newPoint func() curve.Point, msm func(points []curve.Point, scalars []curve.Scalar) curve.Point, points []curve.Point, scalars []curve.Scalar, workers int) (curve.Point, error) {
+	n := len(scalars)
+	if len(points) < n {
+		n = len(points)
+	}
+	partial := make([]curve.Point, (n+msmChunk-1)/msmChunk)
+	err := parallel(ctx, n, msmChunk, workers, func(lo, hi int) {
+		partial[lo/msmChunk] = msm(points[lo:hi], scalars[lo:hi])
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := newPoint()
+	for _, p := range partial {
+		ret.Add(ret, p)
+	}
+	return ret, nil
+}
+
+// Compute the sum of scalars[i] * points[i] in G1 of the curve c with workers goroutines.
+func MultiExpG1Context(ctx context.Context, c curve.Curve, points []curve.Point, scalars []curve.Scalar, workers int) (curve.Point, error) {
+	return multiExpContext(ctx, c.NewG1, func(points []curve.Point, scalars []curve.Scalar) curve.Point {
+		return MultiExpG1(c, points, scalars)
+	}, points, scalars, workers)
+}
+
+// Compute the sum of scalars[i] * points[i] in G2 of the curve c with workers goroutines.
+func MultiExpG2Context(ctx context.Context, c curve.Curve, points []curve.Point, scalars []curve.Scalar, workers int) (curve.Point, error) {
+	return multiExpContext(ctx, c.NewG2, func(points []curve.Point, scalars []curve.Scalar) curve.Point {
+		return MultiExpG2(c, points, scalars)
+	}, points, scalars, workers)
+}
+
+// Return alpha^i for i < t as big.Int.
+func powersBig(alpha curve.Scalar, t int) []*big.Int {
+	e := make([]*big.Int, t)
+	am := alpha.Curve().NewScalar().SetOne()
+	for i := range e {
+		e[i] = am.BigInt()
+		am.Mul(am, alpha)
+	}
+	return e
+}
+
+// Create a new public key for commitment on the curve c as Setup does,
+// with the randomness generated in reader r and degree t.
+// The public key is left untouched unless Setup completes.
+func (pk *Pk) SetupContext(ctx context.Context, c curve.Curve, r io.Reader, t int, workers int) error {
+	alpha, err := randomScalar(c, r)
+	if err != nil {
+		return err
+	}
+	e := powersBig(alpha, t)
+	g1P := make([]curve.Point, t)
+	g2P := make([]curve.Point, t)
+	err = parallel(ctx, t, setupChunk, workers, func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			g1P[i] = c.NewG1().ScalarBaseMult(e[i])
+			g2P[i] = c.NewG2().ScalarBaseMult(e[i])
+		}
+	})
+	if err != nil {
+		return err
+	}
+	pk.Curve, pk.G1P, pk.G2P = c, g1P, g2P
+	return nil
+}
+
+// Generate the commitment of the polynomial poly as Commit does.
+func (pk *Pk) CommitContext(ctx context.Context, poly []curve.Scalar, workers int) (curve.Point, error) {
+	if err := pk.checkPoly(poly); err != nil {
+		return nil, err
+	}
+	return MultiExpG2Context(ctx, pk.Curve, pk.G2P, poly, workers)
+}
+
+// Create a witness g1 to the evaluation of the polynomial p at i as CreateWitness does.
+func (pk *Pk) CreateWitnessContext(ctx context.Context, p []curve.Scalar, i curve.Scalar, workers int) (res curve.Scalar, g1 curve.Point, err error) {
+	if err = pk.checkPoly(p); err != nil {
+		return nil, nil, err
+	}
+	quotient, res := poly.DivLinear(p, i)
+	if g1, err = MultiExpG1Context(ctx, pk.Curve, pk.G1P, quotient, workers); err != nil {
+		return nil, nil, err
+	}
+	return res, g1, nil
+}
+
+// Create a proof that the polynomial poly has fewer than d coefficients
+// as CreateDegreeProof does.
+func (pk *Pk) CreateDegreeProofContext(ctx context.Context, poly []curve.Scalar, d int, workers int) (curve.Point, error) {
+	shift, err := degreeShift(pk.Degree(), poly, d)
+	if err != nil {
+		return nil, err
+	}
+	return MultiExpG1Context(ctx, pk.Curve, pk.G1P[shift:shift+len(poly)], poly, workers)
+}
+
+// Create a new public key for commitment in G1 on the curve c as Setup does,
+// with the randomness generated in reader r and degree t,
+// supporting degree proofs for the degree bounds bounds.
+// The public key is left untouched unless Setup completes.
+func (pk *G1Pk) SetupContext(ctx context.Context, c curve.Curve, r io.Reader, t int, workers int, bounds ...int) error {
+	if err := checkBounds(t, bounds); err != nil {
+		return err
+	}
+	alpha, err := randomScalar(c, r)
+	if err != nil {
+		return err
+	}
+	e := powersBig(alpha, t)
+	g1P := make([]curve.Point, t)
+	err = parallel(ctx, t, setupChunk, workers, func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			g1P[i] = c.NewG1().ScalarBaseMult(e[i])
+		}
+	})
+	if err != nil {
+		return err
+	}
+	pk.Curve = c
+	pk.G1P = g1P
+	pk.G2 = c.NewG2().ScalarBaseMult(big.NewInt(1))
+	pk.G2Alpha = c.NewG2().ScalarBaseMult(alpha.BigInt())
+	pk.setBounds(alpha, t, bounds)
+	return nil
+}
+
+// Generate the commitment of the polynomial poly as Commit does.
+func (pk *G1Pk) CommitContext(ctx context.Context, poly []curve.Scalar, workers int) (curve.Point, error) {
+	if err := pk.checkPoly(poly); err != nil {
+		return nil, err
+	}
+	return MultiExpG1Context(ctx, pk.Curve, pk.G1P, poly, workers)
+}
+
+// Create a witness g1 to the evaluation of the polynomial p at i as CreateWitness does.
+func (pk *G1Pk) CreateWitnessContext(ctx context.Context, p []curve.Scalar, i curve.Scalar, workers int) (res curve.Scalar, g1 curve.Point, err error) {
+	if err = pk.checkPoly(p); err != nil {
+		return nil, nil, err
+	}
+	quotient, res := poly.DivLinear(p, i)
+	if g1, err = MultiExpG1Context(ctx, pk.Curve, pk.G1P, quotient, workers); err != nil {
+		return nil, nil, err
+	}
+	return res, g1, nil
+}
+
+// Create a proof that the polynomial poly has fewer than d coefficients
+// as CreateDegreeProof does.
+func (pk *G1Pk) CreateDegreeProofContext(ctx context.Context, poly []curve.Scalar, d int, workers int) (curve.Point, error) {
+	shift, err := degreeShift(pk.Degree(), poly, d)
+	if err != nil {
+		return nil, err
+	}
+	return MultiExpG1Context(ctx, pk.Curve, pk.G1P[shift:shift+len(poly)], poly, workers)
+}
@@ -0,0 +1,127 @@
+package polycommit
+
+import (
+	"testing"
+
+	"bytes"
+	"context"
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+)
+
+// Return two readers yielding the same random bytes.
+func sameReaders() (*bytes.Reader, *bytes.Reader) {
+	b := make([]byte, 1024)
+	rand.Read(b)
+	return bytes.NewReader(b), bytes.NewReader(b)
+}
+
+func TestSetupContext(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			defer func(n int) { setupChunk = n }(setupChunk)
+			setupChunk = 7
+			r, rc := sameReaders()
+			var pk, pkc Pk
+			pk.Setup(c, r, deg)
+			if err := pkc.SetupContext(context.Background(), c, rc, deg, 3); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < deg; i++ {
+				if !pk.G1P[i].Equal(pkc.G1P[i]) || !pk.G2P[i].Equal(pkc.G2P[i]) {
+					t.Fatal("SetupContext failed. Expected the public key of Setup.")
+				}
+			}
+			r, rc = sameReaders()
+			var g1Pk, g1Pkc G1Pk
+			g1Pk.Setup(c, r, deg)
+			if err := g1Pkc.SetupContext(context.Background(), c, rc, deg, 0); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < deg; i++ {
+				if !g1Pk.G1P[i].Equal(g1Pkc.G1P[i]) {
+					t.Fatal("SetupContext failed. Expected the public key of Setup.")
+				}
+			}
+			if !g1Pk.G2Alpha.Equal(g1Pkc.G2Alpha) {
+				t.Error("SetupContext failed. Expected the public key of Setup.")
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			var empty Pk
+			if err := empty.SetupContext(ctx, c, rand.Reader, deg, 0); err != context.Canceled {
+				t.Error("SetupContext failed. Expected: context.Canceled.")
+			}
+			if empty.G1P != nil || empty.G2P != nil {
+				t.Error("SetupContext failed. Canceled setup modified the public key.")
+			}
+		})
+	}
+}
+
+func TestCommitContext(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			defer func(n int) { msmChunk = n }(msmChunk)
+			msmChunk = 7
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			g1Pk, _ := pk.G1Pk()
+			ctx := context.Background()
+			for _, workers := range []int{1, 4, 0} {
+				poly := generatePoly(c, rand.Reader)
+				com, _ := pk.Commit(poly)
+				comc, err := pk.CommitContext(ctx, poly, workers)
+				if err != nil || !com.Equal(comc) {
+					t.Error("CommitContext failed. Expected the commitment of Commit.")
+				}
+				g1c, _ := g1Pk.Commit(poly)
+				g1cc, err := g1Pk.CommitContext(ctx, poly, workers)
+				if err != nil || !g1c.Equal(g1cc) {
+					t.Error("CommitContext failed. Expected the commitment of Commit.")
+				}
+				i := randomElement(c, rand.Reader)
+				res, w, _ := pk.CreateWitness(poly, i)
+				resc, wc, err := pk.CreateWitnessContext(ctx, poly, i, workers)
+				if err != nil || !res.Equal(resc) || !w.Equal(wc) {
+					t.Error("CreateWitnessContext failed. Expected the witness of CreateWitness.")
+				}
+				res, w, _ = g1Pk.CreateWitness(poly, i)
+				resc, wc, err = g1Pk.CreateWitnessContext(ctx, poly, i, workers)
+				if err != nil || !res.Equal(resc) || !w.Equal(wc) {
+					t.Error("CreateWitnessContext failed. Expected the witness of CreateWitness.")
+				}
+				p, _ := pk.CreateDegreeProof(poly[:deg/2], deg/2)
+				pc, err := pk.CreateDegreeProofContext(ctx, poly[:deg/2], deg/2, workers)
+				if err != nil || !p.Equal(pc) {
+					t.Error("CreateDegreeProofContext failed. Expected the proof of CreateDegreeProof.")
+				}
+			}
+			canceled, cancel := context.WithCancel(ctx)
+			cancel()
+			if _, err := pk.CommitContext(canceled, generatePoly(c, rand.Reader), 0); err != context.Canceled {
+				t.Error("CommitContext failed. Expected: context.Canceled.")
+			}
+			if _, _, err := pk.CreateWitnessContext(canceled, generatePoly(c, rand.Reader), randomElement(c, rand.Reader), 0); err != context.Canceled {
+				t.Error("CreateWitnessContext failed. Expected: context.Canceled.")
+			}
+		})
+	}
+}
+
+func BenchmarkSetupContext(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var pk Pk
+		pk.SetupContext(context.Background(), curve.BN256, rand.Reader, deg, 0)
+	}
+}
+
+func BenchmarkCommitContext(b *testing.B) {
+	var pk Pk
+	pk.Setup(curve.BN256, rand.Reader, deg)
+	poly := generatePoly(pk.Curve, rand.Reader)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pk.CommitContext(context.Background(), poly, 0)
+	}
+}
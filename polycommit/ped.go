@@ -0,0 +1,256 @@
+package polycommit
+
+// This file implements polycommit_ped found in section 3.3,
+// A. Kate, et al.
+// Constant-Size Commitments to Polynomials and Their Applications.
+// The commitment is unconditionally hiding as every commitment is blinded
+// with a random polynomial in the exponent of a second generator h.
+
+import (
+	"errors"
+	"io"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/point"
+	"github.com/zhtluo/libpolycrypto/poly"
+	pb "github.com/zhtluo/libpolycrypto/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Struct PedPk implements a public key for polycommit_ped to function on.
+// H1P and H2P hold the powers of the second generator h.
+type PedPk struct {
+	Curve curve.Curve
+	G1P   []curve.Point
+	G2P   []curve.Point
+	H1P   []curve.Point
+	H2P   []curve.Point
+}
+
+func (pk *PedPk) checkPoly(poly []curve.Scalar, blind []curve.Scalar) error {
+	if len(poly) < 1 {
+		return errors.New("Polynomial is empty")
+	}
+	if len(blind) < 1 {
+		return errors.New("Blinding polynomial is empty")
+	}
+	if pk.Degree() < len(poly) || pk.Degree() < len(blind) {
+		return errors.New("Public key has a degree less than the polynomial")
+	}
+	return nil
+}
+
+// Create a new public key for commitment on the curve c,
+// with the randomness generated in reader r and degree t.
+func (pk *PedPk) Setup(c curve.Curve, r io.Reader, t int) error {
+	alpha, err := randomScalar(c, r)
+	if err != nil {
+		return err
+	}
+	// h = g^lambda where lambda is discarded right after.
+	lambda, err := randomScalar(c, r)
+	if err != nil {
+		return err
+	}
+	pk.Curve = c
+	pk.G1P = make([]curve.Point, t)
+	pk.G2P = make([]curve.Point, t)
+	pk.H1P = make([]curve.Point, t)
+	pk.H2P = make([]curve.Point, t)
+	am := c.NewScalar().SetOne()
+	hm := c.NewScalar()
+	for i := 0; i < t; i++ {
+		e := am.BigInt()
+		h := hm.Mul(am, lambda).BigInt()
+		pk.G1P[i] = c.NewG1().ScalarBaseMult(e)
+		pk.G2P[i] = c.NewG2().ScalarBaseMult(e)
+		pk.H1P[i] = c.NewG1().ScalarBaseMult(h)
+		pk.H2P[i] = c.NewG2().ScalarBaseMult(h)
+		am.Mul(am, alpha)
+	}
+	return nil
+}
+
+// Return the degree of the current public key.
+func (pk *PedPk) Degree() int {
+	return len(pk.G1P)
+}
+
+// Generate the commitment of the polynomial poly blinded by the polynomial blind.
+func (pk *PedPk) Commit(poly []curve.Scalar, blind []curve.Scalar) (curve.Point, error) {
+	err := pk.checkPoly(poly, blind)
+	if err != nil {
+		return nil, err
+	}
+	c := pk.Curve
+	return c.NewG2().Add(MultiExpG2(c, pk.G2P, poly), MultiExpG2(c, pk.H2P, blind)), nil
+}
+
+// Verify that the commitment g2 is consistent with the polynomial poly
+// and the blinding polynomial blind.
+func (pk *PedPk) VerifyPoly(poly []curve.Scalar, blind []curve.Scalar, g2 curve.Point) bool {
+	g2c, err := pk.Commit(poly, blind)
+	if err != nil {
+		return false
+	}
+	return g2.Equal(g2c)
+}
+
+// Create a witness g1 to the evaluation of the polynomial p at i.
+// The evaluation of the blinding polynomial blind at i is returned in blindRes.
+func (pk *PedPk) CreateWitness(p []curve.Scalar, blind []curve.Scalar, i curve.Scalar) (res curve.Scalar, blindRes curve.Scalar, g1 curve.Point, err error) {
+	err = pk.checkPoly(p, blind)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c := pk.Curve
+	quotient, res := poly.DivLinear(p, i)
+	blindQuotient, blindRes := poly.DivLinear(blind, i)
+	g1 = c.NewG1().Add(MultiExpG1(c, pk.G1P, quotient), MultiExpG1(c, pk.H1P, blindQuotient))
+	return res, blindRes, g1, nil
+}
+
+// Return a copy of the first degree powers of the public key.
+func (pk *PedPk) Trim(degree int) (*PedPk, error) {
+	if degree < 1 {
+		return nil, errors.New("Degree is not positive")
+	}
+	if pk.Degree() < degree || len(pk.G2P) < degree || len(pk.H1P) < degree || len(pk.H2P) < degree {
+		return nil, errors.New("Public key has a degree less than the requested one")
+	}
+	tpk := new(PedPk)
+	tpk.Curve = pk.Curve
+	tpk.G1P = append([]curve.Point{}, pk.G1P[:degree]...)
+	tpk.G2P = append([]curve.Point{}, pk.G2P[:degree]...)
+	tpk.H1P = append([]curve.Point{}, pk.H1P[:degree]...)
+	tpk.H2P = append([]curve.Point{}, pk.H2P[:degree]...)
+	return tpk, nil
+}
+
+// Struct PedVerifierKey implements the public key of a verifier for polycommit_ped,
+// holding the generators G1, H1, G2 and G2Alpha = G2^alpha of Curve.
+type PedVerifierKey struct {
+	Curve   curve.Curve
+	G1      curve.Point
+	H1      curve.Point
+	G2      curve.Point
+	G2Alpha curve.Point
+}
+
+// Extract the verifier key from the public key.
+func (pk *PedPk) VerifierKey() (*PedVerifierKey, error) {
+	if pk.Degree() < 2 || len(pk.G2P) < 2 || len(pk.H1P) < 1 {
+		return nil, errors.New("Public key has a degree less than 2")
+	}
+	vk := new(PedVerifierKey)
+	vk.Curve = pk.Curve
+	vk.G1 = pk.G1P[0]
+	vk.H1 = pk.H1P[0]
+	vk.G2 = pk.G2P[0]
+	vk.G2Alpha = pk.G2P[1]
+	return vk, nil
+}
+
+// Verify the evaluation of the polynomial with the commitment g2 and the witness g1.
+func (vk *PedVerifierKey) VerifyEval(g2 curve.Point, i curve.Scalar, res curve.Scalar, blindRes curve.Scalar, g1 curve.Point) bool {
+	c := vk.Curve
+	p := c.NewG2().ScalarMult(vk.G2, i.BigInt())
+	p.Add(vk.G2Alpha, p.Neg(p))
+	// e(w, g^(alpha - i)) * e(g^res * h^blindRes, g) * e(-g, C) = 1
+	v := MultiExpG1(c, []curve.Point{vk.G1, vk.H1}, []curve.Scalar{res, blindRes})
+	return c.PairingCheck([]curve.Point{g1, v, c.NewG1().Neg(vk.G1)}, []curve.Point{p, vk.G2, g2})
+}
+
+// Serialize the verifier key.
+func (vk *PedVerifierKey) Marshal() ([]byte, error) {
+	return vk.MarshalFormat(point.Uncompressed)
+}
+
+// Serialize the verifier key with the points encoded in the format f.
+func (vk *PedVerifierKey) MarshalFormat(f point.Format) ([]byte, error) {
+	var sVk pb.PedVerifierKey
+	sVk.G1 = curve.Encode(vk.G1, f)
+	sVk.H1 = curve.Encode(vk.H1, f)
+	sVk.G2 = curve.Encode(vk.G2, f)
+	sVk.G2Alpha = curve.Encode(vk.G2Alpha, f)
+	sVk.Format = uint32(f)
+	sVk.Curve = vk.Curve.Name()
+	return proto.Marshal(&sVk)
+}
+
+// Deserialize the verifier key.
+func (vk *PedVerifierKey) Unmarshal(b []byte) error {
+	var sVk pb.PedVerifierKey
+	err := proto.Unmarshal(b, &sVk)
+	if err != nil {
+		return err
+	}
+	c, err := curve.ByName(sVk.Curve)
+	if err != nil {
+		return err
+	}
+	f := point.Format(sVk.Format)
+	if vk.G1, err = decodePoint(c.NewG1, sVk.G1, f); err != nil {
+		return err
+	}
+	if vk.H1, err = decodePoint(c.NewG1, sVk.H1, f); err != nil {
+		return err
+	}
+	if vk.G2, err = decodePoint(c.NewG2, sVk.G2, f); err != nil {
+		return err
+	}
+	if vk.G2Alpha, err = decodePoint(c.NewG2, sVk.G2Alpha, f); err != nil {
+		return err
+	}
+	vk.Curve = c
+	return nil
+}
+
+// Serialize the specified public key
+func (pk *PedPk) Marshal() ([]byte, error) {
+	return pk.MarshalFormat(point.Uncompressed)
+}
+
+// Serialize the specified public key with the points encoded in the format f.
+func (pk *PedPk) MarshalFormat(f point.Format) ([]byte, error) {
+	var sPk pb.PedPk
+	sPk.G1P = encodePoints(pk.G1P, f)
+	sPk.G2P = encodePoints(pk.G2P, f)
+	sPk.H1P = encodePoints(pk.H1P, f)
+	sPk.H2P = encodePoints(pk.H2P, f)
+	sPk.Format = uint32(f)
+	sPk.Curve = pk.Curve.Name()
+	return proto.Marshal(&sPk)
+}
+
+// Deserialize the specified public key
+func (pk *PedPk) Unmarshal(b []byte) error {
+	var sPk pb.PedPk
+	err := proto.Unmarshal(b, &sPk)
+	if err != nil {
+		return err
+	}
+	t := len(sPk.G1P)
+	if len(sPk.G2P) != t || len(sPk.H1P) != t || len(sPk.H2P) != t {
+		return errors.New("Public key has powers of different lengths")
+	}
+	c, err := curve.ByName(sPk.Curve)
+	if err != nil {
+		return err
+	}
+	f := point.Format(sPk.Format)
+	if pk.G1P, err = decodePoints(c.NewG1, sPk.G1P, f); err != nil {
+		return err
+	}
+	if pk.G2P, err = decodePoints(c.NewG2, sPk.G2P, f); err != nil {
+		return err
+	}
+	if pk.H1P, err = decodePoints(c.NewG1, sPk.H1P, f); err != nil {
+		return err
+	}
+	if pk.H2P, err = decodePoints(c.NewG2, sPk.H2P, f); err != nil {
+		return err
+	}
+	pk.Curve = c
+	return nil
+}
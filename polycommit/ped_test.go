@@ -0,0 +1,140 @@
+package polycommit
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+)
+
+func TestPedCommit(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk PedPk
+			pk.Setup(c, rand.Reader, deg)
+			poly := generatePoly(c, rand.Reader)
+			blind := generatePoly(c, rand.Reader)
+			g2, err := pk.Commit(poly, blind)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if pk.VerifyPoly(poly, blind, g2) != true {
+				t.Error("VerifyPoly failed, expected: true.")
+			}
+			if pk.VerifyPoly(poly, generatePoly(c, rand.Reader), g2) != false {
+				t.Error("VerifyPoly failed, expected: false.")
+			}
+			// The same polynomial with different blinding gives different commitments.
+			g2b, err := pk.Commit(poly, generatePoly(c, rand.Reader))
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if g2.Equal(g2b) {
+				t.Error("Commit failed, expected different commitments.")
+			}
+		})
+	}
+}
+
+func TestPedWitness(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk PedPk
+			pk.Setup(c, rand.Reader, deg)
+			vk, _ := pk.VerifierKey()
+			// x^3 - 2x^2 + 7x - 5
+			poly := []curve.Scalar{curve.NewScalar(c, -5), curve.NewScalar(c, 7), curve.NewScalar(c, -2), curve.NewScalar(c, 1)}
+			blind := generatePoly(c, rand.Reader)
+			g2, err := pk.Commit(poly, blind)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			i := curve.NewScalar(c, 3)
+			res, blindRes, g1, err := pk.CreateWitness(poly, blind, i)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if !res.Equal(curve.NewScalar(c, 25)) {
+				t.Error("CreateWitness failed. Wrong evaluation result.")
+			}
+			if vk.VerifyEval(g2, i, res, blindRes, g1) != true {
+				t.Error("VerifyEval failed, expected: true.")
+			}
+			if vk.VerifyEval(g2, i, curve.NewScalar(c, 24), blindRes, g1) != false {
+				t.Error("VerifyEval failed, expected: false.")
+			}
+			// random polynomial
+			poly = generatePoly(c, rand.Reader)
+			g2, err = pk.Commit(poly, blind)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			i.SetRandom(rand.Reader)
+			res, blindRes, g1, err = pk.CreateWitness(poly, blind, i)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if vk.VerifyEval(g2, i, res, blindRes, g1) != true {
+				t.Error("VerifyEval failed, expected: true.")
+			}
+			blindRes.SetRandom(rand.Reader)
+			if vk.VerifyEval(g2, i, res, blindRes, g1) != false {
+				t.Error("VerifyEval failed, expected: false.")
+			}
+		})
+	}
+}
+
+func TestPedMarshal(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk, rPk PedPk
+			pk.Setup(c, rand.Reader, deg)
+			b, err := pk.Marshal()
+			if err != nil {
+				t.Error(err)
+			}
+			err = rPk.Unmarshal(b)
+			if err != nil {
+				t.Error(err)
+			}
+			if rPk.Degree() != pk.Degree() {
+				t.Fatal("Marshal does not generate equal result.")
+			}
+			for i := range pk.G1P {
+				if !pk.G1P[i].Equal(rPk.G1P[i]) ||
+					!pk.G2P[i].Equal(rPk.G2P[i]) ||
+					!pk.H1P[i].Equal(rPk.H1P[i]) ||
+					!pk.H2P[i].Equal(rPk.H2P[i]) {
+					t.Error("Marshal does not generate equal result.")
+				}
+			}
+			// A verifier key from the trimmed public key survives serialization.
+			tpk, err := pk.Trim(2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vk, err := tpk.VerifierKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err = vk.Marshal()
+			if err != nil {
+				t.Error(err)
+			}
+			var rVk PedVerifierKey
+			err = rVk.Unmarshal(b)
+			if err != nil {
+				t.Error(err)
+			}
+			poly := generatePoly(c, rand.Reader)
+			blind := generatePoly(c, rand.Reader)
+			g2, _ := pk.Commit(poly, blind)
+			i := randomElement(c, rand.Reader)
+			res, blindRes, g1, _ := pk.CreateWitness(poly, blind, i)
+			if rVk.VerifyEval(g2, i, res, blindRes, g1) != true {
+				t.Error("VerifyEval failed, expected: true.")
+			}
+		})
+	}
+}
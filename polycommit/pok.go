@@ -0,0 +1,179 @@
+package polycommit
+
+// This file implements non-interactive zero-knowledge proofs of knowledge of
+// the polynomial behind a commitment, in the spirit of the proofs of
+// knowledge in section 5 of the paper. The prover commits to a random mask
+// polynomial r as A, derives the challenge c from the transcript and
+// responds with z = r + c * p, which the verifier checks against A + c * C
+// with the homomorphism of the commitment. The response reveals nothing
+// about p besides its number of coefficients.
+
+import (
+	"io"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/poly"
+	"github.com/zhtluo/libpolycrypto/transcript"
+)
+
+// Struct OpeningProof implements a proof of knowledge of the polynomial
+// committed in G2 with Pk.
+type OpeningProof struct {
+	A curve.Point
+	Z []curve.Scalar
+}
+
+// Struct G1OpeningProof implements OpeningProof for commitments in G1.
+type G1OpeningProof struct {
+	A curve.Point
+	Z []curve.Scalar
+}
+
+// Struct PedOpeningProof implements a proof of knowledge of the polynomial
+// and the blinding polynomial committed with PedPk.
+type PedOpeningProof struct {
+	A      curve.Point
+	Z      []curve.Scalar
+	ZBlind []curve.Scalar
+}
+
+// Derive the challenge of the curve c from the serialized commitment com,
+// the serialized mask commitment a and the numbers of coefficients n.
+func openingChallenge(c curve.Curve, label string, com []byte, a []byte, n ...int) curve.Scalar {
+	t := transcript.New(label)
+	t.AppendMessage("commit", com)
+	for _, k := range n {
+		t.AppendUint64("n", uint64(k))
+	}
+	t.AppendMessage("mask", a)
+	return challengeScalar(c, t, "challenge")
+}
+
+// Check that the mask commitment a and every response z are present,
+// so that a malformed proof is rejected before it is hashed.
+func checkOpening(a curve.Point, z ...[]curve.Scalar) bool {
+	if a == nil {
+		return false
+	}
+	for _, zi := range z {
+		if len(zi) < 1 {
+			return false
+		}
+		for _, s := range zi {
+			if s == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Return the response z = r + c * p.
+func openingResponse(r []curve.Scalar, c curve.Scalar, p []curve.Scalar) []curve.Scalar {
+	return poly.Add(r, poly.Scale(p, c))
+}
+
+// Return the response scalars followed by -1 and -c, so that the
+// multi-exponentiation over the key, A and C is zero for a valid proof.
+func openingScalars(z []curve.Scalar, c curve.Scalar) []curve.Scalar {
+	scalars := make([]curve.Scalar, len(z)+2)
+	copy(scalars, z)
+	scalars[len(z)] = c.Curve().NewScalar().SetInt64(-1)
+	scalars[len(z)+1] = c.Curve().NewScalar().Neg(c)
+	return scalars
+}
+
+// Create a proof of knowledge of the polynomial p with the commitment g2,
+// with the randomness in reader r.
+func (pk *Pk) ProveOpening(r io.Reader, p []curve.Scalar, g2 curve.Point) (*OpeningProof, error) {
+	if err := pk.checkPoly(p); err != nil {
+		return nil, err
+	}
+	mask, err := poly.Random(pk.Curve, r, len(p))
+	if err != nil {
+		return nil, err
+	}
+	proof := new(OpeningProof)
+	proof.A = MultiExpG2(pk.Curve, pk.G2P, mask)
+	c := openingChallenge(pk.Curve, "polycommit-opening", g2.Marshal(), proof.A.Marshal(), len(p))
+	proof.Z = openingResponse(mask, c, p)
+	return proof, nil
+}
+
+// Verify the proof of knowledge of the polynomial committed in g2.
+func (pk *Pk) VerifyOpening(g2 curve.Point, proof *OpeningProof) bool {
+	if proof == nil || !checkOpening(proof.A, proof.Z) || pk.checkPoly(proof.Z) != nil {
+		return false
+	}
+	c := openingChallenge(pk.Curve, "polycommit-opening", g2.Marshal(), proof.A.Marshal(), len(proof.Z))
+	// sum_i z_i * g^(alpha^i) - A - c * C = 0
+	points := append(append([]curve.Point{}, pk.G2P[:len(proof.Z)]...), proof.A, g2)
+	return MultiExpG2(pk.Curve, points, openingScalars(proof.Z, c)).IsInfinity()
+}
+
+// Create a proof of knowledge of the polynomial p with the commitment g1 in G1,
+// with the randomness in reader r.
+func (pk *G1Pk) ProveOpening(r io.Reader, p []curve.Scalar, g1 curve.Point) (*G1OpeningProof, error) {
+	if err := pk.checkPoly(p); err != nil {
+		return nil, err
+	}
+	mask, err := poly.Random(pk.Curve, r, len(p))
+	if err != nil {
+		return nil, err
+	}
+	proof := new(G1OpeningProof)
+	proof.A = MultiExpG1(pk.Curve, pk.G1P, mask)
+	c := openingChallenge(pk.Curve, "polycommit-opening-g1", g1.Marshal(), proof.A.Marshal(), len(p))
+	proof.Z = openingResponse(mask, c, p)
+	return proof, nil
+}
+
+// Verify the proof of knowledge of the polynomial committed in g1.
+func (pk *G1Pk) VerifyOpening(g1 curve.Point, proof *G1OpeningProof) bool {
+	if proof == nil || !checkOpening(proof.A, proof.Z) || pk.checkPoly(proof.Z) != nil {
+		return false
+	}
+	c := openingChallenge(pk.Curve, "polycommit-opening-g1", g1.Marshal(), proof.A.Marshal(), len(proof.Z))
+	points := append(append([]curve.Point{}, pk.G1P[:len(proof.Z)]...), proof.A, g1)
+	return MultiExpG1(pk.Curve, points, openingScalars(proof.Z, c)).IsInfinity()
+}
+
+// Create a proof of knowledge of the polynomial p and the blinding polynomial
+// blind with the commitment g2, with the randomness in reader r.
+func (pk *PedPk) ProveOpening(r io.Reader, p []curve.Scalar, blind []curve.Scalar, g2 curve.Point) (*PedOpeningProof, error) {
+	if err := pk.checkPoly(p, blind); err != nil {
+		return nil, err
+	}
+	mask, err := poly.Random(pk.Curve, r, len(p))
+	if err != nil {
+		return nil, err
+	}
+	blindMask, err := poly.Random(pk.Curve, r, len(blind))
+	if err != nil {
+		return nil, err
+	}
+	a, err := pk.Commit(mask, blindMask)
+	if err != nil {
+		return nil, err
+	}
+	proof := new(PedOpeningProof)
+	proof.A = a
+	c := openingChallenge(pk.Curve, "polycommit-opening-ped", g2.Marshal(), proof.A.Marshal(), len(p), len(blind))
+	proof.Z = openingResponse(mask, c, p)
+	proof.ZBlind = openingResponse(blindMask, c, blind)
+	return proof, nil
+}
+
+// Verify the proof of knowledge of the polynomial and the blinding polynomial
+// committed in g2.
+func (pk *PedPk) VerifyOpening(g2 curve.Point, proof *PedOpeningProof) bool {
+	if proof == nil || !checkOpening(proof.A, proof.Z, proof.ZBlind) || pk.checkPoly(proof.Z, proof.ZBlind) != nil {
+		return false
+	}
+	c := openingChallenge(pk.Curve, "polycommit-opening-ped", g2.Marshal(), proof.A.Marshal(), len(proof.Z), len(proof.ZBlind))
+	n, m := len(proof.Z), len(proof.ZBlind)
+	points := append([]curve.Point{}, pk.G2P[:n]...)
+	points = append(append(points, pk.H2P[:m]...), proof.A, g2)
+	scalars := openingScalars(append(append([]curve.Scalar{}, proof.Z...), proof.ZBlind...), c)
+	return MultiExpG2(pk.Curve, points, scalars).IsInfinity()
+}
@@ -0,0 +1,132 @@
+package polycommit
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+)
+
+func TestProveOpening(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			poly := generatePoly(c, rand.Reader)[:deg/2]
+			g2, err := pk.Commit(poly)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			proof, err := pk.ProveOpening(rand.Reader, poly, g2)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if pk.VerifyOpening(g2, proof) != true {
+				t.Error("VerifyOpening failed, expected: true.")
+			}
+			other, _ := pk.Commit(generatePoly(c, rand.Reader))
+			if pk.VerifyOpening(other, proof) != false {
+				t.Error("VerifyOpening failed with another commitment, expected: false.")
+			}
+			proof.Z[0].SetRandom(rand.Reader)
+			if pk.VerifyOpening(g2, proof) != false {
+				t.Error("VerifyOpening failed with a wrong response, expected: false.")
+			}
+			if pk.VerifyOpening(g2, &OpeningProof{Z: proof.Z}) != false {
+				t.Error("VerifyOpening failed without a mask commitment, expected: false.")
+			}
+			if pk.VerifyOpening(g2, &OpeningProof{A: proof.A}) != false {
+				t.Error("VerifyOpening failed without a response, expected: false.")
+			}
+			if pk.VerifyOpening(g2, &OpeningProof{A: proof.A, Z: []curve.Scalar{nil}}) != false {
+				t.Error("VerifyOpening failed with a nil response, expected: false.")
+			}
+			if pk.VerifyOpening(g2, nil) != false {
+				t.Error("VerifyOpening failed without a proof, expected: false.")
+			}
+			// The response does not reveal the polynomial.
+			proof, _ = pk.ProveOpening(rand.Reader, poly, g2)
+			if proof.Z[0].Equal(poly[0]) {
+				t.Error("ProveOpening failed, response equals the polynomial.")
+			}
+		})
+	}
+}
+
+func TestProveOpeningG1(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk G1Pk
+			pk.Setup(c, rand.Reader, deg)
+			poly := generatePoly(c, rand.Reader)
+			g1, err := pk.Commit(poly)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			proof, err := pk.ProveOpening(rand.Reader, poly, g1)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if pk.VerifyOpening(g1, proof) != true {
+				t.Error("VerifyOpening failed, expected: true.")
+			}
+			proof.Z = proof.Z[1:]
+			if pk.VerifyOpening(g1, proof) != false {
+				t.Error("VerifyOpening failed with a truncated response, expected: false.")
+			}
+			if pk.VerifyOpening(g1, &G1OpeningProof{Z: proof.Z}) != false {
+				t.Error("VerifyOpening failed without a mask commitment, expected: false.")
+			}
+			if pk.VerifyOpening(g1, &G1OpeningProof{A: proof.A}) != false {
+				t.Error("VerifyOpening failed without a response, expected: false.")
+			}
+		})
+	}
+}
+
+func TestPedProveOpening(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk PedPk
+			pk.Setup(c, rand.Reader, deg)
+			poly := generatePoly(c, rand.Reader)
+			blind := generatePoly(c, rand.Reader)[:deg/4]
+			g2, err := pk.Commit(poly, blind)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			proof, err := pk.ProveOpening(rand.Reader, poly, blind, g2)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if pk.VerifyOpening(g2, proof) != true {
+				t.Error("VerifyOpening failed, expected: true.")
+			}
+			proof.ZBlind[0].SetRandom(rand.Reader)
+			if pk.VerifyOpening(g2, proof) != false {
+				t.Error("VerifyOpening failed with a wrong blinding response, expected: false.")
+			}
+			if pk.VerifyOpening(g2, &PedOpeningProof{Z: proof.Z, ZBlind: proof.ZBlind}) != false {
+				t.Error("VerifyOpening failed without a mask commitment, expected: false.")
+			}
+			if pk.VerifyOpening(g2, &PedOpeningProof{A: proof.A, ZBlind: proof.ZBlind}) != false {
+				t.Error("VerifyOpening failed without a response, expected: false.")
+			}
+			if pk.VerifyOpening(g2, &PedOpeningProof{A: proof.A, Z: proof.Z}) != false {
+				t.Error("VerifyOpening failed without a blinding response, expected: false.")
+			}
+		})
+	}
+}
+
+func BenchmarkVerifyOpening(b *testing.B) {
+	var pk Pk
+	pk.Setup(curve.BN256, rand.Reader, deg)
+	poly := generatePoly(pk.Curve, rand.Reader)
+	g2, _ := pk.Commit(poly)
+	proof, _ := pk.ProveOpening(rand.Reader, poly, g2)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		pk.VerifyOpening(g2, proof)
+	}
+}
@@ -1,17 +1,22 @@
 // Package polycommit implements polycommit_dl found in section 3.2,
+// polycommit_ped found in section 3.3 and the batch opening found in section 3.4,
 // A. Kate, et al.
 // Constant-Size Commitments to Polynomials and Their Applications.
+//
+// Every public key works on the curve.Curve it was set up or deserialized
+// with, and polynomials are slices of the scalars of that curve.
 
 package polycommit
 
 import (
-	"bytes"
 	"crypto/rand"
 	"errors"
 	"io"
 	"math/big"
 
-	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/point"
+	"github.com/zhtluo/libpolycrypto/poly"
 	pb "github.com/zhtluo/libpolycrypto/proto"
 	"google.golang.org/protobuf/proto"
 )
@@ -19,11 +24,12 @@ import (
 // Struct Pk implements a public key for polycommit to function on.
 // It is the basic structure for all operations.
 type Pk struct {
-	G1P []bn256.G1
-	G2P []bn256.G2
+	Curve curve.Curve
+	G1P   []curve.Point
+	G2P   []curve.Point
 }
 
-func (pk *Pk) checkPoly(poly []big.Int) error {
+func (pk *Pk) checkPoly(poly []curve.Scalar) error {
 	if len(poly) < 1 {
 		return errors.New("Polynomial is empty")
 	}
@@ -33,28 +39,67 @@ func (pk *Pk) checkPoly(poly []big.Int) error {
 	return nil
 }
 
-// Create a new public key for commitment,
-// with the randomness generated in reader r and degree t.
-func (pk *Pk) Setup(r io.Reader, t int) error {
-	pk.G1P = make([]bn256.G1, t)
-	pk.G2P = make([]bn256.G2, t)
-	alpha, err := rand.Int(r, bn256.Order)
-	if err != nil {
-		return err
+// Generate a random nonzero scalar of the curve c with the randomness in reader r.
+func randomScalar(c curve.Curve, r io.Reader) (curve.Scalar, error) {
+	k := c.NewScalar()
+	for {
+		_, err := k.SetRandom(r)
+		if err != nil {
+			return nil, err
+		}
+		if !k.IsZero() {
+			return k, nil
+		}
+	}
+}
+
+// Encode every point of ps in the format f.
+func encodePoints(ps []curve.Point, f point.Format) [][]byte {
+	ret := make([][]byte, len(ps))
+	for i := range ps {
+		ret[i] = curve.Encode(ps[i], f)
+	}
+	return ret
+}
+
+// Decode the point b encoded in the format f into a new point built by newPoint.
+func decodePoint(newPoint func() curve.Point, b []byte, f point.Format) (curve.Point, error) {
+	p := newPoint()
+	if err := curve.Decode(p, b, f); err != nil {
+		return nil, err
 	}
-	for alpha.Cmp(big.NewInt(0)) == 0 {
-		alpha, err = rand.Int(r, bn256.Order)
+	return p, nil
+}
+
+// Decode every point of bs encoded in the format f into new points built by newPoint.
+func decodePoints(newPoint func() curve.Point, bs [][]byte, f point.Format) ([]curve.Point, error) {
+	ret := make([]curve.Point, len(bs))
+	for i := range bs {
+		p, err := decodePoint(newPoint, bs[i], f)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		ret[i] = p
+	}
+	return ret, nil
+}
+
+// Create a new public key for commitment on the curve c,
+// with the randomness generated in reader r and degree t.
+func (pk *Pk) Setup(c curve.Curve, r io.Reader, t int) error {
+	alpha, err := randomScalar(c, r)
+	if err != nil {
+		return err
 	}
-	am := big.NewInt(1)
-	pk.G1P[0].ScalarBaseMult(big.NewInt(1))
-	pk.G2P[0].ScalarBaseMult(big.NewInt(1))
-	for i := 1; i < t; i++ {
-		am.Mod(am.Mul(am, alpha), bn256.Order)
-		pk.G1P[i].ScalarMult(&pk.G1P[0], am)
-		pk.G2P[i].ScalarMult(&pk.G2P[0], am)
+	pk.Curve = c
+	pk.G1P = make([]curve.Point, t)
+	pk.G2P = make([]curve.Point, t)
+	am := c.NewScalar().SetOne()
+	for i := 0; i < t; i++ {
+		e := am.BigInt()
+		pk.G1P[i] = c.NewG1().ScalarBaseMult(e)
+		pk.G2P[i] = c.NewG2().ScalarBaseMult(e)
+		am.Mul(am, alpha)
 	}
 	return nil
 }
@@ -64,109 +109,202 @@ func (pk *Pk) Degree() int {
 	return len(pk.G1P)
 }
 
+// Check that pk holds the successive powers of a single nonzero alpha
+// over the standard generators, as produced by Setup.
+// The powers are compressed with random coefficients into three pairing checks.
+// A public key of degree 1 only holds the generators, so no ratio is checked.
+func (pk *Pk) CheckPowers() error {
+	t := pk.Degree()
+	if t < 1 || len(pk.G2P) != t || pk.Curve == nil {
+		return errors.New("Public key is malformed")
+	}
+	c := pk.Curve
+	g1 := c.NewG1().ScalarBaseMult(big.NewInt(1))
+	g2 := c.NewG2().ScalarBaseMult(big.NewInt(1))
+	if !pk.G1P[0].Equal(g1) || !pk.G2P[0].Equal(g2) {
+		return errors.New("Public key does not start with the generators")
+	}
+	if t == 1 {
+		return nil
+	}
+	if pk.G1P[1].IsInfinity() {
+		return errors.New("Public key has a zero trapdoor")
+	}
+	rho := curve.NewScalars(c, t-1)
+	for i := range rho {
+		if _, err := rho[i].SetRandom(rand.Reader); err != nil {
+			return err
+		}
+	}
+	// e(g^alpha, g) = e(g, g^alpha)
+	ok := c.PairingCheck([]curve.Point{pk.G1P[1], c.NewG1().Neg(g1)}, []curve.Point{g2, pk.G2P[1]})
+	// e(sum rho_i * G1P[i + 1], g) = e(sum rho_i * G1P[i], g^alpha)
+	a := MultiExpG1(c, pk.G1P[1:], rho)
+	b := MultiExpG1(c, pk.G1P[:t-1], rho)
+	ok = ok && c.PairingCheck([]curve.Point{a, b.Neg(b)}, []curve.Point{g2, pk.G2P[1]})
+	// e(g, sum rho_i * G2P[i + 1]) = e(g^alpha, sum rho_i * G2P[i])
+	d := MultiExpG2(c, pk.G2P[1:], rho)
+	e := MultiExpG2(c, pk.G2P[:t-1], rho)
+	ok = ok && c.PairingCheck([]curve.Point{g1, c.NewG1().Neg(pk.G1P[1])}, []curve.Point{d, e})
+	if !ok {
+		return errors.New("Public key is not a sequence of powers")
+	}
+	return nil
+}
+
+// Check that the point p is in the group built by newPoint, on the curve
+// and in the subgroup of prime order.
+func checkPoint(newPoint func() curve.Point, p curve.Point) error {
+	if p == nil {
+		return errors.New("Point is missing")
+	}
+	return newPoint().Unmarshal(p.Marshal())
+}
+
+// Check that pk is well formed: the powers in G1 and G2 have the same length,
+// every point is on the curve and in the subgroup of prime order,
+// and the powers are consistent as checked by CheckPowers.
+func (pk *Pk) Validate() error {
+	if len(pk.G1P) != len(pk.G2P) {
+		return errors.New("Public key has powers of different lengths")
+	}
+	if pk.Curve == nil {
+		return errors.New("Public key has no curve")
+	}
+	for i := range pk.G1P {
+		if err := checkPoint(pk.Curve.NewG1, pk.G1P[i]); err != nil {
+			return err
+		}
+		if err := checkPoint(pk.Curve.NewG2, pk.G2P[i]); err != nil {
+			return err
+		}
+	}
+	return pk.CheckPowers()
+}
+
 // Generate the commitment of the polynomial poly.
-func (pk *Pk) Commit(poly []big.Int) (*bn256.G2, error) {
+func (pk *Pk) Commit(poly []curve.Scalar) (curve.Point, error) {
 	err := pk.checkPoly(poly)
 	if err != nil {
 		return nil, err
 	}
-	ret := new(bn256.G2)
-	term := new(bn256.G2)
-	for i, _ := range poly {
-		if poly[i].Sign() >= 0 {
-			term.ScalarMult(&pk.G2P[i], &poly[i])
-		} else {
-			term.Neg(term.ScalarMult(&pk.G2P[i], new(big.Int).Neg(&poly[i])))
-		}
-		ret.Add(ret, term)
-	}
-	return ret, nil
+	return MultiExpG2(pk.Curve, pk.G2P, poly), nil
 }
 
 // Verify that the commitment g2 is consistent with the polynomial poly.
-func (pk *Pk) VerifyPoly(poly []big.Int, g2 *bn256.G2) bool {
+func (pk *Pk) VerifyPoly(poly []curve.Scalar, g2 curve.Point) bool {
 	g2c, err := pk.Commit(poly)
 	if err != nil {
 		return false
 	}
-	return bytes.Equal(g2.Marshal(), g2c.Marshal())
+	return g2.Equal(g2c)
 }
 
-// Create a witness g1 to the evaluation of the polynomial poly at i.
-func (pk *Pk) CreateWitness(poly []big.Int, i *big.Int) (res *big.Int, g1 *bn256.G1, err error) {
-	err = pk.checkPoly(poly)
+// Create a witness g1 to the evaluation of the polynomial p at i.
+func (pk *Pk) CreateWitness(p []curve.Scalar, i curve.Scalar) (res curve.Scalar, g1 curve.Point, err error) {
+	err = pk.checkPoly(p)
 	if err != nil {
 		return nil, nil, err
 	}
-	// poly(x) - poly(i) always divides (x - i) since the latter is a root of the former.
-	// With that infomation we can jump into the division.
-	quotient := make([]big.Int, len(poly)-1)
-	if len(quotient) > 0 {
-		// q_(n - 1) = p_n
-		quotient[len(quotient)-1] = poly[len(quotient)]
-		for j := len(quotient) - 2; j >= 0; j-- {
-			// q_j = p_(j + 1) + q_(j + 1) * i
-			quotient[j].Add(&poly[j+1], quotient[j].Mul(&quotient[j+1], i))
-		}
+	quotient, res := poly.DivLinear(p, i)
+	return res, MultiExpG1(pk.Curve, pk.G1P, quotient), nil
+}
+
+// Create a witness g1 to the evaluations of the polynomial p at all points,
+// as described in section 3.4.
+// The remainder rem of p divided by the vanishing polynomial of points
+// is returned, which evaluates to the same value as p at every point.
+func (pk *Pk) CreateBatchWitness(p []curve.Scalar, points []curve.Scalar) (rem []curve.Scalar, g1 curve.Point, err error) {
+	err = pk.checkPoly(p)
+	if err != nil {
+		return nil, nil, err
 	}
-	// Utilize the remainder since we know it divides.
-	res = new(big.Int)
-	res.Add(&poly[0], res.Mul(&quotient[0], i))
-	g1 = new(bn256.G1)
-	term_g1 := new(bn256.G1)
-	for j, _ := range quotient {
-		if quotient[j].Sign() >= 0 {
-			term_g1.ScalarMult(&pk.G1P[j], &quotient[j])
-		} else {
-			term_g1.Neg(term_g1.ScalarMult(&pk.G1P[j], new(big.Int).Neg(&quotient[j])))
-		}
-		g1.Add(g1, term_g1)
+	if len(points) < 1 {
+		return nil, nil, errors.New("Point set is empty")
+	}
+	if pk.Degree() <= len(points) {
+		return nil, nil, errors.New("Public key has a degree less than the vanishing polynomial")
+	}
+	quotient, rem := poly.DivVanishing(pk.Curve, p, points)
+	return rem, MultiExpG1(pk.Curve, pk.G1P, quotient), nil
+}
+
+// Verify the evaluations of the polynomial at all points with the commitment g2,
+// the remainder rem and the witness g1.
+func (pk *Pk) VerifyBatchEval(g2 curve.Point, points []curve.Scalar, rem []curve.Scalar, g1 curve.Point) bool {
+	if len(points) < 1 || pk.Degree() <= len(points) || len(rem) > len(points) {
+		return false
 	}
-	return res, g1, nil
+	c := pk.Curve
+	// e(w, g^z(alpha)) * e(g, g^r(alpha)) * e(-g, C) = 1
+	z := MultiExpG2(c, pk.G2P, poly.Vanishing(c, points))
+	r := MultiExpG2(c, pk.G2P, rem)
+	return c.PairingCheck([]curve.Point{g1, pk.G1P[0], c.NewG1().Neg(pk.G1P[0])}, []curve.Point{z, r, g2})
 }
 
-// Verify the evaluation of the polynomial with the commitment g2 and the witness g1.
-func (pk *Pk) VerifyEval(g2 *bn256.G2, i *big.Int, res *big.Int, g1 *bn256.G1) bool {
-	g_i := new(bn256.G2)
-	g_i.ScalarBaseMult(i)
-	p := new(bn256.G2)
-	p.Add(&pk.G2P[1], p.Neg(g_i))
-	rhs := bn256.Pair(&pk.G1P[0], &pk.G2P[0])
-	rhs.Add(bn256.Pair(g1, p), rhs.ScalarMult(rhs, res))
-	lhs := bn256.Pair(&pk.G1P[0], g2)
-	return bytes.Equal(lhs.Marshal(), rhs.Marshal())
+// Evaluate the polynomial p at i.
+func Evaluate(p []curve.Scalar, i curve.Scalar) curve.Scalar {
+	return poly.Polynomial(p).Evaluate(i)
 }
 
 // Serialize the specified public key
 func (pk *Pk) Marshal() ([]byte, error) {
+	return pk.MarshalFormat(point.Uncompressed)
+}
+
+// Serialize the specified public key with the points encoded in the format f.
+func (pk *Pk) MarshalFormat(f point.Format) ([]byte, error) {
 	var sPk pb.Pk
-	sPk.G1P = make([][]byte, len(pk.G1P))
-	sPk.G2P = make([][]byte, len(pk.G2P))
-	for i, _ := range pk.G1P {
-		sPk.G1P[i] = pk.G1P[i].Marshal()
-		sPk.G2P[i] = pk.G2P[i].Marshal()
-	}
+	sPk.G1P = encodePoints(pk.G1P, f)
+	sPk.G2P = encodePoints(pk.G2P, f)
+	sPk.Format = uint32(f)
+	sPk.Curve = pk.Curve.Name()
 	return proto.Marshal(&sPk)
 }
 
+// Struct UnmarshalOptions implements the options of UnmarshalWith.
+// MaxDegree rejects public keys of a larger degree before any point is
+// decoded, unless it is 0. Validate runs Validate on the public key.
+type UnmarshalOptions struct {
+	MaxDegree int
+	Validate  bool
+}
+
 // Deserialize the specified public key
 func (pk *Pk) Unmarshal(b []byte) error {
+	return pk.UnmarshalWith(b, UnmarshalOptions{})
+}
+
+// Deserialize the specified public key with the options opts.
+func (pk *Pk) UnmarshalWith(b []byte, opts UnmarshalOptions) error {
 	var sPk pb.Pk
 	err := proto.Unmarshal(b, &sPk)
 	if err != nil {
 		return err
 	}
-	if pk == nil {
-		pk = new(Pk)
+	t := len(sPk.G1P)
+	if len(sPk.G2P) != t {
+		return errors.New("Public key has powers of different lengths")
 	}
-	pk.G1P = make([]bn256.G1, len(sPk.G1P))
-	pk.G2P = make([]bn256.G2, len(sPk.G2P))
-	for i, _ := range sPk.G1P {
-		_, err = pk.G1P[i].Unmarshal(sPk.G1P[i])
-		_, err = pk.G2P[i].Unmarshal(sPk.G2P[i])
-		if err != nil {
-			return err
-		}
+	if opts.MaxDegree > 0 && t > opts.MaxDegree {
+		return errors.New("Public key has a degree larger than the maximum")
+	}
+	c, err := curve.ByName(sPk.Curve)
+	if err != nil {
+		return err
+	}
+	f := point.Format(sPk.Format)
+	g1P, err := decodePoints(c.NewG1, sPk.G1P, f)
+	if err != nil {
+		return err
+	}
+	g2P, err := decodePoints(c.NewG2, sPk.G2P, f)
+	if err != nil {
+		return err
+	}
+	pk.Curve, pk.G1P, pk.G2P = c, g1P, g2P
+	if opts.Validate {
+		return pk.Validate()
 	}
 	return nil
 }
@@ -5,7 +5,11 @@ import (
 
 	"bytes"
 	"crypto/rand"
-	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"fmt"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/point"
+	pb "github.com/zhtluo/libpolycrypto/proto"
+	"google.golang.org/protobuf/proto"
 	"io"
 	"math/big"
 )
@@ -14,144 +18,603 @@ const (
 	deg = 256
 )
 
-func generatePoly(r io.Reader) []big.Int {
-	poly := make([]big.Int, deg)
-	for i, _ := range poly {
-		var p *big.Int
-		p, _ = rand.Int(r, bn256.Order)
-		poly[i] = *p
+var curves = []curve.Curve{curve.BN256, curve.BN256Google, curve.BLS12381}
+
+func generatePoly(c curve.Curve, r io.Reader) []curve.Scalar {
+	poly := curve.NewScalars(c, deg)
+	for i := range poly {
+		poly[i].SetRandom(r)
 	}
 	return poly
 }
 
-func TestCommit(t *testing.T) {
-	var pk Pk
-	pk.Setup(rand.Reader, deg)
-	poly := generatePoly(rand.Reader)
-	g2, err := pk.Commit(poly)
-	if err != nil {
-		t.Error(err.Error())
-	}
-	flag := pk.VerifyPoly(poly, g2)
-	if flag != true {
-		t.Error("VerifyPoly failed, expected: true.")
-	}
-	poly = generatePoly(rand.Reader)
-	flag = pk.VerifyPoly(poly, g2)
-	if flag != false {
-		t.Error("VerifyPoly failed, expected: false.")
-	}
+func randomElement(c curve.Curve, r io.Reader) curve.Scalar {
+	e, _ := c.NewScalar().SetRandom(r)
+	return e
 }
 
-func TestWitness(t *testing.T) {
-	var pk Pk
-	pk.Setup(rand.Reader, deg)
-	// x^3 - 2x^2 + 7x - 5
-	poly := []big.Int{*big.NewInt(-5), *big.NewInt(7), *big.NewInt(-2), *big.NewInt(1)}
-	g2, err := pk.Commit(poly)
-	if err != nil {
-		t.Error(err.Error())
+// Compute the sum of scalars[i] * points[i] one scalar at a time.
+func naiveMultiExp(newPoint func() curve.Point, points []curve.Point, scalars []curve.Scalar) curve.Point {
+	ret := newPoint().SetInfinity()
+	term := newPoint()
+	for i := range scalars {
+		ret.Add(ret, term.ScalarMult(points[i], scalars[i].BigInt()))
 	}
-	i := big.NewInt(3)
-	res, g1, err := pk.CreateWitness(poly, i)
-	if err != nil {
-		t.Error(err.Error())
+	return ret
+}
+
+func TestMultiExp(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			for _, n := range []int{0, 1, 2, 31, 32, 100, deg} {
+				scalars := generatePoly(c, rand.Reader)[:n]
+				// Cover zero and maximal scalars.
+				if n > 2 {
+					scalars[1].SetZero()
+					scalars[2].SetInt64(-1)
+				}
+				if !MultiExpG1(c, pk.G1P, scalars).Equal(naiveMultiExp(c.NewG1, pk.G1P, scalars)) {
+					t.Errorf("MultiExpG1 failed with %d points.", n)
+				}
+				if !MultiExpG2(c, pk.G2P, scalars).Equal(naiveMultiExp(c.NewG2, pk.G2P, scalars)) {
+					t.Errorf("MultiExpG2 failed with %d points.", n)
+				}
+			}
+			// Every scalar equal to -1 triggers a carry into the extra window.
+			scalars := curve.NewScalars(c, deg)
+			for i := range scalars {
+				scalars[i].SetInt64(-1)
+			}
+			if !MultiExpG1(c, pk.G1P, scalars).Equal(naiveMultiExp(c.NewG1, pk.G1P, scalars)) {
+				t.Error("MultiExpG1 failed with scalars -1.")
+			}
+		})
 	}
-	// 3^3 - 2*3^2 + 7*3 - 5 = 25
-	if res.Cmp(big.NewInt(25)) != 0 {
-		t.Error("CreateWitness failed. Wrong evaluation result.")
+}
+
+func TestCheckPowers(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			if err := pk.CheckPowers(); err != nil {
+				t.Error(err)
+			}
+			pk.G2P[deg-1] = pk.G2P[deg-2]
+			if pk.CheckPowers() == nil {
+				t.Error("CheckPowers accepted inconsistent powers.")
+			}
+			pk.G2P = pk.G2P[:deg-1]
+			if pk.CheckPowers() == nil {
+				t.Error("CheckPowers accepted powers of different lengths.")
+			}
+		})
 	}
-	if pk.VerifyEval(g2, i, res, g1) != true {
-		t.Error("VerifyEval failed, expected: true.")
+}
+
+func TestValidate(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			if err := pk.Validate(); err != nil {
+				t.Error(err)
+			}
+			b, _ := pk.Marshal()
+			var rPk Pk
+			if err := rPk.UnmarshalWith(b, UnmarshalOptions{MaxDegree: deg, Validate: true}); err != nil {
+				t.Error(err)
+			}
+			if rPk.Curve != c {
+				t.Error("Unmarshal failed. Wrong curve.")
+			}
+			if rPk.UnmarshalWith(b, UnmarshalOptions{MaxDegree: deg - 1}) == nil {
+				t.Error("UnmarshalWith accepted a degree above the maximum.")
+			}
+			// Corrupt a single point in G1 and drop a power in G2.
+			var sPk pb.Pk
+			proto.Unmarshal(b, &sPk)
+			sPk.G1P[3] = make([]byte, len(sPk.G1P[3]))
+			sPk.G1P[3][len(sPk.G1P[3])-1] = 1
+			b, _ = proto.Marshal(&sPk)
+			if rPk.Unmarshal(b) == nil {
+				t.Error("Unmarshal accepted a point off the curve.")
+			}
+			sPk.G1P[3] = pk.G1P[3].Marshal()
+			sPk.G2P = sPk.G2P[:deg-1]
+			b, _ = proto.Marshal(&sPk)
+			if rPk.Unmarshal(b) == nil {
+				t.Error("Unmarshal accepted powers of different lengths.")
+			}
+			// Swapping two powers keeps every point valid but breaks the sequence.
+			pk.G1P[2], pk.G1P[3] = pk.G1P[3], pk.G1P[2]
+			b, _ = pk.Marshal()
+			if err := rPk.Unmarshal(b); err != nil {
+				t.Error(err)
+			}
+			if rPk.UnmarshalWith(b, UnmarshalOptions{Validate: true}) == nil {
+				t.Error("UnmarshalWith accepted inconsistent powers.")
+			}
+			// A public key of degree 1 only holds the generators.
+			var onePk Pk
+			onePk.Setup(c, rand.Reader, 1)
+			b, _ = onePk.Marshal()
+			if err := rPk.UnmarshalWith(b, UnmarshalOptions{Validate: true}); err != nil {
+				t.Error(err)
+			}
+			onePk.G1P[0] = c.NewG1().Neg(onePk.G1P[0])
+			if onePk.Validate() == nil {
+				t.Error("Validate accepted a public key of degree 1 without the generators.")
+			}
+		})
 	}
-	if pk.VerifyEval(g2, i, big.NewInt(24), g1) != false {
-		t.Error("VerifyEval failed, expected: false.")
+}
+
+func TestCommit(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			poly := generatePoly(c, rand.Reader)
+			g2, err := pk.Commit(poly)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			flag := pk.VerifyPoly(poly, g2)
+			if flag != true {
+				t.Error("VerifyPoly failed, expected: true.")
+			}
+			poly = generatePoly(c, rand.Reader)
+			flag = pk.VerifyPoly(poly, g2)
+			if flag != false {
+				t.Error("VerifyPoly failed, expected: false.")
+			}
+			if _, err := pk.Commit(curve.NewScalars(c, deg+1)); err == nil {
+				t.Error("Commit accepted a polynomial beyond the degree.")
+			}
+		})
 	}
-	// random polynomial
-	poly = generatePoly(rand.Reader)
-	g2, err = pk.Commit(poly)
-	if err != nil {
-		t.Error(err.Error())
+}
+
+func TestWitness(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			vk, _ := pk.VerifierKey()
+			// x^3 - 2x^2 + 7x - 5
+			poly := []curve.Scalar{curve.NewScalar(c, -5), curve.NewScalar(c, 7), curve.NewScalar(c, -2), curve.NewScalar(c, 1)}
+			g2, err := pk.Commit(poly)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			i := curve.NewScalar(c, 3)
+			res, g1, err := pk.CreateWitness(poly, i)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			// 3^3 - 2*3^2 + 7*3 - 5 = 25
+			if !res.Equal(curve.NewScalar(c, 25)) {
+				t.Error("CreateWitness failed. Wrong evaluation result.")
+			}
+			if vk.VerifyEval(g2, i, res, g1) != true {
+				t.Error("VerifyEval failed, expected: true.")
+			}
+			if vk.VerifyEval(g2, i, curve.NewScalar(c, 24), g1) != false {
+				t.Error("VerifyEval failed, expected: false.")
+			}
+			// random polynomial
+			poly = generatePoly(c, rand.Reader)
+			g2, err = pk.Commit(poly)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			i.SetRandom(rand.Reader)
+			res, g1, err = pk.CreateWitness(poly, i)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if vk.VerifyEval(g2, i, res, g1) != true {
+				t.Error("VerifyEval failed, expected: true.")
+			}
+			res.SetRandom(rand.Reader)
+			if vk.VerifyEval(g2, i, res, g1) != false {
+				t.Error("VerifyEval failed, expected: false.")
+			}
+		})
 	}
-	i, _ = rand.Int(rand.Reader, bn256.Order)
-	res, g1, err = pk.CreateWitness(poly, i)
-	if err != nil {
-		t.Error(err.Error())
+}
+
+func TestBatchWitness(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			// x^3 - 2x^2 + 7x - 5
+			poly := []curve.Scalar{curve.NewScalar(c, -5), curve.NewScalar(c, 7), curve.NewScalar(c, -2), curve.NewScalar(c, 1)}
+			g2, err := pk.Commit(poly)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			points := []curve.Scalar{curve.NewScalar(c, 1), curve.NewScalar(c, 2), curve.NewScalar(c, 3)}
+			rem, g1, err := pk.CreateBatchWitness(poly, points)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			// 1, 9, 25
+			expected := []int64{1, 9, 25}
+			for j := range points {
+				if !Evaluate(rem, points[j]).Equal(curve.NewScalar(c, expected[j])) {
+					t.Error("CreateBatchWitness failed. Wrong evaluation result.")
+				}
+			}
+			if pk.VerifyBatchEval(g2, points, rem, g1) != true {
+				t.Error("VerifyBatchEval failed, expected: true.")
+			}
+			rem[0].Add(rem[0], curve.NewScalar(c, 1))
+			if pk.VerifyBatchEval(g2, points, rem, g1) != false {
+				t.Error("VerifyBatchEval failed, expected: false.")
+			}
+			// random polynomial
+			poly = generatePoly(c, rand.Reader)
+			g2, err = pk.Commit(poly)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			points = generatePoly(c, rand.Reader)[:16]
+			rem, g1, err = pk.CreateBatchWitness(poly, points)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			for j := range points {
+				if !Evaluate(rem, points[j]).Equal(Evaluate(poly, points[j])) {
+					t.Error("CreateBatchWitness failed. Wrong evaluation result.")
+				}
+			}
+			if pk.VerifyBatchEval(g2, points, rem, g1) != true {
+				t.Error("VerifyBatchEval failed, expected: true.")
+			}
+			points[0].SetRandom(rand.Reader)
+			if pk.VerifyBatchEval(g2, points, rem, g1) != false {
+				t.Error("VerifyBatchEval failed, expected: false.")
+			}
+		})
 	}
-	if pk.VerifyEval(g2, i, res, g1) != true {
-		t.Error("VerifyEval failed, expected: true.")
+}
+
+func TestBig(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			vk, _ := pk.VerifierKey()
+			poly := curve.ToBigInts(generatePoly(c, rand.Reader))
+			// Negative and unreduced coefficients are taken modulo the order.
+			poly[0].Neg(&poly[0])
+			poly[1].Add(&poly[1], c.Order())
+			g2, err := pk.CommitBig(poly)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			if pk.VerifyPoly(curve.FromBigInts(c, poly), g2) != true {
+				t.Error("VerifyPoly failed, expected: true.")
+			}
+			i := new(big.Int).Add(randomElement(c, rand.Reader).BigInt(), c.Order())
+			res, g1, err := pk.CreateWitnessBig(poly, i)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			// Check res against a direct evaluation of poly at i.
+			v, m := new(big.Int), big.NewInt(1)
+			for j := range poly {
+				v.Add(v, new(big.Int).Mul(&poly[j], m))
+				m.Mod(m.Mul(m, i), c.Order())
+			}
+			if res.Cmp(v.Mod(v, c.Order())) != 0 || res.Cmp(EvaluateBig(c, poly, i)) != 0 {
+				t.Error("CreateWitnessBig failed. Wrong evaluation result.")
+			}
+			if vk.VerifyEvalBig(g2, i, res, g1) != true {
+				t.Error("VerifyEvalBig failed, expected: true.")
+			}
+			if vk.VerifyEvalBig(g2, i, res.Add(res, big.NewInt(1)), g1) != false {
+				t.Error("VerifyEvalBig failed, expected: false.")
+			}
+		})
 	}
-	res, _ = rand.Int(rand.Reader, bn256.Order)
-	if pk.VerifyEval(g2, i, res, g1) != false {
-		t.Error("VerifyEval failed, expected: false.")
+}
+
+func TestTrim(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			tpk, err := pk.Trim(deg / 4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tpk.Degree() != deg/4 || len(tpk.G2P) != deg/4 {
+				t.Error("Trim failed. Wrong degree.")
+			}
+			poly := generatePoly(c, rand.Reader)[:deg/4]
+			g2, _ := pk.Commit(poly)
+			tg2, err := tpk.Commit(poly)
+			if err != nil {
+				t.Error(err)
+			}
+			if !g2.Equal(tg2) {
+				t.Error("Trim failed. Trimmed commitment differs.")
+			}
+			if _, err = tpk.Commit(generatePoly(c, rand.Reader)); err == nil {
+				t.Error("Commit accepted a polynomial beyond the trimmed degree.")
+			}
+			if _, err = pk.Trim(deg + 1); err == nil {
+				t.Error("Trim accepted a degree beyond the public key.")
+			}
+			// The verifier key of the trimmed public key verifies witnesses of the full one.
+			vk, err := tpk.VerifierKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			i := randomElement(c, rand.Reader)
+			res, g1, _ := pk.CreateWitness(poly, i)
+			if vk.VerifyEval(g2, i, res, g1) != true {
+				t.Error("VerifyEval failed, expected: true.")
+			}
+			tpk, _ = pk.Trim(1)
+			if _, err = tpk.VerifierKey(); err == nil {
+				t.Error("VerifierKey accepted a public key of degree 1.")
+			}
+		})
 	}
 }
 
 func TestMarshal(t *testing.T) {
-	var pk, rPk Pk
-	pk.Setup(rand.Reader, deg)
-	b, err := pk.Marshal()
-	if err != nil {
-		t.Error(err)
-	}
-	err = rPk.Unmarshal(b)
-	if err != nil {
-		t.Error(err)
-	}
-	if len(pk.G1P) != len(rPk.G1P) || len(pk.G2P) != len(rPk.G2P) {
-		t.Error("Marshal does not generate equal result.")
-	}
-	for i, _ := range pk.G1P {
-		if !bytes.Equal(pk.G1P[i].Marshal(), rPk.G1P[i].Marshal()) {
-			t.Error("Marshal does not generate equal result.")
-		}
-		if !bytes.Equal(pk.G2P[i].Marshal(), rPk.G2P[i].Marshal()) {
-			t.Error("Marshal does not generate equal result.")
-		}
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk, rPk Pk
+			pk.Setup(c, rand.Reader, deg)
+			b, err := pk.Marshal()
+			if err != nil {
+				t.Error(err)
+			}
+			err = rPk.Unmarshal(b)
+			if err != nil {
+				t.Error(err)
+			}
+			if rPk.Curve != c || len(pk.G1P) != len(rPk.G1P) || len(pk.G2P) != len(rPk.G2P) {
+				t.Fatal("Marshal does not generate equal result.")
+			}
+			for i := range pk.G1P {
+				if !pk.G1P[i].Equal(rPk.G1P[i]) {
+					t.Error("Marshal does not generate equal result.")
+				}
+				if !pk.G2P[i].Equal(rPk.G2P[i]) {
+					t.Error("Marshal does not generate equal result.")
+				}
+			}
+			vk, _ := pk.VerifierKey()
+			b, err = vk.Marshal()
+			if err != nil {
+				t.Error(err)
+			}
+			var rVk VerifierKey
+			err = rVk.Unmarshal(b)
+			if err != nil {
+				t.Error(err)
+			}
+			if rVk.Curve != c || !vk.G1.Equal(rVk.G1) || !vk.G2.Equal(rVk.G2) || !vk.G2Alpha.Equal(rVk.G2Alpha) {
+				t.Error("Marshal does not generate equal result.")
+			}
+		})
+	}
+}
+
+func TestMarshalCompressed(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk, rPk Pk
+			pk.Setup(c, rand.Reader, deg)
+			b, err := pk.MarshalFormat(point.Compressed)
+			if err != nil {
+				t.Error(err)
+			}
+			u, _ := pk.Marshal()
+			// Points take half the space, up to the framing of the message.
+			if 3*len(b) > 2*len(u) {
+				t.Error("MarshalFormat failed. Compressed key is too large.")
+			}
+			if err = rPk.Unmarshal(b); err != nil {
+				t.Error(err)
+			}
+			if m, _ := rPk.Marshal(); !bytes.Equal(m, u) {
+				t.Error("Marshal does not generate equal result.")
+			}
+			var pedPk, rPedPk PedPk
+			pedPk.Setup(c, rand.Reader, deg)
+			b, _ = pedPk.MarshalFormat(point.Compressed)
+			if err = rPedPk.Unmarshal(b); err != nil {
+				t.Error(err)
+			}
+			u, _ = pedPk.Marshal()
+			if m, _ := rPedPk.Marshal(); !bytes.Equal(m, u) {
+				t.Error("Marshal does not generate equal result.")
+			}
+			var g1Pk, rG1Pk G1Pk
+			g1Pk.Setup(c, rand.Reader, deg)
+			b, _ = g1Pk.MarshalFormat(point.Compressed)
+			if err = rG1Pk.Unmarshal(b); err != nil {
+				t.Error(err)
+			}
+			u, _ = g1Pk.Marshal()
+			if m, _ := rG1Pk.Marshal(); !bytes.Equal(m, u) {
+				t.Error("Marshal does not generate equal result.")
+			}
+			vk, _ := pk.VerifierKey()
+			b, _ = vk.MarshalFormat(point.Compressed)
+			var rVk VerifierKey
+			if err = rVk.Unmarshal(b); err != nil {
+				t.Error(err)
+			}
+			u, _ = vk.Marshal()
+			if m, _ := rVk.Marshal(); !bytes.Equal(m, u) {
+				t.Error("Marshal does not generate equal result.")
+			}
+			pedVk, _ := pedPk.VerifierKey()
+			b, _ = pedVk.MarshalFormat(point.Compressed)
+			var rPedVk PedVerifierKey
+			if err = rPedVk.Unmarshal(b); err != nil {
+				t.Error(err)
+			}
+			u, _ = pedVk.Marshal()
+			if m, _ := rPedVk.Marshal(); !bytes.Equal(m, u) {
+				t.Error("Marshal does not generate equal result.")
+			}
+			dk, _ := pk.DegreeKey(deg / 2)
+			b, _ = dk.MarshalFormat(point.Compressed)
+			var rDk DegreeKey
+			if err = rDk.Unmarshal(b); err != nil {
+				t.Error(err)
+			}
+			u, _ = dk.Marshal()
+			if m, _ := rDk.Marshal(); !bytes.Equal(m, u) {
+				t.Error("Marshal does not generate equal result.")
+			}
+		})
 	}
 }
 
 func BenchmarkCommit(b *testing.B) {
-	var pk Pk
-	pk.Setup(rand.Reader, deg)
-	poly := generatePoly(rand.Reader)
-	b.ResetTimer()
-	for t := 0; t < b.N; t++ {
-		pk.Commit(poly)
+	for _, c := range curves {
+		var pk Pk
+		pk.Setup(c, rand.Reader, deg)
+		poly := generatePoly(c, rand.Reader)
+		b.Run(c.Name(), func(b *testing.B) {
+			for t := 0; t < b.N; t++ {
+				pk.Commit(poly)
+			}
+		})
 	}
 }
 
 func BenchmarkVerifyPoly(b *testing.B) {
-	var pk Pk
-	pk.Setup(rand.Reader, deg)
-	poly := generatePoly(rand.Reader)
-	g2, _ := pk.Commit(poly)
-	b.ResetTimer()
-	for t := 0; t < b.N; t++ {
-		pk.VerifyPoly(poly, g2)
+	for _, c := range curves {
+		var pk Pk
+		pk.Setup(c, rand.Reader, deg)
+		poly := generatePoly(c, rand.Reader)
+		g2, _ := pk.Commit(poly)
+		b.Run(c.Name(), func(b *testing.B) {
+			for t := 0; t < b.N; t++ {
+				pk.VerifyPoly(poly, g2)
+			}
+		})
 	}
 }
 
 func BenchmarkCreateWitness(b *testing.B) {
-	var pk Pk
-	pk.Setup(rand.Reader, deg)
-	poly := generatePoly(rand.Reader)
-	i, _ := rand.Int(rand.Reader, bn256.Order)
-	b.ResetTimer()
-	for t := 0; t < b.N; t++ {
-		pk.CreateWitness(poly, i)
+	for _, c := range curves {
+		var pk Pk
+		pk.Setup(c, rand.Reader, deg)
+		poly := generatePoly(c, rand.Reader)
+		i := randomElement(c, rand.Reader)
+		b.Run(c.Name(), func(b *testing.B) {
+			for t := 0; t < b.N; t++ {
+				pk.CreateWitness(poly, i)
+			}
+		})
 	}
 }
 
 func BenchmarkVerifyEval(b *testing.B) {
-	var pk Pk
-	pk.Setup(rand.Reader, deg)
-	poly := generatePoly(rand.Reader)
-	g2, _ := pk.Commit(poly)
-	i, _ := rand.Int(rand.Reader, bn256.Order)
-	res, g1, _ := pk.CreateWitness(poly, i)
-	b.ResetTimer()
-	for t := 0; t < b.N; t++ {
-		pk.VerifyEval(g2, i, res, g1)
+	for _, c := range curves {
+		var pk Pk
+		pk.Setup(c, rand.Reader, deg)
+		vk, _ := pk.VerifierKey()
+		poly := generatePoly(c, rand.Reader)
+		g2, _ := pk.Commit(poly)
+		i := randomElement(c, rand.Reader)
+		res, g1, _ := pk.CreateWitness(poly, i)
+		b.Run(c.Name(), func(b *testing.B) {
+			for t := 0; t < b.N; t++ {
+				vk.VerifyEval(g2, i, res, g1)
+			}
+		})
+	}
+}
+
+func BenchmarkCreateBatchWitness(b *testing.B) {
+	for _, c := range curves {
+		var pk Pk
+		pk.Setup(c, rand.Reader, deg)
+		poly := generatePoly(c, rand.Reader)
+		points := generatePoly(c, rand.Reader)[:16]
+		b.Run(c.Name(), func(b *testing.B) {
+			for t := 0; t < b.N; t++ {
+				pk.CreateBatchWitness(poly, points)
+			}
+		})
+	}
+}
+
+func BenchmarkVerifyBatchEval(b *testing.B) {
+	for _, c := range curves {
+		var pk Pk
+		pk.Setup(c, rand.Reader, deg)
+		poly := generatePoly(c, rand.Reader)
+		g2, _ := pk.Commit(poly)
+		points := generatePoly(c, rand.Reader)[:16]
+		rem, g1, _ := pk.CreateBatchWitness(poly, points)
+		b.Run(c.Name(), func(b *testing.B) {
+			for t := 0; t < b.N; t++ {
+				pk.VerifyBatchEval(g2, points, rem, g1)
+			}
+		})
+	}
+}
+
+func BenchmarkMultiExpG1(b *testing.B) {
+	for _, c := range curves {
+		for _, n := range []int{16, 256, 1024} {
+			var pk Pk
+			pk.Setup(c, rand.Reader, n)
+			scalars := curve.NewScalars(c, n)
+			for i := range scalars {
+				scalars[i].SetRandom(rand.Reader)
+			}
+			b.Run(fmt.Sprintf("%s/naive/%d", c.Name(), n), func(b *testing.B) {
+				for t := 0; t < b.N; t++ {
+					naiveMultiExp(c.NewG1, pk.G1P, scalars)
+				}
+			})
+			b.Run(fmt.Sprintf("%s/pippenger/%d", c.Name(), n), func(b *testing.B) {
+				for t := 0; t < b.N; t++ {
+					MultiExpG1(c, pk.G1P, scalars)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkMultiExpG2(b *testing.B) {
+	for _, c := range curves {
+		for _, n := range []int{16, 256, 1024} {
+			var pk Pk
+			pk.Setup(c, rand.Reader, n)
+			scalars := curve.NewScalars(c, n)
+			for i := range scalars {
+				scalars[i].SetRandom(rand.Reader)
+			}
+			b.Run(fmt.Sprintf("%s/naive/%d", c.Name(), n), func(b *testing.B) {
+				for t := 0; t < b.N; t++ {
+					naiveMultiExp(c.NewG2, pk.G2P, scalars)
+				}
+			})
+			b.Run(fmt.Sprintf("%s/pippenger/%d", c.Name(), n), func(b *testing.B) {
+				for t := 0; t < b.N; t++ {
+					MultiExpG2(c, pk.G2P, scalars)
+				}
+			})
+		}
 	}
 }
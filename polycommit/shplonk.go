@@ -0,0 +1,256 @@
+package polycommit
+
+// This file implements the opening of many polynomials, each at its own set
+// of points, with a constant-size proof,
+// D. Boneh, J. Drake, B. Fisch, A. Gabizon.
+// Efficient polynomial commitment schemes for multiple points and polynomials.
+//
+// Let T be the union of the point sets S_i, r_i the polynomial interpolating
+// P_i over S_i and gamma, z the Fiat-Shamir challenges. The prover commits to
+// h = sum_i gamma^i * (P_i - r_i) / Z_(S_i) as W and opens
+// L = sum_i gamma^i * Z_(T \ S_i)(z) * (P_i - r_i(z)) - Z_T(z) * h at z,
+// which vanishes there, with the witness W'.
+
+import (
+	"errors"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/poly"
+	"github.com/zhtluo/libpolycrypto/transcript"
+)
+
+// Struct MultiProof implements the proof of the openings of many polynomials
+// committed in G2, each at its own set of points.
+type MultiProof struct {
+	W      curve.Point
+	WPrime curve.Point
+}
+
+// Struct G1MultiProof implements MultiProof for commitments in G1.
+type G1MultiProof struct {
+	W      curve.Point
+	WPrime curve.Point
+}
+
+// Return the key of the scalar z in the maps of point sets.
+func scalarKey(z curve.Scalar) string {
+	return string(z.Marshal())
+}
+
+// Check that there is a nonempty set of distinct points for every one of the
+// n polynomials, and every point has an evaluation if res is not nil.
+func checkPointSets(n int, points [][]curve.Scalar, res [][]curve.Scalar) error {
+	if n == 0 || len(points) != n {
+		return errors.New("Number of point sets does not match the polynomials")
+	}
+	if res != nil && len(res) != n {
+		return errors.New("Number of evaluations does not match the polynomials")
+	}
+	for i := range points {
+		if len(points[i]) < 1 {
+			return errors.New("Point set is empty")
+		}
+		if res != nil && len(res[i]) != len(points[i]) {
+			return errors.New("Number of evaluations does not match the points")
+		}
+		seen := make(map[string]bool, len(points[i]))
+		for j := range points[i] {
+			if seen[scalarKey(points[i][j])] {
+				return errors.New("Point set has duplicate points")
+			}
+			seen[scalarKey(points[i][j])] = true
+		}
+	}
+	return nil
+}
+
+// Start the transcript with the serialized commitments, the point sets and
+// the evaluations, and derive the challenge gamma of the curve c.
+func multiChallenge(c curve.Curve, commits [][]byte, points [][]curve.Scalar, res [][]curve.Scalar) (*transcript.Transcript, curve.Scalar) {
+	t := transcript.New("polycommit-shplonk")
+	t.AppendUint64("n", uint64(len(commits)))
+	for i := range commits {
+		t.AppendMessage("commit", commits[i])
+		t.AppendUint64("points", uint64(len(points[i])))
+		for j := range points[i] {
+			t.AppendScalar("point", points[i][j])
+			t.AppendScalar("eval", res[i][j])
+		}
+	}
+	return t, challengeScalar(c, t, "gamma")
+}
+
+// Derive the challenge z of the curve c after appending the serialized
+// commitment w to h.
+func multiPointChallenge(c curve.Curve, t *transcript.Transcript, w []byte) curve.Scalar {
+	t.AppendMessage("w", w)
+	return challengeScalar(c, t, "z")
+}
+
+// Evaluate the polynomial interpolating res over points at z with the
+// Lagrange basis.
+func evaluateInterpolation(points []curve.Scalar, res []curve.Scalar, z curve.Scalar) curve.Scalar {
+	lambda := poly.LagrangeCoefficients(points, z)
+	ret := z.Curve().NewScalar()
+	term := z.Curve().NewScalar()
+	for j := range points {
+		ret.Add(ret, term.Mul(res[j], lambda[j]))
+	}
+	return ret
+}
+
+// Compute the scalars of the linearization at z: coef_i = gamma^i * Z_(T \ S_i)(z),
+// rz = sum_i coef_i * r_i(z) and zt = Z_T(z).
+func linearize(points [][]curve.Scalar, res [][]curve.Scalar, gamma curve.Scalar, z curve.Scalar) (coef []curve.Scalar, rz curve.Scalar, zt curve.Scalar) {
+	c := z.Curve()
+	// Collect T in the order of first appearance.
+	union := make([]curve.Scalar, 0)
+	seen := make(map[string]bool)
+	for i := range points {
+		for j := range points[i] {
+			if !seen[scalarKey(points[i][j])] {
+				seen[scalarKey(points[i][j])] = true
+				union = append(union, points[i][j])
+			}
+		}
+	}
+	term := c.NewScalar()
+	zt = c.NewScalar().SetOne()
+	for k := range union {
+		zt.Mul(zt, term.Sub(z, union[k]))
+	}
+	coef = curve.NewScalars(c, len(points))
+	rz = c.NewScalar()
+	g := c.NewScalar().SetOne()
+	for i := range points {
+		in := make(map[string]bool, len(points[i]))
+		for j := range points[i] {
+			in[scalarKey(points[i][j])] = true
+		}
+		coef[i].Set(g)
+		for k := range union {
+			if !in[scalarKey(union[k])] {
+				coef[i].Mul(coef[i], term.Sub(z, union[k]))
+			}
+		}
+		rz.Add(rz, term.Mul(coef[i], evaluateInterpolation(points[i], res[i], z)))
+		g.Mul(g, gamma)
+	}
+	return coef, rz, zt
+}
+
+// Evaluate every polynomial at its points, derive gamma and compute h
+// over the curve c.
+func multiFirstRound(c curve.Curve, polys [][]curve.Scalar, commits [][]byte, points [][]curve.Scalar) (res [][]curve.Scalar, t *transcript.Transcript, gamma curve.Scalar, h []curve.Scalar) {
+	res = make([][]curve.Scalar, len(polys))
+	for i := range polys {
+		res[i] = poly.Polynomial(polys[i]).EvaluateMulti(points[i])
+	}
+	t, gamma = multiChallenge(c, commits, points, res)
+	// (P_i - r_i) / Z_(S_i) is the quotient of P_i divided by Z_(S_i).
+	h = curve.NewScalars(c, 1)
+	g := c.NewScalar().SetOne()
+	for i := range polys {
+		quotient, _ := poly.DivVanishing(c, polys[i], points[i])
+		h = poly.Add(h, poly.Scale(quotient, g))
+		g.Mul(g, gamma)
+	}
+	return res, t, gamma, h
+}
+
+// Compute the quotient of L divided by (x - z).
+func multiSecondRound(polys [][]curve.Scalar, points [][]curve.Scalar, res [][]curve.Scalar, gamma curve.Scalar, h []curve.Scalar, z curve.Scalar) []curve.Scalar {
+	coef, rz, zt := linearize(points, res, gamma, z)
+	l := poly.Scale(h, z.Curve().NewScalar().Neg(zt))
+	for i := range polys {
+		l = poly.Add(l, poly.Scale(polys[i], coef[i]))
+	}
+	l[0].Sub(l[0], rz)
+	quotient, _ := poly.DivLinear(l, z)
+	return quotient
+}
+
+// Create a proof of the evaluations res of the polynomials polys with the
+// commitments commits, where res[i][j] is the evaluation of polys[i] at points[i][j].
+func (pk *Pk) CreateMultiProof(polys [][]curve.Scalar, commits []curve.Point, points [][]curve.Scalar) (res [][]curve.Scalar, proof *MultiProof, err error) {
+	if len(commits) != len(polys) {
+		return nil, nil, errors.New("Number of polynomials does not match the commitments")
+	}
+	if err = checkPointSets(len(polys), points, nil); err != nil {
+		return nil, nil, err
+	}
+	for i := range polys {
+		if err = pk.checkPoly(polys[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	c := pk.Curve
+	res, t, gamma, h := multiFirstRound(c, polys, marshalPoints(commits), points)
+	proof = new(MultiProof)
+	proof.W = MultiExpG2(c, pk.G2P, h)
+	z := multiPointChallenge(c, t, proof.W.Marshal())
+	proof.WPrime = MultiExpG1(c, pk.G1P, multiSecondRound(polys, points, res, gamma, h, z))
+	return res, proof, nil
+}
+
+// Verify the evaluations res at points of the polynomials with the
+// commitments commits against the proof.
+func (vk *VerifierKey) VerifyMultiProof(commits []curve.Point, points [][]curve.Scalar, res [][]curve.Scalar, proof *MultiProof) bool {
+	if checkPointSets(len(commits), points, res) != nil {
+		return false
+	}
+	c := vk.Curve
+	t, gamma := multiChallenge(c, marshalPoints(commits), points, res)
+	z := multiPointChallenge(c, t, proof.W.Marshal())
+	coef, rz, zt := linearize(points, res, gamma, z)
+	// F = sum_i coef_i * C_i - rz * g - zt * W
+	// e(W', g^alpha - g^z) * e(-g, F) = 1
+	g2 := append(append([]curve.Point{}, commits...), vk.G2, proof.W)
+	scalars := append(append([]curve.Scalar{}, coef...), c.NewScalar().Neg(rz), c.NewScalar().Neg(zt))
+	f := MultiExpG2(c, g2, scalars)
+	p := c.NewG2().ScalarMult(vk.G2, z.BigInt())
+	p.Add(vk.G2Alpha, p.Neg(p))
+	return c.PairingCheck([]curve.Point{proof.WPrime, c.NewG1().Neg(vk.G1)}, []curve.Point{p, f})
+}
+
+// Create a proof of the evaluations res of the polynomials polys with the
+// commitments commits in G1, where res[i][j] is the evaluation of polys[i] at points[i][j].
+func (pk *G1Pk) CreateMultiProof(polys [][]curve.Scalar, commits []curve.Point, points [][]curve.Scalar) (res [][]curve.Scalar, proof *G1MultiProof, err error) {
+	if len(commits) != len(polys) {
+		return nil, nil, errors.New("Number of polynomials does not match the commitments")
+	}
+	if err = checkPointSets(len(polys), points, nil); err != nil {
+		return nil, nil, err
+	}
+	for i := range polys {
+		if err = pk.checkPoly(polys[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	c := pk.Curve
+	res, t, gamma, h := multiFirstRound(c, polys, marshalPoints(commits), points)
+	proof = new(G1MultiProof)
+	proof.W = MultiExpG1(c, pk.G1P, h)
+	z := multiPointChallenge(c, t, proof.W.Marshal())
+	proof.WPrime = MultiExpG1(c, pk.G1P, multiSecondRound(polys, points, res, gamma, h, z))
+	return res, proof, nil
+}
+
+// Verify the evaluations res at points of the polynomials with the
+// commitments commits in G1 against the proof.
+func (vk *VerifierKey) VerifyMultiProofG1(commits []curve.Point, points [][]curve.Scalar, res [][]curve.Scalar, proof *G1MultiProof) bool {
+	if checkPointSets(len(commits), points, res) != nil {
+		return false
+	}
+	c := vk.Curve
+	t, gamma := multiChallenge(c, marshalPoints(commits), points, res)
+	z := multiPointChallenge(c, t, proof.W.Marshal())
+	coef, rz, zt := linearize(points, res, gamma, z)
+	// e(F + z * W', g) * e(-W', g^alpha) = 1
+	// with F = sum_i coef_i * C_i - rz * g - zt * W
+	g1 := append(append([]curve.Point{}, commits...), vk.G1, proof.W, proof.WPrime)
+	scalars := append(append([]curve.Scalar{}, coef...), c.NewScalar().Neg(rz), c.NewScalar().Neg(zt), z)
+	return c.PairingCheck(
+		[]curve.Point{MultiExpG1(c, g1, scalars), c.NewG1().Neg(proof.WPrime)},
+		[]curve.Point{vk.G2, vk.G2Alpha})
+}
@@ -0,0 +1,123 @@
+package polycommit
+
+import (
+	"testing"
+
+	"crypto/rand"
+	"github.com/zhtluo/libpolycrypto/curve"
+)
+
+// Generate polynomials of different degrees with commitments and
+// overlapping point sets of different sizes.
+func generateMultiOpenings(pk *Pk) (polys [][]curve.Scalar, commits []curve.Point, points [][]curve.Scalar) {
+	c := pk.Curve
+	shared := randomElement(c, rand.Reader)
+	polys = make([][]curve.Scalar, aggregateSize)
+	commits = make([]curve.Point, aggregateSize)
+	points = make([][]curve.Scalar, aggregateSize)
+	for i := range polys {
+		polys[i] = generatePoly(c, rand.Reader)[:deg-i]
+		commits[i], _ = pk.Commit(polys[i])
+		points[i] = curve.NewScalars(c, i+1)
+		points[i][0].Set(shared)
+		for j := 1; j <= i; j++ {
+			points[i][j].SetRandom(rand.Reader)
+		}
+	}
+	return polys, commits, points
+}
+
+func TestMultiProof(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk Pk
+			pk.Setup(c, rand.Reader, deg)
+			vk, _ := pk.VerifierKey()
+			polys, commits, points := generateMultiOpenings(&pk)
+			res, proof, err := pk.CreateMultiProof(polys, commits, points)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			for i := range polys {
+				for j := range points[i] {
+					if !res[i][j].Equal(Evaluate(polys[i], points[i][j])) {
+						t.Error("CreateMultiProof failed. Wrong evaluation result.")
+					}
+				}
+			}
+			if vk.VerifyMultiProof(commits, points, res, proof) != true {
+				t.Error("VerifyMultiProof failed, expected: true.")
+			}
+			res[3][2].SetRandom(rand.Reader)
+			if vk.VerifyMultiProof(commits, points, res, proof) != false {
+				t.Error("VerifyMultiProof failed, expected: false.")
+			}
+			res[3][2].Set(Evaluate(polys[3], points[3][2]))
+			points[3][2].SetRandom(rand.Reader)
+			if vk.VerifyMultiProof(commits, points, res, proof) != false {
+				t.Error("VerifyMultiProof failed with a wrong point, expected: false.")
+			}
+			points[3][2].Set(points[3][1])
+			if _, _, err = pk.CreateMultiProof(polys, commits, points); err == nil {
+				t.Error("CreateMultiProof accepted duplicate points.")
+			}
+			if _, _, err = pk.CreateMultiProof(polys, commits, points[1:]); err == nil {
+				t.Error("CreateMultiProof accepted mismatched point sets.")
+			}
+		})
+	}
+}
+
+func TestMultiProofG1(t *testing.T) {
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk G1Pk
+			pk.Setup(c, rand.Reader, deg)
+			vk, _ := pk.VerifierKey()
+			polys := make([][]curve.Scalar, aggregateSize)
+			commits := make([]curve.Point, aggregateSize)
+			points := make([][]curve.Scalar, aggregateSize)
+			for i := range polys {
+				polys[i] = generatePoly(c, rand.Reader)
+				commits[i], _ = pk.Commit(polys[i])
+				points[i] = curve.NewScalars(c, aggregateSize-i)
+				for j := range points[i] {
+					points[i][j].SetInt64(int64(j))
+				}
+			}
+			res, proof, err := pk.CreateMultiProof(polys, commits, points)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if vk.VerifyMultiProofG1(commits, points, res, proof) != true {
+				t.Error("VerifyMultiProofG1 failed, expected: true.")
+			}
+			res[0][0].SetRandom(rand.Reader)
+			if vk.VerifyMultiProofG1(commits, points, res, proof) != false {
+				t.Error("VerifyMultiProofG1 failed, expected: false.")
+			}
+		})
+	}
+}
+
+func BenchmarkCreateMultiProof(b *testing.B) {
+	var pk Pk
+	pk.Setup(curve.BN256, rand.Reader, deg)
+	polys, commits, points := generateMultiOpenings(&pk)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		pk.CreateMultiProof(polys, commits, points)
+	}
+}
+
+func BenchmarkVerifyMultiProof(b *testing.B) {
+	var pk Pk
+	pk.Setup(curve.BN256, rand.Reader, deg)
+	vk, _ := pk.VerifierKey()
+	polys, commits, points := generateMultiOpenings(&pk)
+	res, proof, _ := pk.CreateMultiProof(polys, commits, points)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		vk.VerifyMultiProof(commits, points, res, proof)
+	}
+}
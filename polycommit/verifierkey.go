@@ -0,0 +1,102 @@
+package polycommit
+
+// This file implements the verifier key, i.e. the part of a public key
+// needed to verify evaluations, and the trimming of public keys.
+
+import (
+	"errors"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/point"
+	pb "github.com/zhtluo/libpolycrypto/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Struct VerifierKey implements the public key of a verifier,
+// holding the generators G1, G2 and G2Alpha = G2^alpha of Curve.
+type VerifierKey struct {
+	Curve   curve.Curve
+	G1      curve.Point
+	G2      curve.Point
+	G2Alpha curve.Point
+}
+
+// Return a copy of the first degree powers of the public key.
+func (pk *Pk) Trim(degree int) (*Pk, error) {
+	if degree < 1 {
+		return nil, errors.New("Degree is not positive")
+	}
+	if pk.Degree() < degree || len(pk.G2P) < degree {
+		return nil, errors.New("Public key has a degree less than the requested one")
+	}
+	tpk := new(Pk)
+	tpk.Curve = pk.Curve
+	tpk.G1P = append([]curve.Point{}, pk.G1P[:degree]...)
+	tpk.G2P = append([]curve.Point{}, pk.G2P[:degree]...)
+	return tpk, nil
+}
+
+// Extract the verifier key from the public key.
+func (pk *Pk) VerifierKey() (*VerifierKey, error) {
+	if pk.Degree() < 2 || len(pk.G2P) < 2 {
+		return nil, errors.New("Public key has a degree less than 2")
+	}
+	vk := new(VerifierKey)
+	vk.Curve = pk.Curve
+	vk.G1 = pk.G1P[0]
+	vk.G2 = pk.G2P[0]
+	vk.G2Alpha = pk.G2P[1]
+	return vk, nil
+}
+
+// Verify the evaluation of the polynomial with the commitment g2 and the witness g1.
+func (vk *VerifierKey) VerifyEval(g2 curve.Point, i curve.Scalar, res curve.Scalar, g1 curve.Point) bool {
+	c := vk.Curve
+	// e(w, g^(alpha - i)) * e(-g, C - g^res) = 1
+	p := c.NewG2().ScalarMult(vk.G2, i.BigInt())
+	p.Add(vk.G2Alpha, p.Neg(p))
+	r := c.NewG2().ScalarMult(vk.G2, res.BigInt())
+	r.Add(g2, r.Neg(r))
+	return c.PairingCheck([]curve.Point{g1, c.NewG1().Neg(vk.G1)}, []curve.Point{p, r})
+}
+
+// Serialize the verifier key.
+func (vk *VerifierKey) Marshal() ([]byte, error) {
+	return vk.MarshalFormat(point.Uncompressed)
+}
+
+// Serialize the verifier key with the points encoded in the format f.
+func (vk *VerifierKey) MarshalFormat(f point.Format) ([]byte, error) {
+	var sVk pb.VerifierKey
+	sVk.G1 = curve.Encode(vk.G1, f)
+	sVk.G2 = curve.Encode(vk.G2, f)
+	sVk.G2Alpha = curve.Encode(vk.G2Alpha, f)
+	sVk.Format = uint32(f)
+	sVk.Curve = vk.Curve.Name()
+	return proto.Marshal(&sVk)
+}
+
+// Deserialize the verifier key.
+func (vk *VerifierKey) Unmarshal(b []byte) error {
+	var sVk pb.VerifierKey
+	err := proto.Unmarshal(b, &sVk)
+	if err != nil {
+		return err
+	}
+	c, err := curve.ByName(sVk.Curve)
+	if err != nil {
+		return err
+	}
+	f := point.Format(sVk.Format)
+	if vk.G1, err = decodePoint(c.NewG1, sVk.G1, f); err != nil {
+		return err
+	}
+	if vk.G2, err = decodePoint(c.NewG2, sVk.G2, f); err != nil {
+		return err
+	}
+	if vk.G2Alpha, err = decodePoint(c.NewG2, sVk.G2Alpha, f); err != nil {
+		return err
+	}
+	vk.Curve = c
+	return nil
+}
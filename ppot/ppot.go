@@ -0,0 +1,134 @@
+// Package ppot reads the transcripts of the Perpetual Powers of Tau ceremony
+// on BN254, which is the curve implemented by bn256, into a polycommit.Pk.
+//
+// A transcript starts with the 64-byte BLAKE2b hash of the previous one,
+// followed by 2^(Power + 1) - 1 powers of tau in G1 and 2^Power powers of
+// tau in G2, and then sections this package does not use.
+// Challenge files store the points uncompressed and response files
+// compressed. Every coordinate is big-endian, G2 coordinates are written
+// as c1 followed by c0, and the top two bits of the first byte of a point
+// flag compression and infinity respectively.
+
+package ppot
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/polycommit"
+)
+
+const (
+	hashSize = 64
+	// Size of a coordinate in bytes.
+	fpSize       = 32
+	flagCompress = 0x80
+	flagInfinity = 0x40
+)
+
+// Struct Params implements the layout of a transcript.
+// A transcript of Power holds 2^Power powers of tau in G2.
+type Params struct {
+	Power      int
+	Compressed bool
+}
+
+func (p *Params) g1Size() int64 {
+	if p.Compressed {
+		return fpSize
+	}
+	return 2 * fpSize
+}
+
+func (p *Params) g2Size() int64 {
+	return 2 * p.g1Size()
+}
+
+// Read the first degree powers of tau from the transcript r laid out as p,
+// and return them as a public key after checking their consistency.
+func Read(r io.ReaderAt, p Params, degree int) (*polycommit.Pk, error) {
+	if p.Power < 1 || p.Power > 28 {
+		return nil, errors.New("Transcript power is out of range")
+	}
+	if degree < 2 || degree > 1<<uint(p.Power) {
+		return nil, errors.New("Degree exceeds the transcript")
+	}
+	pk := new(polycommit.Pk)
+	pk.Curve = curve.BN256
+	pk.G1P = make([]curve.Point, degree)
+	pk.G2P = make([]curve.Point, degree)
+	g1Offset := int64(hashSize)
+	g2Offset := g1Offset + (int64(2)<<uint(p.Power)-1)*p.g1Size()
+	buf := make([]byte, int64(degree)*p.g1Size())
+	if _, err := r.ReadAt(buf, g1Offset); err != nil {
+		return nil, err
+	}
+	for i := range pk.G1P {
+		pk.G1P[i] = curve.BN256.NewG1()
+		if err := decodePoint(pk.G1P[i], buf[int64(i)*p.g1Size():int64(i+1)*p.g1Size()], p.Compressed); err != nil {
+			return nil, err
+		}
+	}
+	buf = make([]byte, int64(degree)*p.g2Size())
+	if _, err := r.ReadAt(buf, g2Offset); err != nil {
+		return nil, err
+	}
+	for i := range pk.G2P {
+		pk.G2P[i] = curve.BN256.NewG2()
+		if err := decodePoint(pk.G2P[i], buf[int64(i)*p.g2Size():int64(i+1)*p.g2Size()], p.Compressed); err != nil {
+			return nil, err
+		}
+	}
+	if err := pk.CheckPowers(); err != nil {
+		return nil, err
+	}
+	return pk, nil
+}
+
+// Read the first degree powers of tau from the transcript file at path.
+func ReadFile(path string, p Params, degree int) (*polycommit.Pk, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f, p, degree)
+}
+
+// Split the flags off the first byte of the uncompressed point b into a copy.
+// Compressed points are decoded by the curve package.
+func splitFlags(b []byte) (m []byte, infinity bool, err error) {
+	if b[0]&flagCompress != 0 {
+		return nil, false, errors.New("Uncompressed point has the compression flag set")
+	}
+	m = make([]byte, len(b))
+	copy(m, b)
+	infinity = m[0]&flagInfinity != 0
+	m[0] &^= flagInfinity
+	if infinity {
+		for _, v := range m {
+			if v != 0 {
+				return nil, false, errors.New("Point at infinity is not encoded as zero")
+			}
+		}
+	}
+	return m, infinity, nil
+}
+
+// Decode the point b in G1 or G2 of bn256 into g.
+func decodePoint(g curve.Point, b []byte, compressed bool) error {
+	if compressed {
+		return g.UnmarshalCompressed(b)
+	}
+	m, infinity, err := splitFlags(b)
+	if err != nil {
+		return err
+	}
+	if infinity {
+		g.SetInfinity()
+		return nil
+	}
+	return g.Unmarshal(m)
+}
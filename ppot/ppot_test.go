@@ -0,0 +1,161 @@
+package ppot
+
+import (
+	"testing"
+
+	"bytes"
+	"crypto/rand"
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/fr"
+	"github.com/zhtluo/libpolycrypto/point"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+)
+
+const (
+	power = 3
+)
+
+// Encode the point g in G1 in the transcript format.
+func encodeG1(g *bn256.G1, compressed bool) []byte {
+	if compressed {
+		return point.CompressG1(g)
+	}
+	m := g.Marshal()
+	if bytes.Equal(m, make([]byte, len(m))) {
+		m[0] |= flagInfinity
+	}
+	return m
+}
+
+// Encode the point g in G2 in the transcript format.
+func encodeG2(g *bn256.G2, compressed bool) []byte {
+	if compressed {
+		return point.CompressG2(g)
+	}
+	m := g.Marshal()
+	if bytes.Equal(m, make([]byte, len(m))) {
+		m[0] |= flagInfinity
+	}
+	return m
+}
+
+// Generate a transcript of power with the trapdoor tau,
+// including the sections after the powers of tau in G2.
+func generateTranscript(tau *fr.Element, compressed bool) []byte {
+	b := make([]byte, hashSize)
+	rand.Read(b)
+	tm := fr.NewElement(1)
+	for i := 0; i < 2<<power-1; i++ {
+		b = append(b, encodeG1(new(bn256.G1).ScalarBaseMult(tm.BigInt(nil)), compressed)...)
+		tm.Mul(&tm, tau)
+	}
+	tm.SetOne()
+	for i := 0; i < 1<<power; i++ {
+		b = append(b, encodeG2(new(bn256.G2).ScalarBaseMult(tm.BigInt(nil)), compressed)...)
+		tm.Mul(&tm, tau)
+	}
+	// alpha * tau^i and beta * tau^i in G1, then beta in G2.
+	for i := 0; i < 2<<power; i++ {
+		_, g1, _ := bn256.RandomG1(rand.Reader)
+		b = append(b, encodeG1(g1, compressed)...)
+	}
+	_, g2, _ := bn256.RandomG2(rand.Reader)
+	return append(b, encodeG2(g2, compressed)...)
+}
+
+func TestDecode(t *testing.T) {
+	for _, compressed := range []bool{false, true} {
+		for i := 0; i < 16; i++ {
+			_, g1, _ := bn256.RandomG1(rand.Reader)
+			_, g2, _ := bn256.RandomG2(rand.Reader)
+			if i == 0 {
+				g1.ScalarBaseMult(new(big.Int))
+				g2.ScalarBaseMult(new(big.Int))
+			}
+			d1 := curve.BN256.NewG1()
+			d2 := curve.BN256.NewG2()
+			if err := decodePoint(d1, encodeG1(g1, compressed), compressed); err != nil {
+				t.Fatal(err)
+			}
+			if err := decodePoint(d2, encodeG2(g2, compressed), compressed); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(d1.Marshal(), g1.Marshal()) || !bytes.Equal(d2.Marshal(), g2.Marshal()) {
+				t.Fatal("Decode failed. Wrong point.")
+			}
+		}
+	}
+	// An x coordinate with no point on the curve.
+	b := make([]byte, fpSize)
+	b[fpSize-1] = 4
+	if decodePoint(curve.BN256.NewG1(), b, true) == nil {
+		t.Error("Decode accepted a point not on the curve.")
+	}
+	_, g2, _ := bn256.RandomG2(rand.Reader)
+	m := encodeG2(g2, false)
+	m[0] |= flagCompress
+	if decodePoint(curve.BN256.NewG2(), m, false) == nil {
+		t.Error("Decode accepted an uncompressed point with the compression flag.")
+	}
+}
+
+func TestRead(t *testing.T) {
+	tau, _ := new(fr.Element).SetRandom(rand.Reader)
+	for _, compressed := range []bool{false, true} {
+		p := Params{Power: power, Compressed: compressed}
+		transcript := generateTranscript(tau, compressed)
+		for _, degree := range []int{2, 5, 1 << power} {
+			pk, err := Read(bytes.NewReader(transcript), p, degree)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pk.Degree() != degree || len(pk.G2P) != degree {
+				t.Fatalf("Read failed. Expected degree: %d, Got: %d", degree, pk.Degree())
+			}
+			tm := fr.NewElement(1)
+			for i := 0; i < degree; i++ {
+				g1 := new(bn256.G1).ScalarBaseMult(tm.BigInt(nil))
+				g2 := new(bn256.G2).ScalarBaseMult(tm.BigInt(nil))
+				if !bytes.Equal(pk.G1P[i].Marshal(), g1.Marshal()) || !bytes.Equal(pk.G2P[i].Marshal(), g2.Marshal()) {
+					t.Fatal("Read failed. Wrong power of tau.")
+				}
+				tm.Mul(&tm, tau)
+			}
+		}
+		if _, err := Read(bytes.NewReader(transcript), p, 1<<power+1); err == nil {
+			t.Error("Read accepted a degree beyond the transcript.")
+		}
+		if _, err := Read(bytes.NewReader(transcript[:hashSize+10]), p, 2); err == nil {
+			t.Error("Read accepted a truncated transcript.")
+		}
+		// Swap two powers in G1.
+		size := int(p.g1Size())
+		broken := append([]byte{}, transcript...)
+		copy(broken[hashSize+2*size:hashSize+3*size], transcript[hashSize+3*size:hashSize+4*size])
+		copy(broken[hashSize+3*size:hashSize+4*size], transcript[hashSize+2*size:hashSize+3*size])
+		if _, err := Read(bytes.NewReader(broken), p, 1<<power); err == nil {
+			t.Error("Read accepted inconsistent powers.")
+		}
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	tau, _ := new(fr.Element).SetRandom(rand.Reader)
+	path := filepath.Join(t.TempDir(), "challenge")
+	if err := ioutil.WriteFile(path, generateTranscript(tau, false), 0600); err != nil {
+		t.Fatal(err)
+	}
+	pk, err := ReadFile(path, Params{Power: power}, 1<<power)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pk.Degree() != 1<<power {
+		t.Error("ReadFile failed. Wrong degree.")
+	}
+	if _, err = ReadFile(filepath.Join(t.TempDir(), "missing"), Params{Power: power}, 2); err == nil {
+		t.Error("ReadFile accepted a missing file.")
+	}
+}
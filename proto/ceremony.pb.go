@@ -0,0 +1,195 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.13.0
+// source: ceremony.proto
+
+package proto
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type Contribution struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	G1P1   []byte `protobuf:"bytes,1,opt,name=g1_p1,json=g1P1,proto3" json:"g1_p1,omitempty"`
+	G2Tau  []byte `protobuf:"bytes,2,opt,name=g2_tau,json=g2Tau,proto3" json:"g2_tau,omitempty"`
+	PokR   []byte `protobuf:"bytes,3,opt,name=pok_r,json=pokR,proto3" json:"pok_r,omitempty"`
+	PokS   []byte `protobuf:"bytes,4,opt,name=pok_s,json=pokS,proto3" json:"pok_s,omitempty"`
+	Format uint32 `protobuf:"varint,5,opt,name=format,proto3" json:"format,omitempty"`
+	Curve  string `protobuf:"bytes,6,opt,name=curve,proto3" json:"curve,omitempty"`
+}
+
+func (x *Contribution) Reset() {
+	*x = Contribution{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ceremony_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Contribution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Contribution) ProtoMessage() {}
+
+func (x *Contribution) ProtoReflect() protoreflect.Message {
+	mi := &file_ceremony_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Contribution.ProtoReflect.Descriptor instead.
+func (*Contribution) Descriptor() ([]byte, []int) {
+	return file_ceremony_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Contribution) GetG1P1() []byte {
+	if x != nil {
+		return x.G1P1
+	}
+	return nil
+}
+
+func (x *Contribution) GetG2Tau() []byte {
+	if x != nil {
+		return x.G2Tau
+	}
+	return nil
+}
+
+func (x *Contribution) GetPokR() []byte {
+	if x != nil {
+		return x.PokR
+	}
+	return nil
+}
+
+func (x *Contribution) GetPokS() []byte {
+	if x != nil {
+		return x.PokS
+	}
+	return nil
+}
+
+func (x *Contribution) GetFormat() uint32 {
+	if x != nil {
+		return x.Format
+	}
+	return 0
+}
+
+func (x *Contribution) GetCurve() string {
+	if x != nil {
+		return x.Curve
+	}
+	return ""
+}
+
+var File_ceremony_proto protoreflect.FileDescriptor
+
+var file_ceremony_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x63, 0x65, 0x72, 0x65, 0x6d, 0x6f, 0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x92, 0x01, 0x0a, 0x0c, 0x43, 0x6f, 0x6e, 0x74,
+	0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x13, 0x0a, 0x05, 0x67, 0x31, 0x5f, 0x70,
+	0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x67, 0x31, 0x50, 0x31, 0x12, 0x15, 0x0a,
+	0x06, 0x67, 0x32, 0x5f, 0x74, 0x61, 0x75, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x67,
+	0x32, 0x54, 0x61, 0x75, 0x12, 0x13, 0x0a, 0x05, 0x70, 0x6f, 0x6b, 0x5f, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x70, 0x6f, 0x6b, 0x52, 0x12, 0x13, 0x0a, 0x05, 0x70, 0x6f, 0x6b,
+	0x5f, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x70, 0x6f, 0x6b, 0x53, 0x12, 0x16,
+	0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06,
+	0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x42, 0x27, 0x5a, 0x25,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x7a, 0x68, 0x74, 0x6c, 0x75,
+	0x6f, 0x2f, 0x6c, 0x69, 0x62, 0x70, 0x6f, 0x6c, 0x79, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_ceremony_proto_rawDescOnce sync.Once
+	file_ceremony_proto_rawDescData = file_ceremony_proto_rawDesc
+)
+
+func file_ceremony_proto_rawDescGZIP() []byte {
+	file_ceremony_proto_rawDescOnce.Do(func() {
+		file_ceremony_proto_rawDescData = protoimpl.X.CompressGZIP(file_ceremony_proto_rawDescData)
+	})
+	return file_ceremony_proto_rawDescData
+}
+
+var file_ceremony_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_ceremony_proto_goTypes = []interface{}{
+	(*Contribution)(nil), // 0: proto.Contribution
+}
+var file_ceremony_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_ceremony_proto_init() }
+func file_ceremony_proto_init() {
+	if File_ceremony_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_ceremony_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Contribution); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_ceremony_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_ceremony_proto_goTypes,
+		DependencyIndexes: file_ceremony_proto_depIdxs,
+		MessageInfos:      file_ceremony_proto_msgTypes,
+	}.Build()
+	File_ceremony_proto = out.File
+	file_ceremony_proto_rawDesc = nil
+	file_ceremony_proto_goTypes = nil
+	file_ceremony_proto_depIdxs = nil
+}
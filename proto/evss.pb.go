@@ -30,8 +30,12 @@ type PublicInfo struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Pk     []byte `protobuf:"bytes,1,opt,name=pk,proto3" json:"pk,omitempty"`
-	Commit []byte `protobuf:"bytes,2,opt,name=commit,proto3" json:"commit,omitempty"`
+	Pk          []byte `protobuf:"bytes,1,opt,name=pk,proto3" json:"pk,omitempty"`
+	Commit      []byte `protobuf:"bytes,2,opt,name=commit,proto3" json:"commit,omitempty"`
+	Mode        uint32 `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	Threshold   uint32 `protobuf:"varint,4,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	DegreeProof []byte `protobuf:"bytes,5,opt,name=degree_proof,json=degreeProof,proto3" json:"degree_proof,omitempty"`
+	Format      uint32 `protobuf:"varint,6,opt,name=format,proto3" json:"format,omitempty"`
 }
 
 func (x *PublicInfo) Reset() {
@@ -80,6 +84,34 @@ func (x *PublicInfo) GetCommit() []byte {
 	return nil
 }
 
+func (x *PublicInfo) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *PublicInfo) GetThreshold() uint32 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *PublicInfo) GetDegreeProof() []byte {
+	if x != nil {
+		return x.DegreeProof
+	}
+	return nil
+}
+
+func (x *PublicInfo) GetFormat() uint32 {
+	if x != nil {
+		return x.Format
+	}
+	return 0
+}
+
 type Share struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -88,6 +120,8 @@ type Share struct {
 	Index   []byte `protobuf:"bytes,1,opt,name=Index,proto3" json:"Index,omitempty"`
 	Result  []byte `protobuf:"bytes,2,opt,name=Result,proto3" json:"Result,omitempty"`
 	Witness []byte `protobuf:"bytes,3,opt,name=Witness,proto3" json:"Witness,omitempty"`
+	Format  uint32 `protobuf:"varint,4,opt,name=format,proto3" json:"format,omitempty"`
+	Curve   string `protobuf:"bytes,5,opt,name=curve,proto3" json:"curve,omitempty"`
 }
 
 func (x *Share) Reset() {
@@ -143,23 +177,145 @@ func (x *Share) GetWitness() []byte {
 	return nil
 }
 
+func (x *Share) GetFormat() uint32 {
+	if x != nil {
+		return x.Format
+	}
+	return 0
+}
+
+func (x *Share) GetCurve() string {
+	if x != nil {
+		return x.Curve
+	}
+	return ""
+}
+
+type VerifierInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vk          []byte `protobuf:"bytes,1,opt,name=vk,proto3" json:"vk,omitempty"`
+	Commit      []byte `protobuf:"bytes,2,opt,name=commit,proto3" json:"commit,omitempty"`
+	Mode        uint32 `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	DegreeProof []byte `protobuf:"bytes,5,opt,name=degree_proof,json=degreeProof,proto3" json:"degree_proof,omitempty"`
+	Format      uint32 `protobuf:"varint,6,opt,name=format,proto3" json:"format,omitempty"`
+	Threshold   uint32 `protobuf:"varint,7,opt,name=threshold,proto3" json:"threshold,omitempty"`
+}
+
+func (x *VerifierInfo) Reset() {
+	*x = VerifierInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_evss_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifierInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifierInfo) ProtoMessage() {}
+
+func (x *VerifierInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_evss_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifierInfo.ProtoReflect.Descriptor instead.
+func (*VerifierInfo) Descriptor() ([]byte, []int) {
+	return file_evss_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VerifierInfo) GetVk() []byte {
+	if x != nil {
+		return x.Vk
+	}
+	return nil
+}
+
+func (x *VerifierInfo) GetCommit() []byte {
+	if x != nil {
+		return x.Commit
+	}
+	return nil
+}
+
+func (x *VerifierInfo) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *VerifierInfo) GetDegreeProof() []byte {
+	if x != nil {
+		return x.DegreeProof
+	}
+	return nil
+}
+
+func (x *VerifierInfo) GetFormat() uint32 {
+	if x != nil {
+		return x.Format
+	}
+	return 0
+}
+
+func (x *VerifierInfo) GetThreshold() uint32 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
 var File_evss_proto protoreflect.FileDescriptor
 
 var file_evss_proto_rawDesc = []byte{
 	0x0a, 0x0a, 0x65, 0x76, 0x73, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x1a, 0x10, 0x70, 0x6f, 0x6c, 0x79, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x34, 0x0a, 0x0a, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49,
-	0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a, 0x02, 0x70, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x02, 0x70, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x22, 0x4f, 0x0a, 0x05, 0x53,
-	0x68, 0x61, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x52, 0x65,
-	0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x52, 0x65, 0x73, 0x75,
-	0x6c, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x07, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x42, 0x27, 0x5a, 0x25,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x7a, 0x68, 0x74, 0x6c, 0x75,
-	0x6f, 0x2f, 0x6c, 0x69, 0x62, 0x70, 0x6f, 0x6c, 0x79, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2f,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa1, 0x01, 0x0a, 0x0a, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a, 0x02, 0x70, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x02, 0x70, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x6d, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x21,
+	0x0a, 0x0c, 0x64, 0x65, 0x67, 0x72, 0x65, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x64, 0x65, 0x67, 0x72, 0x65, 0x65, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x22, 0x7d, 0x0a, 0x05, 0x53, 0x68, 0x61,
+	0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x07, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f,
+	0x72, 0x6d, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d,
+	0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x22, 0xa9, 0x01, 0x0a, 0x0c, 0x56, 0x65, 0x72,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a, 0x02, 0x76, 0x6b, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x76, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x67, 0x72, 0x65, 0x65, 0x5f,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x64, 0x65, 0x67,
+	0x72, 0x65, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d,
+	0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x12, 0x1c, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x4a, 0x04,
+	0x08, 0x04, 0x10, 0x05, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x7a, 0x68, 0x74, 0x6c, 0x75, 0x6f, 0x2f, 0x6c, 0x69, 0x62, 0x70, 0x6f, 0x6c,
+	0x79, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -174,10 +330,11 @@ func file_evss_proto_rawDescGZIP() []byte {
 	return file_evss_proto_rawDescData
 }
 
-var file_evss_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_evss_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_evss_proto_goTypes = []interface{}{
-	(*PublicInfo)(nil), // 0: proto.PublicInfo
-	(*Share)(nil),      // 1: proto.Share
+	(*PublicInfo)(nil),   // 0: proto.PublicInfo
+	(*Share)(nil),        // 1: proto.Share
+	(*VerifierInfo)(nil), // 2: proto.VerifierInfo
 }
 var file_evss_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -218,6 +375,18 @@ func file_evss_proto_init() {
 				return nil
 			}
 		}
+		file_evss_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifierInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -225,7 +394,7 @@ func file_evss_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_evss_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
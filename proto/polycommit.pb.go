@@ -30,8 +30,10 @@ type Pk struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	G1P [][]byte `protobuf:"bytes,1,rep,name=g1_p,json=g1P,proto3" json:"g1_p,omitempty"`
-	G2P [][]byte `protobuf:"bytes,2,rep,name=g2_p,json=g2P,proto3" json:"g2_p,omitempty"`
+	G1P    [][]byte `protobuf:"bytes,1,rep,name=g1_p,json=g1P,proto3" json:"g1_p,omitempty"`
+	G2P    [][]byte `protobuf:"bytes,2,rep,name=g2_p,json=g2P,proto3" json:"g2_p,omitempty"`
+	Format uint32   `protobuf:"varint,3,opt,name=format,proto3" json:"format,omitempty"`
+	Curve  string   `protobuf:"bytes,4,opt,name=curve,proto3" json:"curve,omitempty"`
 }
 
 func (x *Pk) Reset() {
@@ -80,17 +82,513 @@ func (x *Pk) GetG2P() [][]byte {
 	return nil
 }
 
+func (x *Pk) GetFormat() uint32 {
+	if x != nil {
+		return x.Format
+	}
+	return 0
+}
+
+func (x *Pk) GetCurve() string {
+	if x != nil {
+		return x.Curve
+	}
+	return ""
+}
+
+type PedPk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	G1P    [][]byte `protobuf:"bytes,1,rep,name=g1_p,json=g1P,proto3" json:"g1_p,omitempty"`
+	G2P    [][]byte `protobuf:"bytes,2,rep,name=g2_p,json=g2P,proto3" json:"g2_p,omitempty"`
+	H1P    [][]byte `protobuf:"bytes,3,rep,name=h1_p,json=h1P,proto3" json:"h1_p,omitempty"`
+	H2P    [][]byte `protobuf:"bytes,4,rep,name=h2_p,json=h2P,proto3" json:"h2_p,omitempty"`
+	Format uint32   `protobuf:"varint,5,opt,name=format,proto3" json:"format,omitempty"`
+	Curve  string   `protobuf:"bytes,6,opt,name=curve,proto3" json:"curve,omitempty"`
+}
+
+func (x *PedPk) Reset() {
+	*x = PedPk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_polycommit_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PedPk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PedPk) ProtoMessage() {}
+
+func (x *PedPk) ProtoReflect() protoreflect.Message {
+	mi := &file_polycommit_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PedPk.ProtoReflect.Descriptor instead.
+func (*PedPk) Descriptor() ([]byte, []int) {
+	return file_polycommit_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PedPk) GetG1P() [][]byte {
+	if x != nil {
+		return x.G1P
+	}
+	return nil
+}
+
+func (x *PedPk) GetG2P() [][]byte {
+	if x != nil {
+		return x.G2P
+	}
+	return nil
+}
+
+func (x *PedPk) GetH1P() [][]byte {
+	if x != nil {
+		return x.H1P
+	}
+	return nil
+}
+
+func (x *PedPk) GetH2P() [][]byte {
+	if x != nil {
+		return x.H2P
+	}
+	return nil
+}
+
+func (x *PedPk) GetFormat() uint32 {
+	if x != nil {
+		return x.Format
+	}
+	return 0
+}
+
+func (x *PedPk) GetCurve() string {
+	if x != nil {
+		return x.Curve
+	}
+	return ""
+}
+
+type VerifierKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	G1      []byte `protobuf:"bytes,1,opt,name=g1,proto3" json:"g1,omitempty"`
+	G2      []byte `protobuf:"bytes,2,opt,name=g2,proto3" json:"g2,omitempty"`
+	G2Alpha []byte `protobuf:"bytes,3,opt,name=g2_alpha,json=g2Alpha,proto3" json:"g2_alpha,omitempty"`
+	Format  uint32 `protobuf:"varint,4,opt,name=format,proto3" json:"format,omitempty"`
+	Curve   string `protobuf:"bytes,5,opt,name=curve,proto3" json:"curve,omitempty"`
+}
+
+func (x *VerifierKey) Reset() {
+	*x = VerifierKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_polycommit_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifierKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifierKey) ProtoMessage() {}
+
+func (x *VerifierKey) ProtoReflect() protoreflect.Message {
+	mi := &file_polycommit_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifierKey.ProtoReflect.Descriptor instead.
+func (*VerifierKey) Descriptor() ([]byte, []int) {
+	return file_polycommit_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VerifierKey) GetG1() []byte {
+	if x != nil {
+		return x.G1
+	}
+	return nil
+}
+
+func (x *VerifierKey) GetG2() []byte {
+	if x != nil {
+		return x.G2
+	}
+	return nil
+}
+
+func (x *VerifierKey) GetG2Alpha() []byte {
+	if x != nil {
+		return x.G2Alpha
+	}
+	return nil
+}
+
+func (x *VerifierKey) GetFormat() uint32 {
+	if x != nil {
+		return x.Format
+	}
+	return 0
+}
+
+func (x *VerifierKey) GetCurve() string {
+	if x != nil {
+		return x.Curve
+	}
+	return ""
+}
+
+type PedVerifierKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	G1      []byte `protobuf:"bytes,1,opt,name=g1,proto3" json:"g1,omitempty"`
+	H1      []byte `protobuf:"bytes,2,opt,name=h1,proto3" json:"h1,omitempty"`
+	G2      []byte `protobuf:"bytes,3,opt,name=g2,proto3" json:"g2,omitempty"`
+	G2Alpha []byte `protobuf:"bytes,4,opt,name=g2_alpha,json=g2Alpha,proto3" json:"g2_alpha,omitempty"`
+	Format  uint32 `protobuf:"varint,5,opt,name=format,proto3" json:"format,omitempty"`
+	Curve   string `protobuf:"bytes,6,opt,name=curve,proto3" json:"curve,omitempty"`
+}
+
+func (x *PedVerifierKey) Reset() {
+	*x = PedVerifierKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_polycommit_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PedVerifierKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PedVerifierKey) ProtoMessage() {}
+
+func (x *PedVerifierKey) ProtoReflect() protoreflect.Message {
+	mi := &file_polycommit_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PedVerifierKey.ProtoReflect.Descriptor instead.
+func (*PedVerifierKey) Descriptor() ([]byte, []int) {
+	return file_polycommit_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PedVerifierKey) GetG1() []byte {
+	if x != nil {
+		return x.G1
+	}
+	return nil
+}
+
+func (x *PedVerifierKey) GetH1() []byte {
+	if x != nil {
+		return x.H1
+	}
+	return nil
+}
+
+func (x *PedVerifierKey) GetG2() []byte {
+	if x != nil {
+		return x.G2
+	}
+	return nil
+}
+
+func (x *PedVerifierKey) GetG2Alpha() []byte {
+	if x != nil {
+		return x.G2Alpha
+	}
+	return nil
+}
+
+func (x *PedVerifierKey) GetFormat() uint32 {
+	if x != nil {
+		return x.Format
+	}
+	return 0
+}
+
+func (x *PedVerifierKey) GetCurve() string {
+	if x != nil {
+		return x.Curve
+	}
+	return ""
+}
+
+type G1Pk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	G1P     [][]byte `protobuf:"bytes,1,rep,name=g1_p,json=g1P,proto3" json:"g1_p,omitempty"`
+	G2      []byte   `protobuf:"bytes,2,opt,name=g2,proto3" json:"g2,omitempty"`
+	G2Alpha []byte   `protobuf:"bytes,3,opt,name=g2_alpha,json=g2Alpha,proto3" json:"g2_alpha,omitempty"`
+	Format  uint32   `protobuf:"varint,4,opt,name=format,proto3" json:"format,omitempty"`
+	Bounds  []uint32 `protobuf:"varint,5,rep,packed,name=bounds,proto3" json:"bounds,omitempty"`
+	G2Shift [][]byte `protobuf:"bytes,6,rep,name=g2_shift,json=g2Shift,proto3" json:"g2_shift,omitempty"`
+	Curve   string   `protobuf:"bytes,7,opt,name=curve,proto3" json:"curve,omitempty"`
+}
+
+func (x *G1Pk) Reset() {
+	*x = G1Pk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_polycommit_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *G1Pk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*G1Pk) ProtoMessage() {}
+
+func (x *G1Pk) ProtoReflect() protoreflect.Message {
+	mi := &file_polycommit_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use G1Pk.ProtoReflect.Descriptor instead.
+func (*G1Pk) Descriptor() ([]byte, []int) {
+	return file_polycommit_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *G1Pk) GetG1P() [][]byte {
+	if x != nil {
+		return x.G1P
+	}
+	return nil
+}
+
+func (x *G1Pk) GetG2() []byte {
+	if x != nil {
+		return x.G2
+	}
+	return nil
+}
+
+func (x *G1Pk) GetG2Alpha() []byte {
+	if x != nil {
+		return x.G2Alpha
+	}
+	return nil
+}
+
+func (x *G1Pk) GetFormat() uint32 {
+	if x != nil {
+		return x.Format
+	}
+	return 0
+}
+
+func (x *G1Pk) GetBounds() []uint32 {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *G1Pk) GetG2Shift() [][]byte {
+	if x != nil {
+		return x.G2Shift
+	}
+	return nil
+}
+
+func (x *G1Pk) GetCurve() string {
+	if x != nil {
+		return x.Curve
+	}
+	return ""
+}
+
+type DegreeKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Degree  uint32 `protobuf:"varint,1,opt,name=degree,proto3" json:"degree,omitempty"`
+	G1Shift []byte `protobuf:"bytes,2,opt,name=g1_shift,json=g1Shift,proto3" json:"g1_shift,omitempty"`
+	G2      []byte `protobuf:"bytes,3,opt,name=g2,proto3" json:"g2,omitempty"`
+	Format  uint32 `protobuf:"varint,4,opt,name=format,proto3" json:"format,omitempty"`
+	G2Shift []byte `protobuf:"bytes,5,opt,name=g2_shift,json=g2Shift,proto3" json:"g2_shift,omitempty"`
+	Curve   string `protobuf:"bytes,6,opt,name=curve,proto3" json:"curve,omitempty"`
+}
+
+func (x *DegreeKey) Reset() {
+	*x = DegreeKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_polycommit_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DegreeKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DegreeKey) ProtoMessage() {}
+
+func (x *DegreeKey) ProtoReflect() protoreflect.Message {
+	mi := &file_polycommit_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DegreeKey.ProtoReflect.Descriptor instead.
+func (*DegreeKey) Descriptor() ([]byte, []int) {
+	return file_polycommit_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DegreeKey) GetDegree() uint32 {
+	if x != nil {
+		return x.Degree
+	}
+	return 0
+}
+
+func (x *DegreeKey) GetG1Shift() []byte {
+	if x != nil {
+		return x.G1Shift
+	}
+	return nil
+}
+
+func (x *DegreeKey) GetG2() []byte {
+	if x != nil {
+		return x.G2
+	}
+	return nil
+}
+
+func (x *DegreeKey) GetFormat() uint32 {
+	if x != nil {
+		return x.Format
+	}
+	return 0
+}
+
+func (x *DegreeKey) GetG2Shift() []byte {
+	if x != nil {
+		return x.G2Shift
+	}
+	return nil
+}
+
+func (x *DegreeKey) GetCurve() string {
+	if x != nil {
+		return x.Curve
+	}
+	return ""
+}
+
 var File_polycommit_proto protoreflect.FileDescriptor
 
 var file_polycommit_proto_rawDesc = []byte{
 	0x0a, 0x10, 0x70, 0x6f, 0x6c, 0x79, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2a, 0x0a, 0x02, 0x50, 0x6b, 0x12,
+	0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x58, 0x0a, 0x02, 0x50, 0x6b, 0x12,
 	0x11, 0x0a, 0x04, 0x67, 0x31, 0x5f, 0x70, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x03, 0x67,
 	0x31, 0x50, 0x12, 0x11, 0x0a, 0x04, 0x67, 0x32, 0x5f, 0x70, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c,
-	0x52, 0x03, 0x67, 0x32, 0x50, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x7a, 0x68, 0x74, 0x6c, 0x75, 0x6f, 0x2f, 0x6c, 0x69, 0x62, 0x70, 0x6f,
-	0x6c, 0x79, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x52, 0x03, 0x67, 0x32, 0x50, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x75,
+	0x72, 0x76, 0x65, 0x22, 0x81, 0x01, 0x0a, 0x05, 0x50, 0x65, 0x64, 0x50, 0x6b, 0x12, 0x11, 0x0a,
+	0x04, 0x67, 0x31, 0x5f, 0x70, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x03, 0x67, 0x31, 0x50,
+	0x12, 0x11, 0x0a, 0x04, 0x67, 0x32, 0x5f, 0x70, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x03,
+	0x67, 0x32, 0x50, 0x12, 0x11, 0x0a, 0x04, 0x68, 0x31, 0x5f, 0x70, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0c, 0x52, 0x03, 0x68, 0x31, 0x50, 0x12, 0x11, 0x0a, 0x04, 0x68, 0x32, 0x5f, 0x70, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x0c, 0x52, 0x03, 0x68, 0x32, 0x50, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72,
+	0x6d, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x22, 0x76, 0x0a, 0x0b, 0x56, 0x65, 0x72, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x31, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x02, 0x67, 0x31, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x32, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x02, 0x67, 0x32, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x32, 0x5f, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x67, 0x32, 0x41, 0x6c, 0x70, 0x68,
+	0x61, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x75, 0x72,
+	0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x22,
+	0x89, 0x01, 0x0a, 0x0e, 0x50, 0x65, 0x64, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x72, 0x4b,
+	0x65, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02,
+	0x67, 0x31, 0x12, 0x0e, 0x0a, 0x02, 0x68, 0x31, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02,
+	0x68, 0x31, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x32, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02,
+	0x67, 0x32, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x32, 0x5f, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x67, 0x32, 0x41, 0x6c, 0x70, 0x68, 0x61, 0x12, 0x16, 0x0a,
+	0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x66,
+	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x22, 0xa5, 0x01, 0x0a, 0x04,
+	0x47, 0x31, 0x50, 0x6b, 0x12, 0x11, 0x0a, 0x04, 0x67, 0x31, 0x5f, 0x70, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x03, 0x67, 0x31, 0x50, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x32, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x02, 0x67, 0x32, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x32, 0x5f, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x67, 0x32, 0x41, 0x6c, 0x70,
+	0x68, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x6f,
+	0x75, 0x6e, 0x64, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x06, 0x62, 0x6f, 0x75, 0x6e,
+	0x64, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x32, 0x5f, 0x73, 0x68, 0x69, 0x66, 0x74, 0x18, 0x06,
+	0x20, 0x03, 0x28, 0x0c, 0x52, 0x07, 0x67, 0x32, 0x53, 0x68, 0x69, 0x66, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x75,
+	0x72, 0x76, 0x65, 0x22, 0x97, 0x01, 0x0a, 0x09, 0x44, 0x65, 0x67, 0x72, 0x65, 0x65, 0x4b, 0x65,
+	0x79, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x67, 0x72, 0x65, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x06, 0x64, 0x65, 0x67, 0x72, 0x65, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x31, 0x5f,
+	0x73, 0x68, 0x69, 0x66, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x67, 0x31, 0x53,
+	0x68, 0x69, 0x66, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x67, 0x32, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x02, 0x67, 0x32, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x19, 0x0a, 0x08,
+	0x67, 0x32, 0x5f, 0x73, 0x68, 0x69, 0x66, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07,
+	0x67, 0x32, 0x53, 0x68, 0x69, 0x66, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x75, 0x72, 0x76, 0x65, 0x42, 0x27, 0x5a,
+	0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x7a, 0x68, 0x74, 0x6c,
+	0x75, 0x6f, 0x2f, 0x6c, 0x69, 0x62, 0x70, 0x6f, 0x6c, 0x79, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -105,9 +603,14 @@ func file_polycommit_proto_rawDescGZIP() []byte {
 	return file_polycommit_proto_rawDescData
 }
 
-var file_polycommit_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_polycommit_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_polycommit_proto_goTypes = []interface{}{
-	(*Pk)(nil), // 0: proto.Pk
+	(*Pk)(nil),             // 0: proto.Pk
+	(*PedPk)(nil),          // 1: proto.PedPk
+	(*VerifierKey)(nil),    // 2: proto.VerifierKey
+	(*PedVerifierKey)(nil), // 3: proto.PedVerifierKey
+	(*G1Pk)(nil),           // 4: proto.G1Pk
+	(*DegreeKey)(nil),      // 5: proto.DegreeKey
 }
 var file_polycommit_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -135,6 +638,66 @@ func file_polycommit_proto_init() {
 				return nil
 			}
 		}
+		file_polycommit_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PedPk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_polycommit_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifierKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_polycommit_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PedVerifierKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_polycommit_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*G1Pk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_polycommit_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DegreeKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -142,7 +705,7 @@ func file_polycommit_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_polycommit_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,34 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package srs
+
+// This file reads the public key with plain file reads
+// on platforms without memory mapping.
+
+import (
+	"os"
+)
+
+// Struct osFile implements a read-only file of a known size.
+type osFile struct {
+	*os.File
+	size int64
+}
+
+func openFile(path string) (file, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &osFile{File: f, size: st.Size()}, nil
+}
+
+func (f *osFile) Size() int64 {
+	return f.size
+}
@@ -0,0 +1,67 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package srs
+
+// This file maps the public key into memory on unix platforms,
+// so that only the pages of the powers in use are ever read from disk.
+
+import (
+	"errors"
+	"io"
+	"os"
+	"syscall"
+)
+
+// Struct mmapFile implements a read-only file mapped into memory.
+type mmapFile struct {
+	data []byte
+}
+
+func openFile(path string) (file, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := st.Size()
+	if size == 0 {
+		return new(mmapFile), nil
+	}
+	if int64(int(size)) != size {
+		return nil, errors.New("File is too large to map into memory")
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	return &mmapFile{data: data}, nil
+}
+
+func (m *mmapFile) ReadAt(b []byte, off int64) (int, error) {
+	if off < 0 || off > int64(len(m.data)) {
+		return 0, errors.New("Offset exceeds the file")
+	}
+	n := copy(b, m.data[off:])
+	if n < len(b) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (m *mmapFile) Size() int64 {
+	return int64(len(m.data))
+}
+
+func (m *mmapFile) Close() error {
+	if m.data == nil {
+		return nil
+	}
+	err := syscall.Munmap(m.data)
+	m.data = nil
+	return err
+}
@@ -0,0 +1,463 @@
+// Package srs implements an on-disk format for the public key of polycommit
+// that is written incrementally and read lazily by index, so that large keys
+// never have to be held in memory as a whole.
+//
+// A file starts with a header of the magic "LPCS", the version, the point
+// format, the degree n and the name of the curve, followed by n fixed-size
+// records of the powers in G1 and then n fixed-size records of the powers
+// in G2. Every integer is big-endian and every point is encoded by
+// curve.Encode. Files of version 1 have no curve name and are on bn256.
+
+package srs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/point"
+	"github.com/zhtluo/libpolycrypto/poly"
+	"github.com/zhtluo/libpolycrypto/polycommit"
+)
+
+const (
+	version = 2
+	// Size of the header of version 1, which ends with the degree.
+	headerSizeV1 = 24
+	// Size of the curve name at the end of the header, padded with zeros.
+	curveNameSize = 16
+	headerSize    = headerSizeV1 + curveNameSize
+)
+
+var (
+	magic = []byte("LPCS")
+	// Number of powers held in memory at a time by Setup, Commit and CreateWitness.
+	chunkSize = 1 << 12
+)
+
+// Interface file implements a read-only file opened by Open.
+type file interface {
+	io.ReaderAt
+	io.Closer
+	Size() int64
+}
+
+// Interface sizer implements the readers that know the size of their data,
+// such as bytes.Reader, io.SectionReader and the files opened by Open.
+type sizer interface {
+	Size() int64
+}
+
+// Return the sizes of the records in G1 and G2 of the curve c in the format f.
+func recordSizes(c curve.Curve, f point.Format) (int64, int64, error) {
+	if f != point.Uncompressed && f != point.Compressed {
+		return 0, 0, errors.New("Unknown point format")
+	}
+	g1 := c.NewG1().ScalarBaseMult(big.NewInt(1))
+	g2 := c.NewG2().ScalarBaseMult(big.NewInt(1))
+	return int64(len(curve.Encode(g1, f))), int64(len(curve.Encode(g2, f))), nil
+}
+
+// Run f on the ranges [lo, hi) splitting [0, n) among the processors,
+// returning the first error.
+func parallel(n int, f func(lo, hi int) error) error {
+	workers := runtime.NumCPU()
+	if workers > n {
+		workers = n
+	}
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			errs[w] = f(n*w/workers, n*(w+1)/workers)
+		}(w)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Struct Writer implements the incremental writing of a public key,
+// one pair of powers at a time.
+type Writer struct {
+	w      io.WriterAt
+	degree int
+	format point.Format
+	g1Size int64
+	g2Size int64
+	count  int
+}
+
+// Create a writer of a public key on the curve c of degree t with the points
+// in the format f on w, writing the header right away.
+func NewWriter(w io.WriterAt, c curve.Curve, t int, f point.Format) (*Writer, error) {
+	if t < 1 {
+		return nil, errors.New("Degree is not positive")
+	}
+	if c == nil || len(c.Name()) > curveNameSize {
+		return nil, errors.New("Curve has no name that fits the header")
+	}
+	g1Size, g2Size, err := recordSizes(c, f)
+	if err != nil {
+		return nil, err
+	}
+	h := make([]byte, headerSize)
+	copy(h, magic)
+	binary.BigEndian.PutUint32(h[4:], version)
+	binary.BigEndian.PutUint32(h[8:], uint32(f))
+	binary.BigEndian.PutUint64(h[16:], uint64(t))
+	copy(h[headerSizeV1:], c.Name())
+	if _, err = w.WriteAt(h, 0); err != nil {
+		return nil, err
+	}
+	return &Writer{w: w, degree: t, format: f, g1Size: g1Size, g2Size: g2Size}, nil
+}
+
+// Append the next powers g1 and g2 of the public key.
+func (w *Writer) Append(g1, g2 curve.Point) error {
+	if w.count >= w.degree {
+		return errors.New("Public key is already complete")
+	}
+	i := int64(w.count)
+	if _, err := w.w.WriteAt(curve.Encode(g1, w.format), headerSize+i*w.g1Size); err != nil {
+		return err
+	}
+	g2Offset := headerSize + int64(w.degree)*w.g1Size
+	if _, err := w.w.WriteAt(curve.Encode(g2, w.format), g2Offset+i*w.g2Size); err != nil {
+		return err
+	}
+	w.count++
+	return nil
+}
+
+// Check that every power of the public key has been appended.
+func (w *Writer) Finish() error {
+	if w.count != w.degree {
+		return errors.New("Public key is incomplete")
+	}
+	return nil
+}
+
+// Write the public key pk to w with the points in the format f.
+func Write(w io.WriterAt, pk *polycommit.Pk, f point.Format) error {
+	if len(pk.G1P) != len(pk.G2P) {
+		return errors.New("Public key has powers of different lengths")
+	}
+	sw, err := NewWriter(w, pk.Curve, pk.Degree(), f)
+	if err != nil {
+		return err
+	}
+	for i := range pk.G1P {
+		if err = sw.Append(pk.G1P[i], pk.G2P[i]); err != nil {
+			return err
+		}
+	}
+	return sw.Finish()
+}
+
+// Write the public key pk to the file at path with the points in the format f.
+func WriteFile(path string, pk *polycommit.Pk, f point.Format) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = Write(file, pk, f); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Create a new public key on the curve c of degree t on w as
+// polycommit.Pk.Setup does, with the randomness generated in reader r
+// and the points in the format f.
+// Only a chunk of powers is held in memory at a time.
+func Setup(w io.WriterAt, c curve.Curve, r io.Reader, t int, f point.Format) error {
+	sw, err := NewWriter(w, c, t, f)
+	if err != nil {
+		return err
+	}
+	alpha := c.NewScalar()
+	for alpha.IsZero() {
+		if _, err = alpha.SetRandom(r); err != nil {
+			return err
+		}
+	}
+	am := c.NewScalar().SetOne()
+	exps := make([]*big.Int, chunkSize)
+	g1P := make([]curve.Point, chunkSize)
+	g2P := make([]curve.Point, chunkSize)
+	for i := range g1P {
+		g1P[i], g2P[i] = c.NewG1(), c.NewG2()
+	}
+	for lo := 0; lo < t; lo += chunkSize {
+		n := t - lo
+		if n > chunkSize {
+			n = chunkSize
+		}
+		for i := 0; i < n; i++ {
+			exps[i] = am.BigInt()
+			am.Mul(am, alpha)
+		}
+		err = parallel(n, func(lo, hi int) error {
+			for i := lo; i < hi; i++ {
+				g1P[i].ScalarBaseMult(exps[i])
+				g2P[i].ScalarBaseMult(exps[i])
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if err = sw.Append(g1P[i], g2P[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return sw.Finish()
+}
+
+// Struct SRS implements a public key stored in the format of this package,
+// whose powers are decoded on demand.
+type SRS struct {
+	r      io.ReaderAt
+	closer io.Closer
+	curve  curve.Curve
+	degree int
+	format point.Format
+	offset int64
+	g1Size int64
+	g2Size int64
+}
+
+// Read the header of the public key stored in r and check that the size
+// of the data matches it. The size is compared exactly when r has a Size
+// method, and otherwise r is only checked to hold all the powers.
+// The powers are only read when requested.
+func Read(r io.ReaderAt) (*SRS, error) {
+	h := make([]byte, headerSizeV1)
+	if _, err := r.ReadAt(h, 0); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(h[:4], magic) {
+		return nil, errors.New("File is not a public key")
+	}
+	s := &SRS{r: r, format: point.Format(binary.BigEndian.Uint32(h[8:]))}
+	var err error
+	switch binary.BigEndian.Uint32(h[4:]) {
+	case 1:
+		s.curve, s.offset = curve.BN256, headerSizeV1
+	case version:
+		name := make([]byte, curveNameSize)
+		if _, err = r.ReadAt(name, headerSizeV1); err != nil {
+			return nil, err
+		}
+		if s.curve, err = curve.ByName(string(bytes.TrimRight(name, "\x00"))); err != nil {
+			return nil, err
+		}
+		s.offset = headerSize
+	default:
+		return nil, errors.New("Unknown version of the public key")
+	}
+	if s.g1Size, s.g2Size, err = recordSizes(s.curve, s.format); err != nil {
+		return nil, err
+	}
+	degree := binary.BigEndian.Uint64(h[16:])
+	if degree < 1 || degree > 1<<40 {
+		return nil, errors.New("Public key has a degree out of range")
+	}
+	s.degree = int(degree)
+	size := s.offset + int64(s.degree)*(s.g1Size+s.g2Size)
+	if sr, ok := r.(sizer); ok {
+		if sr.Size() != size {
+			return nil, errors.New("Data size does not match the public key")
+		}
+	} else if n, _ := r.ReadAt(make([]byte, 1), size-1); n != 1 {
+		return nil, errors.New("Data is shorter than the public key")
+	}
+	return s, nil
+}
+
+// Open the public key stored in the file at path, mapping it into memory
+// where the platform allows it.
+func Open(path string) (*SRS, error) {
+	r, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := Read(r)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	s.closer = r
+	return s, nil
+}
+
+// Release the file opened by Open.
+func (s *SRS) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// Return the degree of the public key.
+func (s *SRS) Degree() int {
+	return s.degree
+}
+
+// Return the curve of the public key.
+func (s *SRS) Curve() curve.Curve {
+	return s.curve
+}
+
+// Return the format of the points in the public key.
+func (s *SRS) Format() point.Format {
+	return s.format
+}
+
+func (s *SRS) checkRange(lo, hi int) error {
+	if lo < 0 || hi < lo || hi > s.degree {
+		return errors.New("Range exceeds the public key")
+	}
+	return nil
+}
+
+// Decode the powers G1P[lo:hi] in parallel.
+func (s *SRS) G1(lo, hi int) ([]curve.Point, error) {
+	if err := s.checkRange(lo, hi); err != nil {
+		return nil, err
+	}
+	ret := make([]curve.Point, hi-lo)
+	offset := s.offset + int64(lo)*s.g1Size
+	err := parallel(hi-lo, func(a, b int) error {
+		buf := make([]byte, int64(b-a)*s.g1Size)
+		if _, err := s.r.ReadAt(buf, offset+int64(a)*s.g1Size); err != nil {
+			return err
+		}
+		for i := a; i < b; i++ {
+			m := buf[int64(i-a)*s.g1Size : int64(i-a+1)*s.g1Size]
+			ret[i] = s.curve.NewG1()
+			if err := curve.Decode(ret[i], m, s.format); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Decode the powers G2P[lo:hi] in parallel.
+func (s *SRS) G2(lo, hi int) ([]curve.Point, error) {
+	if err := s.checkRange(lo, hi); err != nil {
+		return nil, err
+	}
+	ret := make([]curve.Point, hi-lo)
+	offset := s.offset + int64(s.degree)*s.g1Size + int64(lo)*s.g2Size
+	err := parallel(hi-lo, func(a, b int) error {
+		buf := make([]byte, int64(b-a)*s.g2Size)
+		if _, err := s.r.ReadAt(buf, offset+int64(a)*s.g2Size); err != nil {
+			return err
+		}
+		for i := a; i < b; i++ {
+			m := buf[int64(i-a)*s.g2Size : int64(i-a+1)*s.g2Size]
+			ret[i] = s.curve.NewG2()
+			if err := curve.Decode(ret[i], m, s.format); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Load the first degree powers as a public key.
+func (s *SRS) Pk(degree int) (*polycommit.Pk, error) {
+	if degree < 1 {
+		return nil, errors.New("Degree is not positive")
+	}
+	pk := new(polycommit.Pk)
+	pk.Curve = s.curve
+	var err error
+	if pk.G1P, err = s.G1(0, degree); err != nil {
+		return nil, err
+	}
+	if pk.G2P, err = s.G2(0, degree); err != nil {
+		return nil, err
+	}
+	return pk, nil
+}
+
+func (s *SRS) checkPoly(p []curve.Scalar) error {
+	if len(p) < 1 {
+		return errors.New("Polynomial is empty")
+	}
+	if s.degree < len(p) {
+		return errors.New("Public key has a degree less than the polynomial")
+	}
+	return nil
+}
+
+// Generate the commitment of the polynomial p as polycommit.Pk.Commit does,
+// loading only the powers needed a chunk at a time.
+func (s *SRS) Commit(p []curve.Scalar) (curve.Point, error) {
+	if err := s.checkPoly(p); err != nil {
+		return nil, err
+	}
+	ret := s.curve.NewG2()
+	for lo := 0; lo < len(p); lo += chunkSize {
+		hi := lo + chunkSize
+		if hi > len(p) {
+			hi = len(p)
+		}
+		g2P, err := s.G2(lo, hi)
+		if err != nil {
+			return nil, err
+		}
+		ret.Add(ret, polycommit.MultiExpG2(s.curve, g2P, p[lo:hi]))
+	}
+	return ret, nil
+}
+
+// Create a witness g1 to the evaluation of the polynomial p at i as
+// polycommit.Pk.CreateWitness does, loading only the powers needed
+// a chunk at a time.
+func (s *SRS) CreateWitness(p []curve.Scalar, i curve.Scalar) (res curve.Scalar, g1 curve.Point, err error) {
+	if err = s.checkPoly(p); err != nil {
+		return nil, nil, err
+	}
+	quotient, res := poly.DivLinear(p, i)
+	g1 = s.curve.NewG1()
+	for lo := 0; lo < len(quotient); lo += chunkSize {
+		hi := lo + chunkSize
+		if hi > len(quotient) {
+			hi = len(quotient)
+		}
+		g1P, err := s.G1(lo, hi)
+		if err != nil {
+			return nil, nil, err
+		}
+		g1.Add(g1, polycommit.MultiExpG1(s.curve, g1P, quotient[lo:hi]))
+	}
+	return res, g1, nil
+}
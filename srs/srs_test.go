@@ -0,0 +1,206 @@
+package srs
+
+import (
+	"testing"
+
+	"bytes"
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/zhtluo/libpolycrypto/curve"
+	"github.com/zhtluo/libpolycrypto/point"
+	"github.com/zhtluo/libpolycrypto/polycommit"
+)
+
+const (
+	deg = 64
+)
+
+var curves = []curve.Curve{curve.BN256, curve.BN256Google, curve.BLS12381}
+
+// Struct readerAt hides the Size method of the reader it wraps.
+type readerAt struct {
+	r io.ReaderAt
+}
+
+func (r readerAt) ReadAt(p []byte, off int64) (int, error) {
+	return r.r.ReadAt(p, off)
+}
+
+func generatePoly(c curve.Curve, n int) []curve.Scalar {
+	p := curve.NewScalars(c, n)
+	for i := range p {
+		p[i].SetRandom(rand.Reader)
+	}
+	return p
+}
+
+func TestWriteFile(t *testing.T) {
+	// Small chunks exercise the boundaries between them.
+	defer func(c int) { chunkSize = c }(chunkSize)
+	chunkSize = 7
+	for _, c := range curves {
+		t.Run(c.Name(), func(t *testing.T) {
+			var pk polycommit.Pk
+			pk.Setup(c, rand.Reader, deg)
+			for _, f := range []point.Format{point.Uncompressed, point.Compressed} {
+				path := filepath.Join(t.TempDir(), "srs")
+				if err := WriteFile(path, &pk, f); err != nil {
+					t.Fatal(err)
+				}
+				s, err := Open(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if s.Curve() != c || s.Degree() != deg || s.Format() != f {
+					t.Error("Open failed. Wrong header.")
+				}
+				rPk, err := s.Pk(deg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i := 0; i < deg; i++ {
+					if !bytes.Equal(pk.G1P[i].Marshal(), rPk.G1P[i].Marshal()) || !bytes.Equal(pk.G2P[i].Marshal(), rPk.G2P[i].Marshal()) {
+						t.Fatal("Pk failed. Wrong power.")
+					}
+				}
+				for _, n := range []int{1, chunkSize, 3*chunkSize + 1, deg} {
+					p := generatePoly(c, n)
+					com, err := s.Commit(p)
+					if err != nil {
+						t.Error(err)
+					}
+					rCom, _ := pk.Commit(p)
+					if !bytes.Equal(com.Marshal(), rCom.Marshal()) {
+						t.Error("Commit failed. Expected the commitment of Pk.")
+					}
+					i := curve.NewScalar(c, 5)
+					res, w, err := s.CreateWitness(p, i)
+					if err != nil {
+						t.Error(err)
+					}
+					rRes, rw, _ := pk.CreateWitness(p, i)
+					if !res.Equal(rRes) || !bytes.Equal(w.Marshal(), rw.Marshal()) {
+						t.Error("CreateWitness failed. Expected the witness of Pk.")
+					}
+				}
+				if _, err = s.Commit(generatePoly(c, deg+1)); err == nil {
+					t.Error("Commit accepted a polynomial beyond the degree.")
+				}
+				if _, err = s.G1(deg-1, deg+1); err == nil {
+					t.Error("G1 accepted a range beyond the degree.")
+				}
+				if err = s.Close(); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func TestSetup(t *testing.T) {
+	defer func(c int) { chunkSize = c }(chunkSize)
+	chunkSize = 5
+	path := filepath.Join(t.TempDir(), "srs")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = Setup(f, curve.BLS12381, rand.Reader, deg, point.Compressed); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if s.Curve() != curve.BLS12381 {
+		t.Error("Open failed. Wrong curve.")
+	}
+	pk, err := s.Pk(deg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = pk.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	var pk polycommit.Pk
+	pk.Setup(curve.BN256, rand.Reader, 4)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "srs")
+	if err := WriteFile(path, &pk, point.Uncompressed); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadFile(path)
+	truncated := filepath.Join(dir, "truncated")
+	ioutil.WriteFile(truncated, b[:len(b)-1], 0600)
+	if _, err := Open(truncated); err == nil {
+		t.Error("Open accepted a truncated file.")
+	}
+	if _, err := Read(bytes.NewReader(b[:len(b)-1])); err == nil {
+		t.Error("Read accepted truncated data.")
+	}
+	if _, err := Read(bytes.NewReader(append(b, 0))); err == nil {
+		t.Error("Read accepted trailing data.")
+	}
+	if _, err := Read(readerAt{bytes.NewReader(b[:len(b)-1])}); err == nil {
+		t.Error("Read accepted truncated data without a size.")
+	}
+	if _, err := Read(readerAt{bytes.NewReader(b)}); err != nil {
+		t.Error(err)
+	}
+	// A header of version 1 has no curve name and is read as bn256.
+	v1 := append(append([]byte{}, b[:headerSizeV1]...), b[headerSize:]...)
+	v1[7] = 1
+	s, err := Read(bytes.NewReader(v1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, err := s.G1(0, 1); err != nil || !g[0].Equal(pk.G1P[0]) || s.Curve() != curve.BN256 {
+		t.Error("Read failed to read a header of version 1.")
+	}
+	b[0] = 'X'
+	ioutil.WriteFile(path, b, 0600)
+	if _, err := Open(path); err == nil {
+		t.Error("Open accepted a file without the magic.")
+	}
+	if _, err := Open(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Open accepted a missing file.")
+	}
+	// The writer refuses to go beyond the degree or finish early.
+	f, _ := os.Create(filepath.Join(dir, "partial"))
+	defer f.Close()
+	w, err := NewWriter(f, curve.BN256, 2, point.Compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Append(pk.G1P[0], pk.G2P[0])
+	if w.Finish() == nil {
+		t.Error("Finish accepted an incomplete public key.")
+	}
+	w.Append(pk.G1P[1], pk.G2P[1])
+	if w.Append(pk.G1P[2], pk.G2P[2]) == nil {
+		t.Error("Append accepted a power beyond the degree.")
+	}
+}
+
+func BenchmarkCommit(b *testing.B) {
+	var pk polycommit.Pk
+	pk.Setup(curve.BN256, rand.Reader, deg)
+	path := filepath.Join(b.TempDir(), "srs")
+	WriteFile(path, &pk, point.Compressed)
+	s, _ := Open(path)
+	defer s.Close()
+	p := generatePoly(curve.BN256, deg)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Commit(p)
+	}
+}
@@ -0,0 +1,115 @@
+// Package transcript implements Fiat-Shamir transcripts in the style of
+// Merlin on top of sha256. Every message is appended with a label, and the
+// transcript starts with a protocol domain separator, so that challenges
+// bind the whole history of the protocol and cannot be replayed across
+// protocols or positions.
+
+package transcript
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+const (
+	// Version of the transcript construction, absorbed on creation.
+	protocolLabel = "libpolycrypto-transcript-v1"
+	// Operation tags keep appends, challenges and outputs apart.
+	tagAppend    = 'A'
+	tagChallenge = 'C'
+	tagOutput    = 'O'
+	tagRatchet   = 'R'
+	// A challenge scalar reduces 64 bytes so that its bias modulo the order
+	// of a curve of up to 256 bits is negligible.
+	ScalarBytes = 64
+)
+
+// Interface Marshaler implements the scalars and points of any curve
+// that can be appended to a transcript.
+type Marshaler interface {
+	Marshal() []byte
+}
+
+// Struct Transcript implements the running state of a Fiat-Shamir transcript,
+// i.e. a sha256 chain over every labelled operation so far.
+type Transcript struct {
+	state [sha256.Size]byte
+}
+
+// Create a transcript for the protocol with the domain separator label.
+func New(label string) *Transcript {
+	t := new(Transcript)
+	t.absorb(tagAppend, []byte(protocolLabel), nil)
+	t.AppendMessage("dom-sep", []byte(label))
+	return t
+}
+
+// Fold the operation tag, the label and the message into the state.
+// Lengths are framed so that different splits never collide.
+func (t *Transcript) absorb(tag byte, label []byte, msg []byte) {
+	h := sha256.New()
+	h.Write(t.state[:])
+	h.Write([]byte{tag})
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], uint64(len(label)))
+	h.Write(n[:])
+	h.Write(label)
+	binary.BigEndian.PutUint64(n[:], uint64(len(msg)))
+	h.Write(n[:])
+	h.Write(msg)
+	h.Sum(t.state[:0])
+}
+
+// Return an independent copy of the transcript.
+func (t *Transcript) Clone() *Transcript {
+	c := *t
+	return &c
+}
+
+// Append the byte string msg with label.
+func (t *Transcript) AppendMessage(label string, msg []byte) {
+	t.absorb(tagAppend, []byte(label), msg)
+}
+
+// Append the 64-bit integer v with label.
+func (t *Transcript) AppendUint64(label string, v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	t.AppendMessage(label, b[:])
+}
+
+// Append the scalar s with label.
+func (t *Transcript) AppendScalar(label string, s Marshaler) {
+	t.AppendMessage(label, s.Marshal())
+}
+
+// Append the point p in G1 or G2 with label.
+func (t *Transcript) AppendPoint(label string, p Marshaler) {
+	t.AppendMessage(label, p.Marshal())
+}
+
+// Derive n challenge bytes with label. The challenge is bound to the
+// transcript, so later challenges depend on it.
+func (t *Transcript) ChallengeBytes(label string, n int) []byte {
+	var size [8]byte
+	binary.BigEndian.PutUint64(size[:], uint64(n))
+	t.absorb(tagChallenge, []byte(label), size[:])
+	ret := make([]byte, 0, n+sha256.Size)
+	var counter [8]byte
+	for k := uint64(0); len(ret) < n; k++ {
+		binary.BigEndian.PutUint64(counter[:], k)
+		h := sha256.New()
+		h.Write(t.state[:])
+		h.Write([]byte{tagOutput})
+		h.Write(counter[:])
+		ret = h.Sum(ret)
+	}
+	t.absorb(tagRatchet, nil, nil)
+	return ret[:n]
+}
+
+// Derive the bytes of a challenge scalar with label, to be reduced
+// modulo the order of the curve with SetBytesReduce.
+func (t *Transcript) ChallengeScalarBytes(label string) []byte {
+	return t.ChallengeBytes(label, ScalarBytes)
+}
@@ -0,0 +1,88 @@
+package transcript
+
+import (
+	"testing"
+
+	"bytes"
+	"math/big"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/zhtluo/libpolycrypto/fr"
+)
+
+func TestChallenge(t *testing.T) {
+	s := fr.NewElement(42)
+	g1 := new(bn256.G1).ScalarBaseMult(big.NewInt(7))
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(7))
+	build := func(dom string) *Transcript {
+		tr := New(dom)
+		tr.AppendScalar("s", &s)
+		tr.AppendPoint("g1", g1)
+		tr.AppendPoint("g2", g2)
+		tr.AppendMessage("msg", []byte("hello"))
+		return tr
+	}
+	a, b := build("test"), build("test")
+	if !bytes.Equal(a.ChallengeScalarBytes("c"), b.ChallengeScalarBytes("c")) {
+		t.Error("ChallengeScalarBytes failed. Same transcripts gave different challenges.")
+	}
+	// Later challenges depend on the earlier ones.
+	if bytes.Equal(a.ChallengeScalarBytes("c"), build("test").ChallengeScalarBytes("c")) {
+		t.Error("ChallengeScalarBytes failed. Challenge did not update the transcript.")
+	}
+	if bytes.Equal(build("test").ChallengeScalarBytes("c"), build("other").ChallengeScalarBytes("c")) {
+		t.Error("ChallengeScalarBytes failed. Domain separator is ignored.")
+	}
+	if bytes.Equal(build("test").ChallengeScalarBytes("c"), build("test").ChallengeScalarBytes("d")) {
+		t.Error("ChallengeScalarBytes failed. Challenge label is ignored.")
+	}
+	c := build("test")
+	c.AppendUint64("n", 1)
+	if bytes.Equal(build("test").ChallengeScalarBytes("c"), c.ChallengeScalarBytes("c")) {
+		t.Error("ChallengeScalarBytes failed. Append is ignored.")
+	}
+	// Moving bytes between the label and the message must change the challenge.
+	x, y := New("test"), New("test")
+	x.AppendMessage("ab", []byte("c"))
+	y.AppendMessage("a", []byte("bc"))
+	if bytes.Equal(x.ChallengeBytes("c", 32), y.ChallengeBytes("c", 32)) {
+		t.Error("ChallengeBytes failed. Labels and messages are not framed.")
+	}
+}
+
+func TestClone(t *testing.T) {
+	a := New("test")
+	a.AppendMessage("msg", []byte("hello"))
+	b := a.Clone()
+	if !bytes.Equal(a.ChallengeBytes("c", 100), b.ChallengeBytes("c", 100)) {
+		t.Error("Clone failed. Expected the same challenge.")
+	}
+	a.AppendMessage("msg", []byte("a"))
+	b.AppendMessage("msg", []byte("b"))
+	if bytes.Equal(a.ChallengeBytes("c", 32), b.ChallengeBytes("c", 32)) {
+		t.Error("Clone failed. Copies are not independent.")
+	}
+}
+
+func TestChallengeBytes(t *testing.T) {
+	for _, n := range []int{0, 1, 31, 32, 33, 100} {
+		if len(New("test").ChallengeBytes("c", n)) != n {
+			t.Errorf("ChallengeBytes failed. Expected %d bytes.", n)
+		}
+	}
+	// A prefix of a longer challenge is not the shorter challenge,
+	// since the length is absorbed.
+	if bytes.Equal(New("test").ChallengeBytes("c", 32), New("test").ChallengeBytes("c", 64)[:32]) {
+		t.Error("ChallengeBytes failed. Length is not absorbed.")
+	}
+}
+
+func BenchmarkChallengeScalar(b *testing.B) {
+	tr := New("bench")
+	s := fr.NewElement(42)
+	b.ResetTimer()
+	for t := 0; t < b.N; t++ {
+		tr.AppendScalar("s", &s)
+		new(fr.Element).SetBytesReduce(tr.ChallengeScalarBytes("c"))
+	}
+}